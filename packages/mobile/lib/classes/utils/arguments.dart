@@ -1,15 +1,17 @@
 import 'package:mobile/classes/server/service.dart';
 import 'package:mobile/classes/server/service_area.dart';
 
-/// It's a class that holds the code, the authenticator name, and the redirect URL
+/// It's a class that holds the code, the state, the authenticator name, and the redirect URL
 class CodeArgument {
   final String authenticatorName;
   final String code;
+  final String state;
   final String redirectURL;
 
   const CodeArgument({
     required this.authenticatorName,
     required this.code,
+    required this.state,
     required this.redirectURL,
   });
 }
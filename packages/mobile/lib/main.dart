@@ -60,6 +60,7 @@ class MyAppState extends State<MyApp> {
         if (!mounted) return;
         final code = uri?.queryParameters["code"];
         if (code == null) return;
+        final state = uri?.queryParameters["state"] ?? '';
         final isRegistration = await Store.getRegistrationMode();
         final authenticatorName = await Store.getCurrentAuthenticator();
         if (authenticatorName == null) return;
@@ -68,12 +69,14 @@ class MyAppState extends State<MyApp> {
               arguments: CodeArgument(
                   authenticatorName: authenticatorName,
                   code: code,
+                  state: state,
                   redirectURL: Store.redirectURL));
         } else {
           navigatorKey.currentState?.pushReplacementNamed('/authorize',
               arguments: CodeArgument(
                   authenticatorName: authenticatorName,
                   code: code,
+                  state: state,
                   redirectURL: Store.redirectURL));
         }
       }, onError: (Object err) {
@@ -99,6 +102,7 @@ class MyAppState extends State<MyApp> {
         if (!mounted) return;
         final code = uri.queryParameters["code"];
         if (code == null) return;
+        final state = uri.queryParameters["state"] ?? '';
         final isRegistration = await Store.getRegistrationMode();
         final authenticatorName = await Store.getCurrentAuthenticator();
         if (authenticatorName == null) return;
@@ -107,12 +111,14 @@ class MyAppState extends State<MyApp> {
               arguments: CodeArgument(
                   authenticatorName: authenticatorName,
                   code: code,
+                  state: state,
                   redirectURL: Store.redirectURL));
         } else {
           navigatorKey.currentState?.pushReplacementNamed('/authorize',
               arguments: CodeArgument(
                   authenticatorName: authenticatorName,
                   code: code,
+                  state: state,
                   redirectURL: Store.redirectURL));
         }
         // Go to home page
@@ -130,16 +130,64 @@ class ApiClient {
     Store.setToken(body['data']['token']);
   }
 
-  /// It sends a POST request to the server with the authenticator, code, and redirectUri
+  /// It starts an OAuth flow with the server, which returns the URI of the provider (with the state
+  /// the provider will send back with the code)
+  ///
+  /// Args:
+  ///   registration (bool): true to log in with the authenticator, false to authorize a service.
+  ///   authenticator (String): The name of the authenticator you want to use.
+  ///   redirectUri (String): The redirect URI that you set in the OAuth provider.
+  ///
+  /// Returns:
+  ///   The URI of the provider
+  Future<String> startOAuthFlow(
+    bool registration,
+    String authenticator,
+    String redirectUri,
+  ) async {
+    final headers = {
+      "Access-Control-Allow-Origin": "*",
+      "Content-Type": "application/json"
+    };
+    if (!registration) {
+      final token = await Store.getToken();
+      if (token == null) {
+        showError.createToast("Token must not be null !");
+        throw Exception("Token must not be null !");
+      }
+      headers["Authorization"] = "Bearer $token";
+    }
+
+    final response = await http.post(
+        Uri.parse(await _getUrl(
+            registration ? '/auth/external/state' : '/authorization/state',
+            {})),
+        headers: headers,
+        body: jsonEncode({
+          "authenticator": authenticator,
+          "redirect_uri": redirectUri
+        }));
+    final returnBody = jsonDecode(response.body);
+    if (response.statusCode != 200) {
+      final code = response.statusCode;
+      showError.createToast("Wrong status code : $code");
+      throw Exception(returnBody['error'] ?? "Wrong status code");
+    }
+    return returnBody['data']['authorization_uri'];
+  }
+
+  /// It sends a POST request to the server with the authenticator, code, redirectUri and state
   ///
   /// Args:
   ///   authenticator (String): The name of the authenticator you want to use.
   ///   code (String): The code returned by the OAuth provider
   ///   redirectUri (String): The redirect URI that you set in the OAuth provider.
+  ///   state (String): The state returned by the OAuth provider
   Future<void> postExternalOAuth(
     String authenticator,
     String code,
     String redirectUri,
+    String state,
   ) async {
     final response = await http.post(
         Uri.parse(await _getUrl('/auth/external', {})),
@@ -150,7 +198,8 @@ class ApiClient {
         body: jsonEncode({
           "authenticator": authenticator,
           "code": code,
-          "redirect_uri": redirectUri
+          "redirect_uri": redirectUri,
+          "state": state
         }));
     final returnBody = jsonDecode(response.body);
     if (!(response.statusCode == 201 || response.statusCode == 200)) {
@@ -196,6 +245,7 @@ class ApiClient {
   ///   authenticator (String): The name of the authenticator you want to use.
   ///   code (String): The code you received from the authenticator.
   ///   redirectUri (String): The redirect URI that you specified when you created the authorization.
+  ///   state (String): The state returned by the authenticator.
   ///
   /// Returns:
   ///   A string
@@ -203,6 +253,7 @@ class ApiClient {
     String authenticator,
     String code,
     String redirectUri,
+    String state,
   ) async {
     final token = await Store.getToken();
     if (token == null) {
@@ -220,7 +271,8 @@ class ApiClient {
         body: jsonEncode({
           "authenticator": authenticator,
           "code": code,
-          "redirect_uri": redirectUri
+          "redirect_uri": redirectUri,
+          "state": state
         }));
     final returnBody = jsonDecode(response.body);
     if (response.statusCode != 201) {
@@ -1,15 +1,18 @@
 import 'package:flutter/material.dart';
 import 'package:mobile/classes/server/authenticator.dart';
+import 'package:mobile/net/api.dart';
 import 'package:flutter_custom_tabs/flutter_custom_tabs.dart';
 import 'package:mobile/store/store.dart';
 
-/// It opens a custom tab with the authorization URL of the authenticator
+/// It starts an OAuth flow with the server and opens a custom tab with the authorization URL it returns
 class OAuthLib {
   static void openAuthentifier(bool mode, Authenticator authenticator) async {
+    final uri =
+        await api.startOAuthFlow(mode, authenticator.name, Store.redirectURL);
     Store.setRegistrationMode(mode);
     Store.setCurrentAuthenticator(authenticator.name);
     await launch(
-      '${authenticator.authorizationUri}&redirect_uri=${Store.redirectURL}',
+      uri,
       customTabsOption: CustomTabsOption(
         toolbarColor: const Color(0xFF1E1E1E),
         enableDefaultShare: true,
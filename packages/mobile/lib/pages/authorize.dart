@@ -20,7 +20,8 @@ class AuthorizePageState extends ConsumerState<AuthorizePage> {
       (ref, argument) async {
     if (argument != null) {
       await api.createAuthorization(
-          argument.authenticatorName, argument.code, argument.redirectURL);
+          argument.authenticatorName, argument.code,
+          argument.redirectURL, argument.state);
       Store.setCurrentAuthenticator('');
     }
     final about = await api.getAbout();
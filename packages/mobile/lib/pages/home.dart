@@ -11,7 +11,8 @@ class HomePage extends ConsumerWidget {
       FutureProvider.family<void, CodeArgument?>((ref, argument) async {
     if (argument != null) {
       await api.postExternalOAuth(
-          argument.authenticatorName, argument.code, argument.redirectURL);
+          argument.authenticatorName, argument.code,
+          argument.redirectURL, argument.state);
       Store.setCurrentAuthenticator('');
     }
   });
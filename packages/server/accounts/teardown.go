@@ -0,0 +1,196 @@
+package accounts
+
+import (
+	"area-server/authenticators"
+	"area-server/config"
+	"area-server/db/postgres"
+	"area-server/db/postgres/models"
+	"area-server/store"
+	"area-server/store/webhooks"
+	"area-server/utils"
+	"context"
+	"log"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Only one teardown runs at a time (the triggers are not safe for concurrent use)
+var running sync.Mutex
+
+// ScheduleTeardown disables the account and records the deletion of its applets, the deletion is then
+// done in the background (see `StartTeardownWorker`). Scheduling an account twice does nothing.
+func ScheduleTeardown(account models.Account) error {
+	err := postgres.DB.Transaction(func(tx *gorm.DB) error {
+		var applets []string
+		if result := tx.Model(&models.Applet{}).Where("account_uuid = ?", account.UUID).Pluck("uuid", &applets); result.Error != nil {
+			return result.Error
+		}
+
+		teardown := models.AccountTeardown{
+			AccountUUID: account.UUID,
+			Email:       account.Email,
+			Applets:     applets,
+			Step:        models.TeardownTriggers,
+			NextRunAt:   time.Now(),
+		}
+		if result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&teardown); result.Error != nil {
+			return result.Error
+		}
+
+		// The middlewares reject the account until it is deleted
+		return tx.Model(&models.Account{}).Where("uuid = ?", account.UUID).Update("disabled", true).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	go runTeardown(account.UUID)
+	return nil
+}
+
+// IsTearingDown returns true if the deletion of the account is scheduled (the account must stay disabled)
+func IsTearingDown(accountUUID uuid.UUID) (bool, error) {
+	var count int64
+	if result := postgres.DB.Model(&models.AccountTeardown{}).Where("account_uuid = ?", accountUUID).Count(&count); result.Error != nil {
+		return false, result.Error
+	}
+	return count > 0, nil
+}
+
+// It stops and unregisters the triggers of the applets of the account with their webhooks, the
+// applets created since the teardown was scheduled are added to it
+func stopTriggers(teardown *models.AccountTeardown) error {
+	var applets []string
+	if result := postgres.DB.Model(&models.Applet{}).Where("account_uuid = ?", teardown.AccountUUID).Pluck("uuid", &applets); result.Error != nil {
+		return result.Error
+	}
+	known := make(map[string]bool, len(teardown.Applets))
+	for _, applet := range teardown.Applets {
+		known[applet] = true
+	}
+	for _, applet := range applets {
+		if !known[applet] {
+			teardown.Applets = append(teardown.Applets, applet)
+		}
+	}
+
+	for _, applet := range teardown.Applets {
+		if id, err := uuid.Parse(applet); err == nil && store.GetTrigger(id) != nil {
+			store.StopTrigger(id)
+			store.RemoveTrigger(id)
+		}
+		webhooks.RemoveWebhook(applet)
+	}
+	return nil
+}
+
+// It revokes the tokens of the authorizations at the providers (a provider refusing it doesn't stop
+// the teardown), then the sessions and the tokens of the account
+func revokeTokens(teardown *models.AccountTeardown) error {
+	var authorizations []models.Authorization
+	if result := postgres.DB.Where(&models.Authorization{AccountUUID: teardown.AccountUUID}).Find(&authorizations); result.Error != nil {
+		return result.Error
+	}
+
+	for _, authorization := range authorizations {
+		authenticator := authenticators.GetAuthenticator(authorization.AuthService)
+		if authenticator == nil || authenticator.AuthEndpoints.RevokeToken == nil {
+			continue
+		}
+		if _, _, err := authenticator.AuthEndpoints.RevokeToken.CallEncode([]interface{}{authorization}); err != nil {
+			log.Printf("[Teardown] Could not revoke the token of %s (%s): %s", authorization.UUID, authorization.AuthService, err.Error())
+		}
+	}
+
+	return store.RevokeAccount(teardown.AccountUUID)
+}
+
+// It deletes the account, with everything linked to it
+func deleteAccount(teardown *models.AccountTeardown) error {
+	return postgres.DB.Where("uuid = ?", teardown.AccountUUID).Delete(&models.Account{}).Error
+}
+
+// It deletes the logs of the applets and the avatar, or archives them (`config.CFG.Teardown.Archive`)
+func removeFiles(teardown *models.AccountTeardown) error {
+	archive := ""
+	if config.CFG.Teardown.Archive != "" {
+		archive = filepath.Join(config.CFG.Teardown.Archive, teardown.AccountUUID.String())
+	}
+	return utils.RemoveFiles(utils.AccountFiles(teardown.AccountUUID.String(), teardown.Applets), archive)
+}
+
+// The steps of a teardown, in order
+var steps = []utils.TeardownStep{
+	{Name: models.TeardownTriggers, Run: stopTriggers},
+	{Name: models.TeardownTokens, Run: revokeTokens},
+	{Name: models.TeardownAccount, Run: deleteAccount},
+	{Name: models.TeardownFiles, Run: removeFiles},
+}
+
+// It saves the progress of the teardown
+func saveTeardown(teardown *models.AccountTeardown) error {
+	return postgres.DB.Model(teardown).Select("applets", "step", "attempts", "last_error", "next_run_at").Updates(teardown).Error
+}
+
+// It runs the teardown of the account from the step where it stopped, a step that fails is tried again
+// later by the worker
+func runTeardown(accountUUID uuid.UUID) {
+	running.Lock()
+	defer running.Unlock()
+
+	var teardown models.AccountTeardown
+	if result := postgres.DB.Where(&models.AccountTeardown{AccountUUID: accountUUID}).First(&teardown); result.Error != nil {
+		if result.Error != gorm.ErrRecordNotFound {
+			log.Printf("[Teardown] Could not find the teardown of %s: %s", accountUUID, result.Error.Error())
+		}
+		return
+	}
+
+	if done, err := utils.RunTeardownSteps(&teardown, steps, saveTeardown, time.Now); !done {
+		log.Printf("[Teardown] Teardown of %s stopped (attempt %d): %s", accountUUID, teardown.Attempts, err.Error())
+		return
+	}
+
+	if result := postgres.DB.Delete(&teardown); result.Error != nil {
+		log.Printf("[Teardown] Could not delete the teardown of %s: %s", accountUUID, result.Error.Error())
+		return
+	}
+	log.Printf("[Teardown] Account %s deleted", accountUUID)
+}
+
+// It runs the teardowns that are due
+func resumeTeardowns() {
+	var pending []uuid.UUID
+	if result := postgres.DB.Model(&models.AccountTeardown{}).Where("next_run_at <= ?", time.Now()).Order("created_at").Pluck("account_uuid", &pending); result.Error != nil {
+		log.Printf("[Teardown] Could not list the teardowns: %s", result.Error.Error())
+		return
+	}
+	for _, accountUUID := range pending {
+		runTeardown(accountUUID)
+	}
+}
+
+// StartTeardownWorker resumes in the background the deletions of accounts that were interrupted (by a
+// failure or a restart of the server), until the context is done
+func StartTeardownWorker(ctx context.Context) {
+	interval := time.Duration(config.CFG.Teardown.Interval) * time.Second
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		resumeTeardowns()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package middlewares
+
+import (
+	"area-server/db/postgres/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AdminMiddleware refuses the accounts that are not administrators, it must be used after the session
+// or token middleware (the API keys are not accepted on the administration routes)
+func AdminMiddleware(c *fiber.Ctx) error {
+	account, ok := c.Locals("account").(models.Account)
+	if !ok || !account.IsAdmin() {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"code":  fiber.StatusForbidden,
+			"error": "Administrator role required",
+		})
+	}
+	return c.Next()
+}
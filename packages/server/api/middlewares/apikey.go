@@ -0,0 +1,110 @@
+package middlewares
+
+import (
+	"area-server/db/postgres"
+	"area-server/db/postgres/models"
+	"area-server/utils"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Minimum time between two updates of the last use of a key
+const apiKeyLastUsedInterval = time.Minute
+
+// It returns the API key sent with the request (`Authorization: ApiKey <key>`), empty if none
+func apiKeyFromRequest(c *fiber.Ctx) string {
+	header := c.Get("Authorization")
+	if len(header) > 7 && strings.EqualFold(header[:7], "ApiKey ") {
+		return strings.TrimSpace(header[7:])
+	}
+	return ""
+}
+
+// ReadWriteScope returns the scope of the request: `<resource>:read` for GET requests and
+// `<resource>:write` for the others
+func ReadWriteScope(resource string) func(*fiber.Ctx) string {
+	return func(c *fiber.Ctx) string {
+		if c.Method() == fiber.MethodGet || c.Method() == fiber.MethodHead {
+			return resource + ":read"
+		}
+		return resource + ":write"
+	}
+}
+
+// Scope returns the same scope for all the requests
+func Scope(scope string) func(*fiber.Ctx) string {
+	return func(*fiber.Ctx) string {
+		return scope
+	}
+}
+
+// APIKeyMiddleware authenticates the requests sent with an API key having the scope of the request,
+// the other requests are authenticated by `next` (the session or token middleware). A nil scope
+// refuses the API keys.
+func APIKeyMiddleware(next fiber.Handler, scope func(*fiber.Ctx) string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := apiKeyFromRequest(c)
+		if key == "" {
+			return next(c)
+		}
+
+		required := ""
+		if scope != nil {
+			required = scope(c)
+		}
+		if required == "" {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"code":  fiber.StatusForbidden,
+				"error": "API keys are not accepted on this route",
+			})
+		}
+
+		var apiKey models.APIKey
+		if !utils.IsAPIKey(key) || postgres.DB.Where(&models.APIKey{Hash: utils.HashAPIKey(key)}).First(&apiKey).Error != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"code":  fiber.StatusUnauthorized,
+				"error": "Invalid API key",
+			})
+		}
+
+		now := time.Now()
+		if apiKey.Expired(now) {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"code":  fiber.StatusUnauthorized,
+				"error": "API key expired",
+			})
+		}
+
+		if !apiKey.HasScope(required) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"code":  fiber.StatusForbidden,
+				"error": "API key is missing the scope " + required,
+			})
+		}
+
+		var account models.Account
+		if result := postgres.DB.Where(&models.Account{UUID: apiKey.AccountUUID}).First(&account); result.Error != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"code":  fiber.StatusUnauthorized,
+				"error": "API key is not linked to an user",
+			})
+		}
+
+		if account.Disabled {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"code":  fiber.StatusForbidden,
+				"error": "Account disabled",
+			})
+		}
+
+		if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) > apiKeyLastUsedInterval {
+			postgres.DB.Model(&apiKey).Update("last_used_at", now)
+		}
+
+		c.Locals("account", account)
+		c.Locals("api_key", apiKey)
+		return c.Next()
+	}
+}
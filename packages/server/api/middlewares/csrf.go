@@ -0,0 +1,51 @@
+package middlewares
+
+import (
+	"area-server/config"
+	session "area-server/store"
+
+	"github.com/gofiber/fiber/v2"
+	fsession "github.com/gofiber/fiber/v2/middleware/session"
+)
+
+// It returns true if the request can't change anything (RFC 7231 safe methods)
+func isSafeMethod(c *fiber.Ctx) bool {
+	switch c.Method() {
+	case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions, fiber.MethodTrace:
+		return true
+	}
+	return false
+}
+
+// It checks the CSRF token of a mutating request (the `X-CSRF-Token` header must be the token of the
+// session), it returns false if a response was sent
+func verifyCSRF(c *fiber.Ctx, sess *fsession.Session) (bool, error) {
+	if isSafeMethod(c) || session.CheckCSRF(sess, c.Get(session.CSRFHeader)) {
+		return true, nil
+	}
+	return false, c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+		"code":  fiber.StatusForbidden,
+		"error": "Invalid CSRF token",
+	})
+}
+
+// CSRFMiddleware protects the routes that are not authenticated by the session (login, register...) in
+// session mode, the client retrieves the token of its session with `/auth/csrf` first
+func CSRFMiddleware(c *fiber.Ctx) error {
+	if config.CFG.Mode != config.Session || isSafeMethod(c) {
+		return c.Next()
+	}
+
+	sess, serr := session.SessionStore.Get(c)
+	if serr != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":  fiber.StatusBadRequest,
+			"error": "Invalid session",
+		})
+	}
+
+	if ok, err := verifyCSRF(c, sess); !ok {
+		return err
+	}
+	return c.Next()
+}
@@ -10,7 +10,8 @@ import (
 )
 
 // It retrieves the session from the session store, checks if the account is linked to the session, and
-// if it is, it retrieves the account from the database and stores it in the context
+// if it is, it retrieves the account from the database and stores it in the context. The mutating
+// requests must carry the CSRF token of the session.
 func SessionMiddleware(c *fiber.Ctx) error {
 
 	sess, serr := session.SessionStore.Get(c)
@@ -37,6 +38,21 @@ func SessionMiddleware(c *fiber.Ctx) error {
 		})
 	}
 
+	// The account logged out everywhere after the creation of the session
+	issuedAt, _ := sess.Get("issued_at").(int64)
+	if revoked, err := session.RevokedSince(accountUUID.String(), issuedAt); err != nil || revoked {
+		session.DestroySession(sess)
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"code":  fiber.StatusUnauthorized,
+			"error": "Session revoked",
+		})
+	}
+
+	// The cookie is sent by the browser with the requests of any site
+	if ok, err := verifyCSRF(c, sess); !ok {
+		return err
+	}
+
 	// Retrieve account from database
 	var Account models.Account
 
@@ -47,6 +63,13 @@ func SessionMiddleware(c *fiber.Ctx) error {
 		})
 	}
 
+	if Account.Disabled {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"code":  fiber.StatusForbidden,
+			"error": "Account disabled",
+		})
+	}
+
 	c.Locals("account", Account)
 	c.Locals("session", sess)
 	return c.Next()
@@ -3,24 +3,20 @@ package middlewares
 import (
 	"area-server/db/postgres"
 	"area-server/db/postgres/models"
-	"area-server/utils"
+	"area-server/store"
 	"errors"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-// It takes a token, validates it, and returns the claims if it's valid
+// It takes a token, validates it, and returns the claims if it's a valid access token that has not
+// been revoked
 func ValidateToken(token string) (map[string]interface{}, error) {
 	if len(token) < 7 || token[:6] != "Bearer" {
 		return nil, errors.New("Invalid token format")
 	}
 
-	claims, err := utils.ValidateJWT(token[7:])
-	if err != nil || claims["accountID"] == nil {
-		return nil, errors.New("Invalid token")
-	}
-
-	return claims, nil
+	return store.ParseToken(token[7:], store.AccessToken)
 }
 
 // It gets the token from the header, validates it, and gets the user from the token
@@ -58,6 +54,14 @@ func TokenMiddleware(c *fiber.Ctx) error {
 		})
 	}
 
+	if user.Disabled {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"code":  fiber.StatusForbidden,
+			"error": "Account disabled",
+		})
+	}
+
 	c.Locals("account", user)
+	c.Locals("claims", claims)
 	return c.Next()
 }
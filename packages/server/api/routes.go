@@ -2,6 +2,7 @@ package routes
 
 import (
 	"area-server/api/middlewares"
+	adminr "area-server/api/routes/admin"
 	appletr "area-server/api/routes/applet"
 	appletcontextr "area-server/api/routes/applet/context"
 	appletnewr "area-server/api/routes/applet/new"
@@ -26,7 +27,6 @@ func Init(app *fiber.App) {
 
 	// Routes
 
-	// TODO: Admin routes (Admin Dashboard)
 	app.Get("/", func(c *fiber.Ctx) error {
 		return c.Status(200).SendString("Area Server - v0.1.0")
 	})
@@ -39,7 +39,7 @@ func Init(app *fiber.App) {
 			},
 			"server": fiber.Map{
 				"current_time":   time.Now(),
-				"authenticators": authenticators.List,
+				"authenticators": authenticators.List(),
 				"services":       services.List,
 			},
 		})
@@ -55,29 +55,73 @@ func Init(app *fiber.App) {
 	app.Static("/assets", "./assets")
 	app.Static("/avatars", "./avatars")
 
-	// Auth
-	auth := app.Group("/auth")
+	// Auth (the mutating requests need the CSRF token of the session in session mode)
+	auth := app.Group("/auth", middlewares.CSRFMiddleware)
+	auth.Get("/csrf", authr.GetCSRFToken)
 	auth.Post("/login", authr.Login)
+	auth.Post("/login/2fa", authr.LoginSecondFactor)
 	auth.Post("/register", authr.Register)
 	auth.Post("/external", authr.ExternalAuth)
+	auth.Post("/external/state", authr.StartExternalAuth)
+	auth.Post("/refresh", authr.Refresh)
+	auth.Post("/verify-email", authr.VerifyEmail)
+	auth.Post("/password/forgot", authr.ForgotPassword)
+	auth.Post("/password/reset", authr.ResetPassword)
 
 	// Connected User
 	user := app.Group("/me", cmiddleware)
 	user.Get("/", userr.GetUser)
 	user.Post("/logout", userr.LogoutUser)
+	user.Post("/logout/all", userr.LogoutUserEverywhere)
+	user.Post("/verify-email", userr.SendVerificationEmail)
 	user.Put("/", userr.UpdateUser)
 	user.Delete("/", userr.DeleteUser)
 
+	// API keys (managed with a session or a token only)
+	keys := user.Group("/keys")
+	keys.Get("/", userr.GetAPIKeys)
+	keys.Post("/", userr.CreateAPIKey)
+	keys.Delete("/:key_id", userr.DeleteAPIKey)
+
+	// Two-factor authentication (local accounts)
+	twoFactor := user.Group("/2fa")
+	twoFactor.Get("/", userr.GetTwoFactor)
+	twoFactor.Post("/", userr.EnrollTwoFactor)
+	twoFactor.Post("/verify", userr.ConfirmTwoFactor)
+	twoFactor.Post("/recovery-codes", userr.RegenerateRecoveryCodes)
+	twoFactor.Delete("/", userr.DisableTwoFactor)
+
+	// Linked logins (external authenticators)
+	identities := user.Group("/identities")
+	identities.Get("/", userr.GetIdentities)
+	identities.Post("/", userr.LinkIdentity)
+	identities.Post("/state", userr.StartIdentityLink)
+	identities.Delete("/:identity_id", userr.UnlinkIdentity)
+
 	// Avatar
 	avatar := user.Group("/avatar")
 	avatar.Get("/", userr.GetAvatar)
 	avatar.Put("/", userr.UpdateAvatar)
 
+	// Administration (accounts with the admin role, no API keys)
+	admin := app.Group("/admin", cmiddleware, middlewares.AdminMiddleware)
+	admin.Get("/accounts", adminr.GetAccounts)
+	admin.Get("/accounts/:account_id", adminr.GetAccount)
+	admin.Patch("/accounts/:account_id", adminr.UpdateAccount)
+	admin.Delete("/accounts/:account_id", adminr.DeleteAccount)
+	admin.Post("/accounts/:account_id/merge", adminr.MergeAccount)
+	admin.Get("/teardowns", adminr.GetTeardowns)
+	admin.Get("/applets", adminr.GetApplets)
+	admin.Put("/applets/:applet_id/stop", adminr.StopApplet)
+	admin.Delete("/applets/:applet_id", adminr.DeleteApplet)
+	admin.Get("/triggers", adminr.GetTriggers)
+	admin.Get("/services/errors", adminr.GetServiceErrors)
+
 	store := app.Group("/store") // List all public applets
 	store.Get("/", storer.GetStoreApplets)
 
 	// Applet (Area)
-	applet := app.Group("/applet", cmiddleware)
+	applet := app.Group("/applet", middlewares.APIKeyMiddleware(cmiddleware, middlewares.ReadWriteScope("applets")))
 
 	appletnew := applet.Group("/new")
 	appletnew.Get("/", appletnewr.GetNewApplet)                                             // Default public=false
@@ -104,6 +148,7 @@ func Init(app *fiber.App) {
 	authorization := app.Group("/authorization", cmiddleware)
 	authorization.Get("/", authorizationr.GetAuthorizations)
 	authorization.Post("/", authorizationr.CreateAuthorization)
+	authorization.Post("/state", authorizationr.StartAuthorization)
 	authorization.Delete("/:name", authorizationr.DeleteAuthorization)
 
 	authorization.Get("/services", authorizationr.GetAuthorizedServices)
@@ -119,17 +164,22 @@ func Init(app *fiber.App) {
 
 	serviceRoutes := servicesL.Group("/:service")
 	serviceRoutes.Get("/", servicesr.GetService)
+	serviceRoutes.Get("/schema", servicesr.GetServiceSchema)
 	serviceRoutes.Get("/actions", servicesr.GetServiceActions)
 	serviceRoutes.Get("/actions/:action", servicesr.GetServiceAction)
+	serviceRoutes.Get("/actions/:action/schema", servicesr.GetServiceActionSchema)
 	serviceRoutes.Get("/reactions", servicesr.GetServiceReactions)
 	serviceRoutes.Get("/reactions/:reaction", servicesr.GetServiceReaction)
+	serviceRoutes.Get("/reactions/:reaction/schema", servicesr.GetServiceReactionSchema)
 	serviceRoutes.Get("/api", servicesr.GetApiEndpoints)
+	serviceRoutes.Get("/options/:area/:field", cmiddleware, servicesr.GetAreaFieldOptions)
 
 	for _, service := range services.List {
 		serviceR2 := servicesL.Group("/" + service.Name + "/api")
 		for _, route := range service.Routes {
 			if route.NeedAuth == true {
-				serviceR2.Add(route.Method, route.Endpoint, cmiddleware, route.Handler)
+				// The API keys are accepted if the route has a scope
+				serviceR2.Add(route.Method, route.Endpoint, middlewares.APIKeyMiddleware(cmiddleware, middlewares.Scope(route.Scope)), route.Handler)
 			} else {
 				serviceR2.Add(route.Method, route.Endpoint, route.Handler)
 			}
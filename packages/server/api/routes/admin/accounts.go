@@ -0,0 +1,336 @@
+package admin
+
+import (
+	"area-server/accounts"
+	"area-server/db/postgres"
+	"area-server/db/postgres/models"
+	"area-server/store"
+	"area-server/utils"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// `UpdateAccountBody` is the body of the request updating an account, the missing fields are not changed.
+// @property {string} Role - The new role (`user` or `admin`).
+// @property {bool} Disabled - If true, the account is disabled: its applets are stopped and its sessions
+// and tokens revoked.
+type UpdateAccountBody struct {
+	Role     *string `json:"role" validate:"omitempty,oneof=user admin"`
+	Disabled *bool   `json:"disabled"`
+}
+
+// `MergeAccountBody` is the body of the request merging an account into another one.
+// @property {string} Into - The UUID of the account receiving the applets, authorizations and logins.
+type MergeAccountBody struct {
+	Into string `json:"into" validate:"required,uuid"`
+}
+
+// It returns the account of the `account_id` parameter, or sends an error
+func findAccount(c *fiber.Ctx) (*models.Account, error) {
+	id, err := uuid.Parse(c.Params("account_id"))
+	if err != nil {
+		return nil, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":  fiber.StatusBadRequest,
+			"error": "Invalid account ID",
+		})
+	}
+
+	var account models.Account
+	if result := postgres.DB.Where(&models.Account{UUID: id}).First(&account); result.Error == gorm.ErrRecordNotFound {
+		return nil, c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"code":  fiber.StatusNotFound,
+			"error": "Account not found",
+		})
+	} else if result.Error != nil {
+		return nil, c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":  fiber.StatusInternalServerError,
+			"error": "Internal server error",
+		})
+	}
+	return &account, nil
+}
+
+// It stops the running applets of the account
+func stopAccountApplets(accountUUID uuid.UUID) error {
+	var applets []models.Applet
+	if result := postgres.DB.Where(&models.Applet{AccountUUID: accountUUID, Status: "running"}).Find(&applets); result.Error != nil {
+		return result.Error
+	}
+	for _, applet := range applets {
+		store.StopTrigger(applet.UUID)
+	}
+	return nil
+}
+
+// It lists the accounts (paginated with `limit` and `offset`), `q` filters them by email or username
+func GetAccounts(c *fiber.Ctx) error {
+	limit, offset := page(c)
+
+	query := postgres.DB.Model(&models.Account{})
+	if q := c.Query("q"); q != "" {
+		query = query.Where("email ILIKE ? OR username ILIKE ?", "%"+q+"%", "%"+q+"%")
+	}
+
+	var total int64
+	if result := query.Count(&total); result.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":  fiber.StatusInternalServerError,
+			"error": "Internal server error",
+		})
+	}
+
+	accounts := make([]models.Account, 0)
+	if result := query.Order("created_at").Limit(limit).Offset(offset).Find(&accounts); result.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":  fiber.StatusInternalServerError,
+			"error": "Internal server error",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"code": fiber.StatusOK,
+		"data": fiber.Map{
+			"accounts": accounts,
+			"total":    total,
+			"limit":    limit,
+			"offset":   offset,
+		},
+	})
+}
+
+// It returns an account with its applets, its authorizations and its linked logins
+func GetAccount(c *fiber.Ctx) error {
+	account, err := findAccount(c)
+	if account == nil {
+		return err
+	}
+
+	applets := make([]models.Applet, 0)
+	authorizations := make([]models.Authorization, 0)
+	identities := make([]models.Identity, 0)
+	if postgres.DB.Where(&models.Applet{AccountUUID: account.UUID}).Find(&applets).Error != nil ||
+		postgres.DB.Where(&models.Authorization{AccountUUID: account.UUID}).Find(&authorizations).Error != nil ||
+		postgres.DB.Where(&models.Identity{AccountUUID: account.UUID}).Find(&identities).Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":  fiber.StatusInternalServerError,
+			"error": "Internal server error",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"code": fiber.StatusOK,
+		"data": fiber.Map{
+			"account":        account,
+			"authenticator":  account.Authenticator,
+			"applets":        appletsWithStatus(applets),
+			"authorizations": authorizations,
+			"identities":     identities,
+		},
+	})
+}
+
+// It changes the role of an account or disables it, an administrator can't change their own account
+func UpdateAccount(c *fiber.Ctx) error {
+	admin := c.Locals("account").(models.Account)
+
+	validate := validator.New()
+	body := new(UpdateAccountBody)
+
+	if err := c.BodyParser(body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":  fiber.StatusBadRequest,
+			"error": "Bad Request (Wrong Body)",
+		})
+	}
+
+	if err := validate.Struct(body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":  fiber.StatusBadRequest,
+			"error": "Bad Request (Invalid Body)",
+		})
+	}
+
+	account, err := findAccount(c)
+	if account == nil {
+		return err
+	}
+
+	if account.UUID == admin.UUID {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"code":  fiber.StatusForbidden,
+			"error": "Cannot change your own account",
+		})
+	}
+
+	// The account is locked out until its deletion is done
+	if tearingDown, err := accounts.IsTearingDown(account.UUID); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":  fiber.StatusInternalServerError,
+			"error": "Internal server error",
+		})
+	} else if tearingDown {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"code":  fiber.StatusConflict,
+			"error": "The account is being deleted",
+		})
+	}
+
+	disable := body.Disabled != nil && *body.Disabled && !account.Disabled
+	if body.Role != nil {
+		account.Role = *body.Role
+	}
+	if body.Disabled != nil {
+		account.Disabled = *body.Disabled
+	}
+
+	if result := postgres.DB.Model(account).Select("role", "disabled").Updates(account); result.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":  fiber.StatusInternalServerError,
+			"error": "Internal server error",
+		})
+	}
+
+	// The applets stop and the user is logged out everywhere
+	if disable {
+		if err := stopAccountApplets(account.UUID); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"code":  fiber.StatusInternalServerError,
+				"error": "Internal server error",
+			})
+		}
+		if err := store.RevokeAccount(account.UUID); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"code":  fiber.StatusInternalServerError,
+				"error": "Internal server error (Store)",
+			})
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"code": fiber.StatusOK,
+		"data": fiber.Map{
+			"message": "Account updated",
+			"account": account,
+		},
+	})
+}
+
+// It deletes an account on behalf of its owner, the applets are stopped, the tokens revoked at the
+// providers and the files deleted in the background (see `GetTeardowns`)
+func DeleteAccount(c *fiber.Ctx) error {
+	admin := c.Locals("account").(models.Account)
+
+	account, err := findAccount(c)
+	if account == nil {
+		return err
+	}
+
+	if account.UUID == admin.UUID {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"code":  fiber.StatusForbidden,
+			"error": "Cannot delete your own account",
+		})
+	}
+
+	if err := accounts.ScheduleTeardown(*account); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":  fiber.StatusInternalServerError,
+			"error": "Internal server error",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"code": fiber.StatusOK,
+		"data": fiber.Map{
+			"message": "Account deleted",
+		},
+	})
+}
+
+// It lists the deletions of accounts that are not finished (paginated with `limit` and `offset`), with
+// the step where they stopped and its last error
+func GetTeardowns(c *fiber.Ctx) error {
+	limit, offset := page(c)
+
+	var total int64
+	if result := postgres.DB.Model(&models.AccountTeardown{}).Count(&total); result.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":  fiber.StatusInternalServerError,
+			"error": "Internal server error",
+		})
+	}
+
+	teardowns := make([]models.AccountTeardown, 0)
+	if result := postgres.DB.Order("created_at").Limit(limit).Offset(offset).Find(&teardowns); result.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":  fiber.StatusInternalServerError,
+			"error": "Internal server error",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"code": fiber.StatusOK,
+		"data": fiber.Map{
+			"teardowns": teardowns,
+			"total":     total,
+			"limit":     limit,
+			"offset":    offset,
+		},
+	})
+}
+
+// It merges the account into another one: the applets (with their areas), the authorizations, the API
+// keys and the logins are moved, then the account is deleted
+func MergeAccount(c *fiber.Ctx) error {
+	validate := validator.New()
+	body := new(MergeAccountBody)
+
+	if err := c.BodyParser(body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":  fiber.StatusBadRequest,
+			"error": "Bad Request (Wrong Body)",
+		})
+	}
+
+	if err := validate.Struct(body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":  fiber.StatusBadRequest,
+			"error": "Bad Request (Invalid Body)",
+		})
+	}
+
+	account, err := findAccount(c)
+	if account == nil {
+		return err
+	}
+
+	target := uuid.MustParse(body.Into)
+	if err := utils.MergeAccounts(account.UUID, target); err == utils.ErrMergeSameAccount {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":  fiber.StatusBadRequest,
+			"error": err.Error(),
+		})
+	} else if err == gorm.ErrRecordNotFound {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"code":  fiber.StatusNotFound,
+			"error": "Target account not found",
+		})
+	} else if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":  fiber.StatusInternalServerError,
+			"error": "Internal server error",
+		})
+	}
+	store.RevokeAccount(account.UUID)
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"code": fiber.StatusOK,
+		"data": fiber.Map{
+			"message": "Account merged",
+			"into":    target,
+		},
+	})
+}
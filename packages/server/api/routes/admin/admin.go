@@ -0,0 +1,64 @@
+package admin
+
+import (
+	"area-server/store"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// NEED AUTHENTICATION (ADMIN)
+
+// Size of a page of the lists (`limit` query parameter)
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+)
+
+// `TriggerStatus` is the live state of the trigger of an applet (in `store.Triggers`).
+// @property {bool} Loaded - If true, the trigger of the applet is loaded by the server.
+// @property {bool} Stopped - If true, the trigger was stopped since it was loaded.
+// @property {bool} Active - If false, the trigger is paused (the action is not checked).
+// @property {string} Emitter - The action of the trigger (<service>:<name>).
+// @property {[]string} Receivers - The reactions of the trigger (<service>:<name>).
+type TriggerStatus struct {
+	Loaded    bool     `json:"loaded"`
+	Stopped   bool     `json:"stopped"`
+	Active    bool     `json:"active"`
+	Emitter   string   `json:"emitter,omitempty"`
+	Receivers []string `json:"receivers,omitempty"`
+}
+
+// It returns the live state of the trigger of the applet
+func triggerStatus(appletID uuid.UUID) TriggerStatus {
+	trigger := store.GetTrigger(appletID)
+	if trigger == nil {
+		return TriggerStatus{}
+	}
+
+	status := TriggerStatus{
+		Loaded:  true,
+		Stopped: trigger.Stopped,
+		Active:  trigger.Active,
+	}
+	if trigger.EmitterArea != nil {
+		status.Emitter = trigger.EmitterArea.Model.Service + ":" + trigger.EmitterArea.Model.Name
+	}
+	for _, receiver := range trigger.ReceiversArea {
+		status.Receivers = append(status.Receivers, receiver.Model.Service+":"+receiver.Model.Name)
+	}
+	return status
+}
+
+// It returns the page requested with the `limit` and `offset` query parameters
+func page(c *fiber.Ctx) (int, int) {
+	limit := c.QueryInt("limit", defaultPageSize)
+	if limit <= 0 || limit > maxPageSize {
+		limit = defaultPageSize
+	}
+	offset := c.QueryInt("offset", 0)
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
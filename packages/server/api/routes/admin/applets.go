@@ -0,0 +1,170 @@
+package admin
+
+import (
+	"area-server/db/postgres"
+	"area-server/db/postgres/models"
+	"area-server/store"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// `AppletStatus` is an applet with the live state of its trigger.
+// @property {models.Applet} Applet - The applet.
+// @property {TriggerStatus} Trigger - The live state of its trigger.
+type AppletStatus struct {
+	models.Applet
+	Trigger TriggerStatus `json:"trigger"`
+}
+
+// It adds the live state of their trigger to the applets
+func appletsWithStatus(applets []models.Applet) []AppletStatus {
+	statuses := make([]AppletStatus, 0, len(applets))
+	for _, applet := range applets {
+		statuses = append(statuses, AppletStatus{Applet: applet, Trigger: triggerStatus(applet.UUID)})
+	}
+	return statuses
+}
+
+// It returns the applet of the `applet_id` parameter, or sends an error
+func findApplet(c *fiber.Ctx) (*models.Applet, error) {
+	id, err := uuid.Parse(c.Params("applet_id"))
+	if err != nil {
+		return nil, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":  fiber.StatusBadRequest,
+			"error": "Invalid Applet ID",
+		})
+	}
+
+	var applet models.Applet
+	if result := postgres.DB.Where(&models.Applet{UUID: id}).First(&applet); result.Error == gorm.ErrRecordNotFound {
+		return nil, c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"code":  fiber.StatusNotFound,
+			"error": "No Applet found",
+		})
+	} else if result.Error != nil {
+		return nil, c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":  fiber.StatusInternalServerError,
+			"error": "Internal server error",
+		})
+	}
+	return &applet, nil
+}
+
+// It lists the applets of all the accounts with the live state of their trigger (paginated with `limit`
+// and `offset`), they can be filtered by `account` and `status` (running, stopped)
+func GetApplets(c *fiber.Ctx) error {
+	limit, offset := page(c)
+
+	query := postgres.DB.Model(&models.Applet{})
+	if account := c.Query("account"); account != "" {
+		id, err := uuid.Parse(account)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"code":  fiber.StatusBadRequest,
+				"error": "Invalid account ID",
+			})
+		}
+		query = query.Where("account_uuid = ?", id)
+	}
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if result := query.Count(&total); result.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":  fiber.StatusInternalServerError,
+			"error": "Internal server error",
+		})
+	}
+
+	var applets []models.Applet
+	if result := query.Order("created_at").Limit(limit).Offset(offset).Find(&applets); result.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":  fiber.StatusInternalServerError,
+			"error": "Internal server error",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"code": fiber.StatusOK,
+		"data": fiber.Map{
+			"applets": appletsWithStatus(applets),
+			"total":   total,
+			"limit":   limit,
+			"offset":  offset,
+		},
+	})
+}
+
+// It lists the triggers loaded by the server, with their live state
+func GetTriggers(c *fiber.Ctx) error {
+	triggers := make(map[string]TriggerStatus, len(store.Triggers))
+	for id := range store.Triggers {
+		triggers[id.String()] = triggerStatus(id)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"code": fiber.StatusOK,
+		"data": fiber.Map{
+			"triggers": triggers,
+		},
+	})
+}
+
+// It force-stops an applet of any account
+func StopApplet(c *fiber.Ctx) error {
+	applet, err := findApplet(c)
+	if applet == nil {
+		return err
+	}
+
+	if applet.Status != "running" {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"code":  fiber.StatusConflict,
+			"error": "Applet already stopped",
+		})
+	}
+
+	if err := store.StopTrigger(applet.UUID); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":  fiber.StatusInternalServerError,
+			"error": "Internal server error",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"code": fiber.StatusOK,
+		"data": fiber.Map{
+			"message": "Applet stopped",
+		},
+	})
+}
+
+// It deletes an applet of any account
+func DeleteApplet(c *fiber.Ctx) error {
+	applet, err := findApplet(c)
+	if applet == nil {
+		return err
+	}
+
+	// Stop trigger if running
+	store.StopTrigger(applet.UUID)
+	store.RemoveTrigger(applet.UUID)
+
+	if result := postgres.DB.Delete(applet); result.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":  fiber.StatusInternalServerError,
+			"error": "Internal Server Error",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"code": fiber.StatusOK,
+		"data": fiber.Map{
+			"message": "Applet deleted",
+		},
+	})
+}
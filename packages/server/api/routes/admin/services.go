@@ -0,0 +1,31 @@
+package admin
+
+import (
+	"area-server/utils"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Default window of the error rates (`window` query parameter, in seconds)
+const defaultErrorWindow = time.Hour
+
+// It returns the error rate of the calls sent to every service by the triggers during the `window`
+// (in seconds, one hour by default), since the start of the server
+func GetServiceErrors(c *fiber.Ctx) error {
+	window := time.Duration(c.QueryInt("window", 0)) * time.Second
+	if window <= 0 {
+		window = defaultErrorWindow
+	}
+	if window > utils.ServiceStatsRetention {
+		window = utils.ServiceStatsRetention
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"code": fiber.StatusOK,
+		"data": fiber.Map{
+			"window":   int(window.Seconds()),
+			"services": utils.Stats.Summary(window, time.Now()),
+		},
+	})
+}
@@ -208,18 +208,19 @@ type UpdateAppletRequest struct {
 	}
 
 	// Foreach settings, if value is not empty, check if it's valid
-	var invalidKeys map[string]bool
+	var invalidKeys *static.ValidationResult
 
 	if authorization.UUID != uuid.Nil {
-		invalidKeys = areaItem.Validate(&authorization, service, body.AreaItemSettings)
+		invalidKeys = areaItem.Validate(&authorization, service, body.AreaType, body.AreaItemSettings)
 	} else {
-		invalidKeys = areaItem.Validate(nil, service, body.AreaItemSettings)
+		invalidKeys = areaItem.Validate(nil, service, body.AreaType, body.AreaItemSettings)
 	}
 
 	if invalidKeys != nil {
 		return c.Status(fiber.StatusNotAcceptable).JSON(fiber.Map{
-			"code": fiber.StatusNotAcceptable,
-			"data": invalidKeys,
+			"code":  fiber.StatusNotAcceptable,
+			"error": "Invalid area settings",
+			"data":  invalidKeys,
 		})
 	}
 
@@ -8,6 +8,7 @@ import (
 	"area-server/services"
 	"area-server/store"
 	"area-server/store/webhooks"
+	"area-server/utils"
 	"encoding/json"
 	"strconv"
 
@@ -77,12 +78,15 @@ func GetNewApplet(c *fiber.Ctx) error {
 // @property AreaItemSettings - This is a map of settings that are specific to the area item. For
 // example, if the area item is "send_email", then the area settings would be the email address to send
 // the email to.
+// @property {string} Authorization - The label of the authorization used by the area, when the account
+// has several authorizations for the service (the oldest one if empty).
 type AddStateToNewAppletRequest struct {
 	// Type can be "action" or "reaction"
 	Service          string                 `json:"service" validate:"required"`
 	AreaType         string                 `json:"area_type" validate:"required,oneof=action reaction"`
 	AreaItem         string                 `json:"area_item" validate:"required"`
 	AreaItemSettings map[string]interface{} `json:"area_settings"`
+	Authorization    string                 `json:"authorization"`
 }
 
 // METHOD: PUT
@@ -139,15 +143,14 @@ func AddStateToNewApplet(c *fiber.Ctx) error {
 	var authorization models.Authorization
 	authorization.UUID = uuid.Nil
 	if service.Authenticator != nil {
-		if result := postgres.DB.Where(&models.Authorization{
-			AccountUUID: account.UUID,
-			AuthService: service.Authenticator.Name,
-		}).First(&authorization); result.Error != nil {
+		found, err := utils.FindAuthorization(account.UUID, service.Authenticator.Name, body.Authorization)
+		if err != nil {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 				"code":  fiber.StatusNotFound,
-				"error": "Authorization not found :>" + result.Error.Error(),
+				"error": "Authorization not found :>" + err.Error(),
 			})
 		}
+		authorization = *found
 	}
 
 	// Check if action/reaction exists
@@ -176,18 +179,19 @@ func AddStateToNewApplet(c *fiber.Ctx) error {
 	}
 
 	// Foreach settings, if value is not empty, check if it's valid
-	var invalidKeys map[string]bool
+	var invalidKeys *static.ValidationResult
 
 	if authorization.UUID != uuid.Nil {
-		invalidKeys = areaItem.Validate(&authorization, service, body.AreaItemSettings)
+		invalidKeys = areaItem.Validate(&authorization, service, body.AreaType, body.AreaItemSettings)
 	} else {
-		invalidKeys = areaItem.Validate(nil, service, body.AreaItemSettings)
+		invalidKeys = areaItem.Validate(nil, service, body.AreaType, body.AreaItemSettings)
 	}
 
 	if invalidKeys != nil {
 		return c.Status(fiber.StatusNotAcceptable).JSON(fiber.Map{
-			"code": fiber.StatusNotAcceptable,
-			"data": invalidKeys,
+			"code":  fiber.StatusNotAcceptable,
+			"error": "Invalid area settings",
+			"data":  invalidKeys,
 		})
 	}
 
@@ -268,6 +272,14 @@ func SubmitNewApplet(c *fiber.Ctx) error {
 		})
 	}
 
+	// Only the verified accounts can publish applets in the store
+	if body.Public && !account.EmailVerified {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"code":  fiber.StatusForbidden,
+			"error": "Verify your email address to publish a public applet",
+		})
+	}
+
 	applet := c.Locals("applet").(models.Applet)
 
 	var reactions []models.Area
@@ -0,0 +1,207 @@
+package auth
+
+import (
+	pg "area-server/db/postgres"
+	models "area-server/db/postgres/models"
+	session "area-server/store"
+	"area-server/utils"
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+// `VerifyEmailRequest` contains the token sent by mail to verify the address of an account.
+// @property {string} Token - The token of the verification link.
+type VerifyEmailRequest struct {
+	Token string `validate:"required" json:"token"`
+}
+
+/*
+ *
+ * Description: Route to verify the email address of an account (link sent by mail)
+ * Method: POST
+ * Body:
+ *		Token: <token>
+ *
+ * @Return 200: Email verified
+ * @Return 400: The token is invalid or expired
+ */
+func VerifyEmail(c *fiber.Ctx) error {
+
+	validate := validator.New()
+	verifyReq := new(VerifyEmailRequest)
+
+	// Parse JSON
+	if err := c.BodyParser(verifyReq); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":  fiber.StatusBadRequest,
+			"error": "Bad Request (Wrong Body)",
+		})
+	}
+
+	// Validate
+	if err := validate.Struct(verifyReq); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":  fiber.StatusBadRequest,
+			"error": "Bad Request (Invalid Body)",
+		})
+	}
+
+	account, err := utils.ValidateAccountToken(verifyReq.Token, utils.EmailVerificationToken)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":  fiber.StatusBadRequest,
+			"error": err.Error(),
+		})
+	}
+
+	if !account.EmailVerified {
+		if result := pg.DB.Model(account).Update("email_verified", true); result.Error != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"code":  fiber.StatusInternalServerError,
+				"error": "Internal server error",
+			})
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"code": fiber.StatusOK,
+		"data": fiber.Map{
+			"message": "Email verified !",
+		},
+	})
+}
+
+// `ForgotPasswordRequest` contains the address of the account whose password is forgotten.
+// @property {string} Email - The email address of the account.
+type ForgotPasswordRequest struct {
+	Email string `validate:"required,email" json:"email"`
+}
+
+/*
+ *
+ * Description: Route to receive a link to reset the password of a local account
+ * Method: POST
+ * Body:
+ *		Email: <email>
+ *
+ * @Return 200: Always (it doesn't reveal if an account uses the address)
+ */
+func ForgotPassword(c *fiber.Ctx) error {
+
+	validate := validator.New()
+	forgotReq := new(ForgotPasswordRequest)
+
+	// Parse JSON
+	if err := c.BodyParser(forgotReq); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":  fiber.StatusBadRequest,
+			"error": "Bad Request (Wrong Body)",
+		})
+	}
+
+	// Validate
+	if err := validate.Struct(forgotReq); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":  fiber.StatusBadRequest,
+			"error": "Bad Request (Invalid Body)",
+		})
+	}
+
+	var account models.Account
+	if result := pg.DB.Where(&models.Account{Email: forgotReq.Email, Authenticator: "@local"}).First(&account); result.Error == nil {
+		if allowed, err := session.AllowMail(utils.PasswordResetToken, account.Email); err == nil && allowed {
+			if err := utils.SendPasswordResetEmail(&account); err != nil {
+				fmt.Println("Could not send the password reset mail: ", err)
+			}
+		}
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"code": fiber.StatusOK,
+		"data": fiber.Map{
+			"message": "If an account uses this address, a mail was sent",
+		},
+	})
+}
+
+// `ResetPasswordRequest` contains the token sent by mail and the new password.
+// @property {string} Token - The token of the reset link.
+// @property {string} EncodedPassword - The new password.
+type ResetPasswordRequest struct {
+	Token           string `validate:"required" json:"token"`
+	EncodedPassword string `validate:"required,min=10" json:"encoded_password"`
+}
+
+/*
+ *
+ * Description: Route to reset the password of a local account (link sent by mail), all the sessions
+ * and tokens of the account are revoked
+ * Method: POST
+ * Body:
+ *		Token: <token>
+ *		EncodedPassword: <password>
+ *
+ * @Return 200: Password updated
+ * @Return 400: The token is invalid, expired or already used
+ */
+func ResetPassword(c *fiber.Ctx) error {
+
+	validate := validator.New()
+	resetReq := new(ResetPasswordRequest)
+
+	// Parse JSON
+	if err := c.BodyParser(resetReq); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":  fiber.StatusBadRequest,
+			"error": "Bad Request (Wrong Body)",
+		})
+	}
+
+	// Validate
+	if err := validate.Struct(resetReq); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":  fiber.StatusBadRequest,
+			"error": "Bad Request (Invalid Body)",
+		})
+	}
+
+	account, err := utils.ValidateAccountToken(resetReq.Token, utils.PasswordResetToken)
+	if err != nil || account.Authenticator != "@local" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":  fiber.StatusBadRequest,
+			"error": utils.ErrInvalidAccountToken.Error(),
+		})
+	}
+
+	hash, err := utils.HashPassword(resetReq.EncodedPassword)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":  fiber.StatusInternalServerError,
+			"error": "Internal server error",
+		})
+	}
+
+	// The link was received by mail, so the address is verified too
+	if result := pg.DB.Model(account).Updates(map[string]interface{}{
+		"password":       hash,
+		"email_verified": true,
+	}); result.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":  fiber.StatusInternalServerError,
+			"error": "Internal server error",
+		})
+	}
+
+	if err := session.RevokeAccount(account.UUID); err != nil {
+		fmt.Println("Could not revoke the sessions of the account: ", err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"code": fiber.StatusOK,
+		"data": fiber.Map{
+			"message": "Password updated !",
+		},
+	})
+}
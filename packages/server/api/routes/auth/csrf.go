@@ -0,0 +1,45 @@
+package auth
+
+import (
+	"area-server/config"
+	session "area-server/store"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// It returns the CSRF token of the session (session mode), the token must be sent in the `X-CSRF-Token`
+// header of the mutating requests. A session is created if the client has none yet (before the login).
+func GetCSRFToken(c *fiber.Ctx) error {
+	if config.CFG.Mode != config.Session {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"code":  fiber.StatusNotFound,
+			"error": "CSRF tokens are only used in session mode",
+		})
+	}
+
+	sess, serr := session.SessionStore.Get(c)
+	if serr != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":  fiber.StatusInternalServerError,
+			"error": "Internal server error (Store)",
+		})
+	}
+
+	token, err := session.CSRFToken(sess)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":  fiber.StatusInternalServerError,
+			"error": "Internal server error (Store)",
+		})
+	}
+
+	// The token must not be cached (it is bound to the cookie)
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"code": fiber.StatusOK,
+		"data": fiber.Map{
+			"csrf_token": token,
+			"header":     session.CSRFHeader,
+		},
+	})
+}
@@ -4,11 +4,13 @@ import (
 	"area-server/authenticators"
 	"area-server/classes/static"
 	"area-server/config"
+	"area-server/db/encryption"
 	pg "area-server/db/postgres"
 	models "area-server/db/postgres/models"
 	session "area-server/store"
 	"area-server/utils"
 	"encoding/json"
+	"errors"
 	"time"
 
 	"github.com/go-playground/validator/v10"
@@ -22,10 +24,20 @@ import (
 // @property {string} Authenticator - The name of the authenticator you want to use.
 // @property {string} Code - The code that was returned from the external authentication provider.
 // @property {string} RedirectURI - The redirect URI that was used to obtain the code.
+// @property {string} State - The state returned by the provider (issued by `StartExternalAuth`).
 type ExternalAuthRequest struct {
 	Authenticator string `validate:"required" json:"authenticator"`
 	Code          string `validate:"required" json:"code"`
 	RedirectURI   string `validate:"required" json:"redirect_uri"`
+	State         string `validate:"required" json:"state"`
+}
+
+// `StartExternalAuthRequest` is the body of the request starting an external authentication.
+// @property {string} Authenticator - The name of the authenticator you want to use.
+// @property {string} RedirectURI - The redirect URI the provider will send the code to.
+type StartExternalAuthRequest struct {
+	Authenticator string `validate:"required" json:"authenticator"`
+	RedirectURI   string `validate:"required" json:"redirect_uri"`
 }
 
 // It creates a new account and authorization for the user
@@ -35,6 +47,7 @@ func newAccount(fSR *static.AuthenticateResponse, externalAuthReq *ExternalAuthR
 		Email:         fSR.Data["email"].(string),
 		Authenticator: externalAuthReq.Authenticator,
 		Username:      utils.GenerateNameForUser(),
+		EmailVerified: true, // Verified by the provider
 	}
 
 	if result := pg.DB.Create(account); result.Error != nil || result.RowsAffected == 0 {
@@ -76,7 +89,61 @@ func newAccount(fSR *static.AuthenticateResponse, externalAuthReq *ExternalAuthR
  *
  */
 
-// It takes a code, authenticator, and redirect URI, and returns a token or session
+// It issues a one-time state for the authenticator and returns the URI the user must be redirected to
+func StartExternalAuth(c *fiber.Ctx) error {
+	validate := validator.New()
+	body := new(StartExternalAuthRequest)
+
+	if err := c.BodyParser(body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":  fiber.StatusBadRequest,
+			"error": "[Error] Bad Request (JSON)",
+		})
+	}
+
+	if err := validate.Struct(body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":  fiber.StatusBadRequest,
+			"error": "[Error] Bad Request (JSON)",
+		})
+	}
+
+	authenticator := authenticators.GetAuthenticator(body.Authenticator)
+	if authenticator == nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"code":  fiber.StatusNotFound,
+			"error": "[Error] Authenticator not found",
+		})
+	}
+
+	if !authenticator.Enabled {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":  fiber.StatusBadRequest,
+			"error": "Service does not provide authentication",
+		})
+	}
+
+	uri, state, err := session.BeginOAuthFlow(authenticator, session.OAuthFlow{
+		RedirectURI: body.RedirectURI,
+		Purpose:     session.OAuthLogin,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":  fiber.StatusInternalServerError,
+			"error": "Internal server error (Store)",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"code": fiber.StatusOK,
+		"data": fiber.Map{
+			"authorization_uri": uri,
+			"state":             state,
+		},
+	})
+}
+
+// It takes a code, authenticator, redirect URI and state, and returns a token or session
 func ExternalAuth(c *fiber.Ctx) error {
 
 	// Token Mode: Remove this
@@ -121,11 +188,27 @@ func ExternalAuth(c *fiber.Ctx) error {
 		})
 	}
 
+	// Verify the state (one-time, the flow is deleted even if it does not match)
+	flow, err := session.ConsumeOAuthFlow(externalAuthReq.State)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":  fiber.StatusInternalServerError,
+			"error": "Internal server error (Store)",
+		})
+	}
+	if flow == nil || !flow.Matches(authenticator.Name, externalAuthReq.RedirectURI, session.OAuthLogin, "") {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":  fiber.StatusBadRequest,
+			"error": "[Error] Invalid state",
+		})
+	}
+
 	// Validate service + Retrieve Email
 	fSR, fSE := authenticator.Authenticate([]interface{}{
 		true,
 		externalAuthReq.Code,
 		externalAuthReq.RedirectURI,
+		flow.Verifier,
 	})
 
 	if fSE != nil {
@@ -135,19 +218,26 @@ func ExternalAuth(c *fiber.Ctx) error {
 		})
 	}
 
-	// 4. Check if account exists, if not, return 401
-	var Account models.Account
+	// 4. Find the account of the login (linked identity or account created with the authenticator)
 	var status int
 	var message string
 	var accountUUID uuid.UUID
 
-	if result := pg.DB.Where(&models.Account{Email: fSR.Data["email"].(string)}).First(&Account); result.Error == gorm.ErrRecordNotFound || result.RowsAffected == 0 {
-		// Create account
+	email := fSR.Data["email"].(string)
+	Account, err := utils.FindLoginAccount(externalAuthReq.Authenticator, email)
+	if err == gorm.ErrRecordNotFound {
+		// The login must be linked from the account that already uses the email
+		if result := pg.DB.Where(&models.Account{Email: email}).First(&models.Account{}); result.Error == nil {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"code":  fiber.StatusConflict,
+				"error": "Account already exist with this email ! Log in to it and link " + authenticator.Name + " from your profile",
+			})
+		}
 
 		// Check if access token already exists
 		if result := pg.DB.Where(&models.Authorization{
-			AuthService: externalAuthReq.Authenticator,
-			AccessToken: fSR.Data["access_token"].(string),
+			AuthService:     externalAuthReq.Authenticator,
+			AccessTokenHash: encryption.Fingerprint(fSR.Data["access_token"].(string)),
 		}).First(&models.Authorization{}); result.Error == nil && result.RowsAffected != 0 {
 			return c.Status(fiber.StatusNotAcceptable).JSON(fiber.Map{
 				"code":  fiber.StatusNotAcceptable,
@@ -155,6 +245,7 @@ func ExternalAuth(c *fiber.Ctx) error {
 			})
 		}
 
+		// Create account
 		account, err := newAccount(fSR, externalAuthReq)
 		if err != nil {
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -163,11 +254,64 @@ func ExternalAuth(c *fiber.Ctx) error {
 			})
 		}
 		status, message, accountUUID = 201, "Account Created", account.UUID
+	} else if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":  fiber.StatusInternalServerError,
+			"error": err.Error(),
+		})
 	} else {
-		if Account.Authenticator != externalAuthReq.Authenticator {
-			return c.Status(fiber.StatusNotAcceptable).JSON(fiber.Map{
-				"code":  fiber.StatusNotAcceptable,
-				"error": "Account already exist with this email !",
+		// Renew the authorization of the account if its refresh token was revoked
+		var authorization models.Authorization
+		if result := pg.DB.Where(&models.Authorization{
+			AccountUUID: Account.UUID,
+			AuthService: externalAuthReq.Authenticator,
+			Permanent:   true,
+		}).First(&authorization); result.Error == nil && authorization.NeedsReauth {
+			if err := static.RenewAuthorization(&authorization, fSR); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"code":  fiber.StatusInternalServerError,
+					"error": err.Error(),
+				})
+			}
+		}
+
+		if Account.Disabled {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"code":  fiber.StatusForbidden,
+				"error": "Account disabled",
+			})
+		}
+
+		// A local account with two-factor authentication can be logged in with a linked identity,
+		// the second factor is still required
+		twoFactor, err := utils.FindTwoFactor(Account.UUID)
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"code":  fiber.StatusInternalServerError,
+				"error": "Internal server error",
+			})
+		}
+		if twoFactor != nil && twoFactor.Enabled {
+			challenge, err := session.StartLoginChallenge(Account.UUID)
+			if errors.Is(err, session.ErrSecondFactorLocked) {
+				return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+					"code":  fiber.StatusTooManyRequests,
+					"error": err.Error(),
+				})
+			}
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"code":  fiber.StatusInternalServerError,
+					"error": "Internal server error (Store)",
+				})
+			}
+			return c.Status(fiber.StatusOK).JSON(fiber.Map{
+				"code": fiber.StatusOK,
+				"data": fiber.Map{
+					"message":    "Second factor required",
+					"two_factor": true,
+					"challenge":  challenge,
+				},
 			})
 		}
 
@@ -186,17 +330,19 @@ func ExternalAuth(c *fiber.Ctx) error {
 				"error": err.Error(),
 			})
 		}
+		// The client sends it with the mutating requests (`X-CSRF-Token`)
+		data["csrf_token"] = sess.Get("csrf")
 	} else {
-		token, err := utils.GenerateJWT(fiber.Map{
-			"accountID": accountUUID,
-		}, config.CFG.TokenDuration)
+		tokens, err := session.IssueTokens(accountUUID)
 		if err != nil {
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 				"code":  fiber.StatusInternalServerError,
 				"error": err.Error(),
 			})
 		}
-		data["token"] = token
+		data["token"] = tokens.AccessToken
+		data["refresh_token"] = tokens.RefreshToken
+		data["expires_in"] = tokens.ExpiresIn
 	}
 
 	return c.Status(status).JSON(fiber.Map{
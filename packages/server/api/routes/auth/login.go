@@ -6,9 +6,13 @@ import (
 	models "area-server/db/postgres/models"
 	session "area-server/store"
 	"area-server/utils"
+	"errors"
+	"log"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
+	fsession "github.com/gofiber/fiber/v2/middleware/session"
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
@@ -64,6 +68,8 @@ func Login(c *fiber.Ctx) error {
 	// Check if account already exist
 	var Account models.Account
 	if result := pg.DB.Where(&models.Account{Email: loginReq.Email, Authenticator: "@local"}).First(&Account); result.Error == gorm.ErrRecordNotFound || result.RowsAffected == 0 {
+		// As long as a wrong password, so the existing accounts can't be guessed
+		utils.VerifyDummyPassword(loginReq.EncodedPassword)
 		return c.Status(fiber.StatusNotAcceptable).JSON(fiber.Map{
 			"code":  fiber.StatusNotAcceptable,
 			"error": "Account not found",
@@ -71,36 +77,93 @@ func Login(c *fiber.Ctx) error {
 	}
 
 	// Check if password is correct
-	if loginReq.EncodedPassword != Account.Password {
+	ok, rehash, err := utils.VerifyPassword(loginReq.EncodedPassword, Account.Password)
+	if err != nil || !ok {
 		return c.Status(fiber.StatusNotAcceptable).JSON(fiber.Map{
 			"code":  fiber.StatusNotAcceptable,
 			"error": "Account not found",
 		})
 	}
 
+	// Upgrade the stored password (not hashed yet or old parameters)
+	if rehash {
+		if hash, err := utils.HashPassword(loginReq.EncodedPassword); err != nil {
+			log.Printf("[Login] Could not hash the password of %s: %s", Account.UUID, err.Error())
+		} else if result := pg.DB.Model(&Account).Update("password", hash); result.Error != nil {
+			log.Printf("[Login] Could not upgrade the password of %s: %s", Account.UUID, result.Error.Error())
+		}
+	}
+
+	if Account.Disabled {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"code":  fiber.StatusForbidden,
+			"error": "Account disabled",
+		})
+	}
+
+	// The session or the token is only issued once the second factor is verified
+	twoFactor, err := utils.FindTwoFactor(Account.UUID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":  fiber.StatusInternalServerError,
+			"error": "Internal server error",
+		})
+	}
+	if twoFactor != nil && twoFactor.Enabled {
+		challenge, err := session.StartLoginChallenge(Account.UUID)
+		if errors.Is(err, session.ErrSecondFactorLocked) {
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+				"code":  fiber.StatusTooManyRequests,
+				"error": err.Error(),
+			})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"code":  fiber.StatusInternalServerError,
+				"error": "Internal server error (Store)",
+			})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"code": fiber.StatusOK,
+			"data": fiber.Map{
+				"message":    "Second factor required",
+				"two_factor": true,
+				"challenge":  challenge,
+			},
+		})
+	}
+
+	return completeLogin(c, sess, Account.UUID)
+}
+
+// It issues the session (or the tokens) of an authenticated account
+func completeLogin(c *fiber.Ctx, sess *fsession.Session, accountUUID uuid.UUID) error {
+
 	data := make(map[string]interface{})
 
 	data["message"] = "Login successful !"
 	if config.CFG.Mode == config.Session {
 		session.DestroySession(sess)
-		err := session.GenerateSession(sess, Account.UUID)
+		err := session.GenerateSession(sess, accountUUID)
 		if err != nil {
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 				"code":  fiber.StatusInternalServerError,
 				"error": err.Error(),
 			})
 		}
+		// The client sends it with the mutating requests (`X-CSRF-Token`)
+		data["csrf_token"] = sess.Get("csrf")
 	} else {
-		token, err := utils.GenerateJWT(fiber.Map{
-			"accountID": Account.UUID,
-		}, config.CFG.TokenDuration)
+		tokens, err := session.IssueTokens(accountUUID)
 		if err != nil {
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 				"code":  fiber.StatusInternalServerError,
 				"error": err.Error(),
 			})
 		}
-		data["token"] = token
+		data["token"] = tokens.AccessToken
+		data["refresh_token"] = tokens.RefreshToken
+		data["expires_in"] = tokens.ExpiresIn
 	}
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
@@ -108,3 +171,94 @@ func Login(c *fiber.Ctx) error {
 		"data": data,
 	})
 }
+
+// `LoginSecondFactorRequest` contains the challenge returned by the login and a code of the second
+// factor.
+// @property {string} Challenge - The challenge returned by `/auth/login`.
+// @property {string} Code - A TOTP code or a recovery code.
+type LoginSecondFactorRequest struct {
+	Challenge string `validate:"required" json:"challenge"`
+	Code      string `validate:"required,max=32" json:"code"`
+}
+
+/*
+ *
+ * Description: Route to finish the login of an account with two-factor authentication
+ * Method: POST
+ * Body:
+ *		Challenge: <challenge>
+ *		Code: <totp code | recovery code>
+ *
+ * @Return 200: Login successful
+ * @Return 401: Invalid code, or the login expired (5 minutes, 5 codes)
+ */
+func LoginSecondFactor(c *fiber.Ctx) error {
+
+	sess, serr := session.SessionStore.Get(c)
+	if serr != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":  fiber.StatusInternalServerError,
+			"error": "Internal server error (Store)",
+		})
+	}
+	validate := validator.New()
+	secondReq := new(LoginSecondFactorRequest)
+
+	// Parse JSON
+	if err := c.BodyParser(secondReq); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":  fiber.StatusBadRequest,
+			"error": "Bad Request (Wrong Body)",
+		})
+	}
+
+	// Validate
+	if err := validate.Struct(secondReq); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":  fiber.StatusBadRequest,
+			"error": "Bad Request (Invalid Body)",
+		})
+	}
+
+	accountUUID, err := session.LoginChallengeAccount(secondReq.Challenge)
+	if errors.Is(err, session.ErrSecondFactorLocked) {
+		session.EndLoginChallenge(secondReq.Challenge)
+		return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+			"code":  fiber.StatusTooManyRequests,
+			"error": err.Error(),
+		})
+	}
+	if err == session.ErrLoginChallenge {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"code":  fiber.StatusUnauthorized,
+			"error": err.Error(),
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":  fiber.StatusInternalServerError,
+			"error": "Internal server error (Store)",
+		})
+	}
+
+	valid, err := utils.VerifySecondFactor(accountUUID, secondReq.Code)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":  fiber.StatusInternalServerError,
+			"error": "Internal server error",
+		})
+	}
+	if !valid {
+		if err := session.RecordSecondFactorFailure(accountUUID); err != nil {
+			log.Printf("[Login] Could not count the invalid code of %s: %s", accountUUID, err.Error())
+		}
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"code":  fiber.StatusUnauthorized,
+			"error": "Invalid code",
+		})
+	}
+
+	session.ResetSecondFactorFailures(accountUUID)
+	session.EndLoginChallenge(secondReq.Challenge)
+	return completeLogin(c, sess, accountUUID)
+}
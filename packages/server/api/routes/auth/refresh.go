@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"area-server/config"
+	session "area-server/store"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+// RefreshRequest contains the refresh token returned on login (or by the previous refresh).
+// @property {string} RefreshToken - The refresh token, it can only be used once.
+type RefreshRequest struct {
+	RefreshToken string `validate:"required" json:"refresh_token"`
+}
+
+/*
+ *
+ * Description: Route to exchange a refresh token for a new access token and refresh token (token mode)
+ * Method: POST
+ * Body:
+ *		RefreshToken: <refresh_token>
+ *
+ * @Return 200: The new tokens
+ * @Return 401: The refresh token is invalid, expired or revoked (reusing a refresh token revokes the
+ * whole session)
+ */
+func Refresh(c *fiber.Ctx) error {
+
+	if config.CFG.Mode != config.Token {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"code":  fiber.StatusNotFound,
+			"error": "The server doesn't use tokens",
+		})
+	}
+
+	validate := validator.New()
+	refreshReq := new(RefreshRequest)
+
+	// Parse JSON
+	if err := c.BodyParser(refreshReq); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":  fiber.StatusBadRequest,
+			"error": "Bad Request (Wrong Body)",
+		})
+	}
+
+	// Validate
+	if err := validate.Struct(refreshReq); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":  fiber.StatusBadRequest,
+			"error": "Bad Request (Invalid Body)",
+		})
+	}
+
+	tokens, err := session.RefreshTokens(refreshReq.RefreshToken)
+	if err == session.ErrInvalidToken || err == session.ErrTokenRevoked || err == session.ErrTokenReused {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"code":  fiber.StatusUnauthorized,
+			"error": err.Error(),
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":  fiber.StatusInternalServerError,
+			"error": "Internal server error",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"code": fiber.StatusOK,
+		"data": fiber.Map{
+			"token":         tokens.AccessToken,
+			"refresh_token": tokens.RefreshToken,
+			"expires_in":    tokens.ExpiresIn,
+		},
+	})
+}
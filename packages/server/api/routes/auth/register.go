@@ -6,6 +6,7 @@ import (
 	models "area-server/db/postgres/models"
 	session "area-server/store"
 	utils "area-server/utils"
+	"fmt"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
@@ -70,13 +71,22 @@ func Register(c *fiber.Ctx) error {
 		})
 	}
 
+	// Hash the password
+	hash, err := utils.HashPassword(registerReq.EncodedPassword)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":  fiber.StatusInternalServerError,
+			"error": "Internal server error",
+		})
+	}
+
 	// Create account
 	account := models.Account{
 		UUID:          uuid.New(),
 		Authenticator: "@local",
 		Email:         registerReq.Email,
 		Username:      utils.GenerateNameForUser(),
-		Password:      registerReq.EncodedPassword,
+		Password:      hash,
 	}
 
 	if result := pg.DB.Create(&account); result.Error != nil || result.RowsAffected == 0 {
@@ -86,6 +96,13 @@ func Register(c *fiber.Ctx) error {
 		})
 	}
 
+	// The account works without verification, except the publication of applets
+	if allowed, err := session.AllowMail(utils.EmailVerificationToken, account.Email); err == nil && allowed {
+		if err := utils.SendVerificationEmail(&account); err != nil {
+			fmt.Println("Could not send the verification mail: ", err)
+		}
+	}
+
 	data := make(map[string]interface{})
 
 	data["message"] = "Account created !"
@@ -99,11 +116,10 @@ func Register(c *fiber.Ctx) error {
 				"error": err.Error(),
 			})
 		}
-
+		// The client sends it with the mutating requests (`X-CSRF-Token`)
+		data["csrf_token"] = sess.Get("csrf")
 	} else {
-		token, err := utils.GenerateJWT(fiber.Map{
-			"accountID": account.UUID,
-		}, config.CFG.TokenDuration)
+		tokens, err := session.IssueTokens(account.UUID)
 
 		if err != nil {
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -111,8 +127,9 @@ func Register(c *fiber.Ctx) error {
 				"error": err.Error(),
 			})
 		}
-
-		data["token"] = token
+		data["token"] = tokens.AccessToken
+		data["refresh_token"] = tokens.RefreshToken
+		data["expires_in"] = tokens.ExpiresIn
 	}
 
 	// Return token
@@ -8,7 +8,7 @@ import (
 
 // `GetAuthenticators` is a function that takes a `fiber.Ctx` and returns an `error`
 func GetAuthenticators(c *fiber.Ctx) error {
-	return c.Status(fiber.StatusOK).JSON(authenticators.List)
+	return c.Status(fiber.StatusOK).JSON(authenticators.List())
 }
 
 // "Get the authenticator with the given name and return it as JSON."
@@ -19,7 +19,7 @@ func GetAuthenticators(c *fiber.Ctx) error {
 func GetAuthenticator(c *fiber.Ctx) error {
 	name := c.Params("name")
 
-	for _, authenticator := range authenticators.List {
+	for _, authenticator := range authenticators.List() {
 		if authenticator.Name == name {
 			return c.Status(fiber.StatusOK).JSON(authenticator)
 		}
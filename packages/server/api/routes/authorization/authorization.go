@@ -2,6 +2,8 @@ package authorization
 
 import (
 	"area-server/authenticators"
+	"area-server/classes/static"
+	"area-server/db/encryption"
 	"area-server/db/postgres"
 	"area-server/db/postgres/models"
 	"area-server/services"
@@ -27,10 +29,23 @@ import (
 // authenticate the user.
 // @property {string} Code - The code that was returned from the authorization request.
 // @property {string} RedirectURI - The redirect URI that was used to obtain the authorization code.
+// @property {string} State - The state returned by the provider (issued by `StartAuthorization`).
+// @property {string} Label - The label of the authorization (e.g. "personal", "bot"), an account can
+// have several authorizations for the same service with different labels ("default" if empty).
 type CreateAuthorizationForServiceRequest struct {
 	AuthenticatorName string `json:"authenticator" validate:"required"`
 	Code              string `json:"code" validate:"required"`
 	RedirectURI       string `json:"redirect_uri" validate:"required"`
+	State             string `json:"state" validate:"required"`
+	Label             string `json:"label" validate:"omitempty,max=32,printascii"`
+}
+
+// `StartAuthorizationRequest` is the body of the request starting the authorization of a service.
+// @property {string} AuthenticatorName - The name of the authenticator to authorize.
+// @property {string} RedirectURI - The redirect URI the provider will send the code to.
+type StartAuthorizationRequest struct {
+	AuthenticatorName string `json:"authenticator" validate:"required"`
+	RedirectURI       string `json:"redirect_uri" validate:"required"`
 }
 
 // `AuthorizationMeta` is a struct with two fields, `Authenticator` and `Applets`.
@@ -40,8 +55,10 @@ type CreateAuthorizationForServiceRequest struct {
 // the user.
 // @property {[]string} Applets - A list of applets that are allowed to be used with this
 // authenticator.
+// @property {string} Label - The label of the authorization.
 type AuthorizationMeta struct {
 	Authenticator string   `json:"authenticator"`
+	Label         string   `json:"label"`
 	Applets       []string `json:"applets"`
 }
 
@@ -90,6 +107,7 @@ func GetAuthorizations(c *fiber.Ctx) error {
 		}
 		meta = append(meta, AuthorizationMeta{
 			Authenticator: authorization.AuthService,
+			Label:         authorization.Label,
 			Applets:       appletsUsed,
 		})
 	}
@@ -104,7 +122,61 @@ func GetAuthorizations(c *fiber.Ctx) error {
 }
 
 // METHOD: POST
-// Body: service, code, redirect_uri
+// Body: authenticator, redirect_uri
+
+// It issues a one-time state bound to the account and returns the URI the user must be redirected to
+func StartAuthorization(c *fiber.Ctx) error {
+
+	account := c.Locals("account").(models.Account)
+
+	validate := validator.New()
+	body := new(StartAuthorizationRequest)
+
+	if err := c.BodyParser(body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":  fiber.StatusBadRequest,
+			"error": "Bad Request (Wrong body)",
+		})
+	}
+
+	if err := validate.Struct(body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":  fiber.StatusBadRequest,
+			"error": "Bad Request (Invalid body)",
+		})
+	}
+
+	authenticator := authenticators.GetAuthenticator(body.AuthenticatorName)
+	if authenticator == nil {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"code":  fiber.StatusForbidden,
+			"error": "Authenticator not found",
+		})
+	}
+
+	uri, state, err := store.BeginOAuthFlow(authenticator, store.OAuthFlow{
+		RedirectURI: body.RedirectURI,
+		Purpose:     store.OAuthAuthorization,
+		Account:     account.UUID.String(),
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":  fiber.StatusInternalServerError,
+			"error": "Internal server error",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"code": fiber.StatusOK,
+		"data": fiber.Map{
+			"authorization_uri": uri,
+			"state":             state,
+		},
+	})
+}
+
+// METHOD: POST
+// Body: service, code, redirect_uri, state
 
 // It creates an authorization for a service
 func CreateAuthorization(c *fiber.Ctx) error {
@@ -137,10 +209,26 @@ func CreateAuthorization(c *fiber.Ctx) error {
 		})
 	}
 
+	// Verify the state (one-time and bound to the account that started the flow)
+	flow, err := store.ConsumeOAuthFlow(body.State)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":  fiber.StatusInternalServerError,
+			"error": "Internal server error",
+		})
+	}
+	if flow == nil || !flow.Matches(authenticator.Name, body.RedirectURI, store.OAuthAuthorization, account.UUID.String()) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"code":  fiber.StatusForbidden,
+			"error": "Invalid state",
+		})
+	}
+
 	fSR, fSE := authenticator.Authenticate([]interface{}{
 		false,
 		body.Code,
 		body.RedirectURI,
+		flow.Verifier,
 	})
 
 	if fSE != nil {
@@ -159,8 +247,8 @@ func CreateAuthorization(c *fiber.Ctx) error {
 
 	var tmpAuth models.Authorization
 	if result := postgres.DB.Where(&models.Authorization{
-		AuthService: authenticator.Name,
-		AccessToken: fSR.Data["access_token"].(string),
+		AuthService:     authenticator.Name,
+		AccessTokenHash: encryption.Fingerprint(fSR.Data["access_token"].(string)),
 	}).First(&tmpAuth); result.Error == nil && result.RowsAffected != 0 {
 
 		if tmpAuth.AccountUUID == account.UUID {
@@ -178,15 +266,40 @@ func CreateAuthorization(c *fiber.Ctx) error {
 		})
 	}
 
-	// Retrieve authorizations for the account
+	label := body.Label
+	if label == "" {
+		label = models.DefaultAuthorizationLabel
+	}
+
+	// Retrieve the authorization with the same label for the account
 	var authorizations []models.Authorization
 	if result := postgres.DB.Where(&models.Authorization{
 		AccountUUID: account.UUID,
 		AuthService: authenticator.Name,
-	}).Find(&authorizations); result.Error != nil || len(authorizations) != 0 {
+		Label:       label,
+	}).Find(&authorizations); result.Error == nil && len(authorizations) == 1 && authorizations[0].NeedsReauth {
+		// The refresh token was revoked, the authorization is renewed
+		if err := static.RenewAuthorization(&authorizations[0], fSR); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"code":  fiber.StatusInternalServerError,
+				"error": "Internal server error",
+			})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"code": fiber.StatusOK,
+			"data": fiber.Map{
+				"message": "Authorization renewed !",
+			},
+		})
+	} else if result.Error != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"code":  fiber.StatusInternalServerError,
-			"error": "Service already authorized",
+			"error": "Internal server error",
+		})
+	} else if len(authorizations) != 0 {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"code":  fiber.StatusConflict,
+			"error": "Service already authorized with the label " + label,
 		})
 	}
 
@@ -208,6 +321,7 @@ func CreateAuthorization(c *fiber.Ctx) error {
 		AccountUUID:  account.UUID,
 		Type:         fSR.Type,
 		AuthService:  authenticator.Name,
+		Label:        label,
 		AccessToken:  fSR.Data["access_token"].(string),
 		RefreshToken: fSR.Data["refresh_token"].(string),
 		ExpireAt:     fSR.Data["expired_at"].(time.Time),
@@ -230,6 +344,7 @@ func CreateAuthorization(c *fiber.Ctx) error {
 
 // METHOD: DELETE
 // Params: authService
+// Query: label (required if the account has several authorizations for the service)
 func DeleteAuthorization(c *fiber.Ctx) error {
 
 	authenticator := authenticators.GetAuthenticator(c.Params("name", ""))
@@ -248,6 +363,7 @@ func DeleteAuthorization(c *fiber.Ctx) error {
 	if result := postgres.DB.Where(&models.Authorization{
 		AccountUUID: account.UUID,
 		AuthService: authenticator.Name,
+		Label:       c.Query("label"),
 	}).Find(&authorizations); result.Error != nil {
 
 		if result.Error != gorm.ErrRecordNotFound {
@@ -265,10 +381,17 @@ func DeleteAuthorization(c *fiber.Ctx) error {
 		})
 	}
 
+	if len(authorizations) == 0 {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"code":  fiber.StatusNotFound,
+			"error": "Authorization not found",
+		})
+	}
+
 	if len(authorizations) != 1 {
-		return c.Status(fiber.StatusTeapot).JSON(fiber.Map{
-			"code":  fiber.StatusTeapot,
-			"error": "Internal server error :> " + strconv.Itoa(len(authorizations)) + " authorizations found",
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"code":  fiber.StatusConflict,
+			"error": strconv.Itoa(len(authorizations)) + " authorizations found, select one with the label",
 		})
 	}
 
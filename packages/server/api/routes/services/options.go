@@ -0,0 +1,238 @@
+package services
+
+import (
+	"area-server/classes/static"
+	"area-server/db/postgres/models"
+	sservices "area-server/services"
+	"area-server/utils"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/valyala/fasthttp"
+)
+
+// Duration during which the options fetched from a service are reused
+const OptionsCacheDuration = 5 * time.Minute
+
+// Maximum number of routes whose options are kept in the cache
+const OptionsCacheSize = 1000
+
+// Default and maximum number of options returned per page
+const (
+	OptionsDefaultLimit = 50
+	OptionsMaxLimit     = 100
+)
+
+// `OptionsResponse` is the response of a service route used by a select_uri field.
+// @property {[]interface{}} Data - The options.
+// @property {[]string} Fields - The field used as label, then the field used as value.
+type OptionsResponse struct {
+	Data   []interface{} `json:"data"`
+	Fields []string      `json:"fields"`
+}
+
+var (
+	optionsCache = utils.NewExpiringCache(OptionsCacheDuration, OptionsCacheSize)
+
+	// Applications serving the routes of each service, used to call them without going through HTTP
+	optionsApps      = make(map[string]*fiber.App)
+	optionsAppsMutex sync.Mutex
+)
+
+// It returns the application serving the routes of the service
+func optionsApp(service *static.Service) *fiber.App {
+	optionsAppsMutex.Lock()
+	defer optionsAppsMutex.Unlock()
+
+	if app, ok := optionsApps[service.Name]; ok {
+		return app
+	}
+
+	app := fiber.New()
+	for _, route := range service.Routes {
+		app.Add(route.Method, route.Endpoint, route.Handler)
+	}
+	optionsApps[service.Name] = app
+	return app
+}
+
+// It calls the service route with the account of the user (and the label of the authorization to use)
+// and returns the options
+func fetchOptions(service *static.Service, account models.Account, label string, uri string) (*OptionsResponse, int, error) {
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.Header.SetMethod(fiber.MethodGet)
+	ctx.Request.SetRequestURI(uri)
+	ctx.SetUserValue("account", account)
+	ctx.SetUserValue("authorization", label)
+
+	optionsApp(service).Handler()(ctx)
+
+	var body struct {
+		Error string          `json:"error"`
+		Data  OptionsResponse `json:"data"`
+	}
+	if err := json.Unmarshal(ctx.Response.Body(), &body); err != nil {
+		return nil, fiber.StatusBadGateway, err
+	}
+
+	if status := ctx.Response.StatusCode(); status != fiber.StatusOK {
+		return nil, status, fmt.Errorf("%s", body.Error)
+	}
+	return &body.Data, fiber.StatusOK, nil
+}
+
+// It returns the value of the field of an option, nested fields are separated by ':'
+func optionField(option interface{}, field string) string {
+	current := option
+	for _, key := range strings.Split(field, ":") {
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		current = object[key]
+	}
+
+	switch value := current.(type) {
+	case string:
+		return value
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// METHOD: GET
+// Description: Get the options of a select_uri field, the `${...}` placeholders of the route are
+// replaced by the query parameters (the fields already filled by the user)
+func GetAreaFieldOptions(c *fiber.Ctx) error {
+	account := c.Locals("account").(models.Account)
+
+	service := sservices.GetServiceByName(c.Params("service"))
+	if service == nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"code":  fiber.StatusNotFound,
+			"error": "Service not found",
+		})
+	}
+
+	area := service.GetActionByName(c.Params("area"))
+	if area == nil {
+		area = service.GetReactionByName(c.Params("area"))
+	}
+	if area == nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"code":  fiber.StatusNotFound,
+			"error": "Area item not found",
+		})
+	}
+
+	element, ok := area.RequestStore[c.Params("field")]
+	if !ok {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"code":  fiber.StatusNotFound,
+			"error": "Field not found",
+		})
+	}
+
+	if element.Type != "select_uri" || len(element.Values) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":  fiber.StatusBadRequest,
+			"error": "Field has no options",
+		})
+	}
+
+	page, errP := strconv.Atoi(c.Query("page", "1"))
+	limit, errL := strconv.Atoi(c.Query("limit", strconv.Itoa(OptionsDefaultLimit)))
+	if errP != nil || errL != nil || page < 1 || limit < 1 || limit > OptionsMaxLimit {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":  fiber.StatusBadRequest,
+			"error": "Invalid pagination",
+		})
+	}
+
+	// The cache is shared by the users of the same authorization (selected by its label)
+	owner := account.UUID
+	label := c.Query("authorization")
+	if service.Authenticator != nil {
+		authorization, err := utils.FindAuthorization(account.UUID, service.Authenticator.Name, label)
+		if err != nil {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"code":  fiber.StatusNotFound,
+				"error": "Authorization not found",
+			})
+		}
+		owner, label = authorization.UUID, authorization.Label
+	}
+
+	uri := element.OptionsURI(func(key string) string {
+		return c.Query(key)
+	})
+
+	options, err := cachedOptions(service, account, owner, label, uri, c.Query("refresh") == "true")
+	if err != nil {
+		return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{
+			"code":  fiber.StatusBadGateway,
+			"error": err.Error(),
+		})
+	}
+
+	// Search on the label of the options
+	items := options.Data
+	if search := strings.ToLower(c.Query("search")); search != "" && len(options.Fields) > 0 {
+		items = []interface{}{}
+		for _, option := range options.Data {
+			if strings.Contains(strings.ToLower(optionField(option, options.Fields[0])), search) {
+				items = append(items, option)
+			}
+		}
+	}
+
+	total := len(items)
+	start := (page - 1) * limit
+	if start > total {
+		start = total
+	}
+	end := start + limit
+	if end > total {
+		end = total
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"code": fiber.StatusOK,
+		"data": fiber.Map{
+			"data":     items[start:end],
+			"fields":   options.Fields,
+			"page":     page,
+			"limit":    limit,
+			"total":    total,
+			"has_more": end < total,
+		},
+	})
+}
+
+// It returns the options of the route from the cache, or fetches them if they are missing / expired
+func cachedOptions(service *static.Service, account models.Account, owner uuid.UUID, label string, uri string, refresh bool) (*OptionsResponse, error) {
+	key := service.Name + ":" + owner.String() + ":" + uri
+
+	if cached, ok := optionsCache.Get(key, time.Now()); ok && !refresh {
+		return cached.(*OptionsResponse), nil
+	}
+
+	options, _, err := fetchOptions(service, account, label, uri)
+	if err != nil {
+		return nil, err
+	}
+	if options.Data == nil {
+		options.Data = []interface{}{}
+	}
+
+	optionsCache.Set(key, options, time.Now())
+	return options, nil
+}
@@ -116,3 +116,67 @@ func GetApiEndpoints(c *fiber.Ctx) error {
 		"error": "Service not found",
 	})
 }
+
+// It takes the service name from the URL, and returns the JSON Schema of the store of every action and
+// reaction of the service
+func GetServiceSchema(c *fiber.Ctx) error {
+	service := sservices.GetServiceByName(c.Params("service"))
+	if service == nil {
+		return c.Status(404).JSON(fiber.Map{
+			"error": "Service not found",
+		})
+	}
+
+	actions := make(map[string]interface{})
+	for _, a := range service.Actions {
+		actions[a.Name] = a.Schema(service, "action")
+	}
+
+	reactions := make(map[string]interface{})
+	for _, r := range service.Reactions {
+		reactions[r.Name] = r.Schema(service, "reaction")
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"actions":   actions,
+		"reactions": reactions,
+	})
+}
+
+// It gets the service and action from the URL, and returns the JSON Schema of the action store
+func GetServiceActionSchema(c *fiber.Ctx) error {
+	service := sservices.GetServiceByName(c.Params("service"))
+	if service == nil {
+		return c.Status(404).JSON(fiber.Map{
+			"error": "Service not found",
+		})
+	}
+
+	action := service.GetActionByName(c.Params("action"))
+	if action == nil {
+		return c.Status(404).JSON(fiber.Map{
+			"error": "Action not found",
+		})
+	}
+
+	return c.Status(200).JSON(action.Schema(service, "action"))
+}
+
+// It gets the service and reaction from the URL, and returns the JSON Schema of the reaction store
+func GetServiceReactionSchema(c *fiber.Ctx) error {
+	service := sservices.GetServiceByName(c.Params("service"))
+	if service == nil {
+		return c.Status(404).JSON(fiber.Map{
+			"error": "Service not found",
+		})
+	}
+
+	reaction := service.GetReactionByName(c.Params("reaction"))
+	if reaction == nil {
+		return c.Status(404).JSON(fiber.Map{
+			"error": "Reaction not found",
+		})
+	}
+
+	return c.Status(200).JSON(reaction.Schema(service, "reaction"))
+}
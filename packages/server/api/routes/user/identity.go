@@ -0,0 +1,343 @@
+package user
+
+import (
+	"area-server/authenticators"
+	"area-server/classes/static"
+	"area-server/db/postgres"
+	"area-server/db/postgres/models"
+	sessionr "area-server/store"
+	"area-server/utils"
+	"encoding/json"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// `StartIdentityLinkBody` is the body of the request starting the link of an external login.
+// @property {string} Authenticator - The name of the authenticator to link.
+// @property {string} RedirectURI - The redirect URI the provider will send the code to.
+type StartIdentityLinkBody struct {
+	Authenticator string `json:"authenticator" validate:"required"`
+	RedirectURI   string `json:"redirect_uri" validate:"required"`
+}
+
+// `LinkIdentityBody` is the body of the request linking an external login to the account.
+// @property {string} Authenticator - The name of the authenticator to link.
+// @property {string} Code - The code returned by the provider.
+// @property {string} RedirectURI - The redirect URI that was used to obtain the code.
+// @property {string} State - The state returned by the provider (issued by `StartIdentityLink`).
+type LinkIdentityBody struct {
+	Authenticator string `json:"authenticator" validate:"required"`
+	Code          string `json:"code" validate:"required"`
+	RedirectURI   string `json:"redirect_uri" validate:"required"`
+	State         string `json:"state" validate:"required"`
+}
+
+// It returns the logins of the account: the authenticator it was created with and the linked identities
+func GetIdentities(c *fiber.Ctx) error {
+	account := c.Locals("account").(models.Account)
+
+	identities := make([]models.Identity, 0)
+	if result := postgres.DB.Where(&models.Identity{AccountUUID: account.UUID}).Order("created_at").Find(&identities); result.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":  fiber.StatusInternalServerError,
+			"error": "Internal server error",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"code": fiber.StatusOK,
+		"data": fiber.Map{
+			"authenticator": account.Authenticator,
+			"identities":    identities,
+		},
+	})
+}
+
+// It issues a state bound to the account and returns the URI the user must be redirected to, the user
+// proves the ownership of the external login by coming back with a code
+func StartIdentityLink(c *fiber.Ctx) error {
+	account := c.Locals("account").(models.Account)
+
+	validate := validator.New()
+	body := new(StartIdentityLinkBody)
+
+	if err := c.BodyParser(body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":  fiber.StatusBadRequest,
+			"error": "Bad Request (Wrong Body)",
+		})
+	}
+
+	if err := validate.Struct(body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":  fiber.StatusBadRequest,
+			"error": "Bad Request (Invalid Body)",
+		})
+	}
+
+	authenticator := authenticators.GetAuthenticator(body.Authenticator)
+	if authenticator == nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"code":  fiber.StatusNotFound,
+			"error": "Authenticator not found",
+		})
+	}
+
+	if !authenticator.Enabled {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":  fiber.StatusBadRequest,
+			"error": "Service does not provide authentication",
+		})
+	}
+
+	uri, state, err := sessionr.BeginOAuthFlow(authenticator, sessionr.OAuthFlow{
+		RedirectURI: body.RedirectURI,
+		Purpose:     sessionr.OAuthLink,
+		Account:     account.UUID.String(),
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":  fiber.StatusInternalServerError,
+			"error": "Internal server error (Store)",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"code": fiber.StatusOK,
+		"data": fiber.Map{
+			"authorization_uri": uri,
+			"state":             state,
+		},
+	})
+}
+
+// It links the external login to the account, the account can then be logged in with the
+// authenticator. The authorization of the login is created (or renewed) and becomes permanent.
+func LinkIdentity(c *fiber.Ctx) error {
+	account := c.Locals("account").(models.Account)
+
+	validate := validator.New()
+	body := new(LinkIdentityBody)
+
+	if err := c.BodyParser(body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":  fiber.StatusBadRequest,
+			"error": "Bad Request (Wrong Body)",
+		})
+	}
+
+	if err := validate.Struct(body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":  fiber.StatusBadRequest,
+			"error": "Bad Request (Invalid Body)",
+		})
+	}
+
+	authenticator := authenticators.GetAuthenticator(body.Authenticator)
+	if authenticator == nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"code":  fiber.StatusNotFound,
+			"error": "Authenticator not found",
+		})
+	}
+
+	// Verify the state (one-time and bound to the account that started the flow)
+	flow, err := sessionr.ConsumeOAuthFlow(body.State)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":  fiber.StatusInternalServerError,
+			"error": "Internal server error (Store)",
+		})
+	}
+	if flow == nil || !flow.Matches(authenticator.Name, body.RedirectURI, sessionr.OAuthLink, account.UUID.String()) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"code":  fiber.StatusForbidden,
+			"error": "Invalid state",
+		})
+	}
+
+	// Validate service + Retrieve Email
+	fSR, fSE := authenticator.Authenticate([]interface{}{
+		true,
+		body.Code,
+		body.RedirectURI,
+		flow.Verifier,
+	})
+	if fSE != nil {
+		return c.Status(fSE.StatusCode).JSON(fiber.Map{
+			"code":  fSE.StatusCode,
+			"error": fSE.ErrorDesc,
+		})
+	}
+	email, _ := fSR.Data["email"].(string)
+	if email == "" {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"code":  fiber.StatusForbidden,
+			"error": "The provider did not return an email",
+		})
+	}
+
+	if account.Authenticator == authenticator.Name && account.Email == email {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"code":  fiber.StatusConflict,
+			"error": "The account was created with this login",
+		})
+	}
+
+	// The login must not be used by another account (an administrator can merge the accounts)
+	var identity models.Identity
+	if result := postgres.DB.Where(&models.Identity{Authenticator: authenticator.Name, Email: email}).First(&identity); result.Error == nil {
+		message := "Login already linked to another account"
+		if identity.AccountUUID == account.UUID {
+			message = "Login already linked"
+		}
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"code":  fiber.StatusConflict,
+			"error": message,
+		})
+	} else if result.Error != gorm.ErrRecordNotFound {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":  fiber.StatusInternalServerError,
+			"error": "Internal server error",
+		})
+	}
+	if result := postgres.DB.Where(&models.Account{Authenticator: authenticator.Name, Email: email}).First(&models.Account{}); result.Error == nil {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"code":  fiber.StatusConflict,
+			"error": "Login used by another account, ask an administrator to merge the accounts",
+		})
+	}
+
+	identity = models.Identity{
+		UUID:          uuid.New(),
+		AccountUUID:   account.UUID,
+		Authenticator: authenticator.Name,
+		Email:         email,
+	}
+	var authorization *models.Authorization
+	err = postgres.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&identity).Error; err != nil {
+			return err
+		}
+		var err error
+		authorization, err = linkAuthorization(tx, account, authenticator.Name, email, fSR)
+		return err
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":  fiber.StatusInternalServerError,
+			"error": "Internal server error",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"code": fiber.StatusCreated,
+		"data": fiber.Map{
+			"message":       "Login linked",
+			"identity":      identity,
+			"authorization": authorization,
+		},
+	})
+}
+
+// It creates a permanent authorization of the service with the tokens of the login, labelled after its
+// email: the login can be another account of the provider, so the existing authorizations are kept
+func linkAuthorization(tx *gorm.DB, account models.Account, authService string, email string, fSR *static.AuthenticateResponse) (*models.Authorization, error) {
+	var labels []string
+	if result := tx.Model(&models.Authorization{}).Where(&models.Authorization{
+		AccountUUID: account.UUID,
+		AuthService: authService,
+	}).Pluck("label", &labels); result.Error != nil {
+		return nil, result.Error
+	}
+	taken := make(map[string]bool, len(labels))
+	for _, label := range labels {
+		taken[label] = true
+	}
+
+	var other []byte
+	if fSR.Data["other"] != nil {
+		var err error
+		if other, err = json.Marshal(fSR.Data["other"]); err != nil {
+			return nil, err
+		}
+	}
+
+	refreshToken, _ := fSR.Data["refresh_token"].(string)
+	authorization := &models.Authorization{
+		UUID:         uuid.New(),
+		AccountUUID:  account.UUID,
+		Type:         fSR.Type,
+		AuthService:  authService,
+		Label:        utils.IdentityLabel(email, taken),
+		AccessToken:  fSR.Data["access_token"].(string),
+		RefreshToken: refreshToken,
+		ExpireAt:     fSR.Data["expired_at"].(time.Time),
+		Other:        other,
+		Permanent:    true,
+	}
+	if err := tx.Create(authorization).Error; err != nil {
+		return nil, err
+	}
+	return authorization, nil
+}
+
+// It unlinks an external login from the account, its authorization is kept but can be deleted
+func UnlinkIdentity(c *fiber.Ctx) error {
+	account := c.Locals("account").(models.Account)
+
+	id, err := uuid.Parse(c.Params("identity_id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":  fiber.StatusBadRequest,
+			"error": "Invalid identity ID",
+		})
+	}
+
+	var identity models.Identity
+	if result := postgres.DB.Where(&models.Identity{UUID: id, AccountUUID: account.UUID}).First(&identity); result.Error == gorm.ErrRecordNotFound {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"code":  fiber.StatusNotFound,
+			"error": "Identity not found",
+		})
+	} else if result.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":  fiber.StatusInternalServerError,
+			"error": "Internal server error",
+		})
+	}
+
+	err = postgres.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&identity).Error; err != nil {
+			return err
+		}
+		// The authorization stays permanent while the authenticator still logs in to the account
+		var linked int64
+		if err := tx.Model(&models.Identity{}).Where(&models.Identity{AccountUUID: account.UUID, Authenticator: identity.Authenticator}).Count(&linked).Error; err != nil {
+			return err
+		}
+		if identity.Authenticator == account.Authenticator || linked != 0 {
+			return nil
+		}
+		return tx.Model(&models.Authorization{}).
+			Where(&models.Authorization{AccountUUID: account.UUID, AuthService: identity.Authenticator, Permanent: true}).
+			Update("permanent", false).Error
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":  fiber.StatusInternalServerError,
+			"error": "Internal server error",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"code": fiber.StatusOK,
+		"data": fiber.Map{
+			"message": "Login unlinked",
+		},
+	})
+}
@@ -0,0 +1,161 @@
+package user
+
+import (
+	"area-server/db/postgres"
+	"area-server/db/postgres/models"
+	"area-server/utils"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// Maximum number of API keys per account
+const MaxAPIKeys = 20
+
+// `CreateAPIKeyBody` is the body of the request creating an API key.
+// @property {string} Name - The name of the key (e.g. "ci").
+// @property {[]string} Scopes - The scopes granted to the key (see `utils.APIKeyScopes`).
+// @property {int} ExpiresIn - The lifetime of the key in seconds (0: the key never expires).
+type CreateAPIKeyBody struct {
+	Name      string   `json:"name" validate:"required,max=64"`
+	Scopes    []string `json:"scopes" validate:"required,min=1,dive,required"`
+	ExpiresIn int      `json:"expires_in" validate:"omitempty,min=60"`
+}
+
+// It returns the API keys of the account (without the keys themselves)
+func GetAPIKeys(c *fiber.Ctx) error {
+	account := c.Locals("account").(models.Account)
+
+	keys := []models.APIKey{}
+	if result := postgres.DB.Where(&models.APIKey{AccountUUID: account.UUID}).Order("created_at").Find(&keys); result.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":  fiber.StatusInternalServerError,
+			"error": "Internal server error",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"code": fiber.StatusOK,
+		"data": fiber.Map{
+			"keys":   keys,
+			"scopes": utils.APIKeyScopes,
+		},
+	})
+}
+
+// It creates an API key, the key is only returned in this response (only its hash is stored)
+func CreateAPIKey(c *fiber.Ctx) error {
+	account := c.Locals("account").(models.Account)
+
+	validate := validator.New()
+	body := new(CreateAPIKeyBody)
+
+	if err := c.BodyParser(body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":  fiber.StatusBadRequest,
+			"error": "Bad Request (Wrong Body)",
+		})
+	}
+
+	if err := validate.Struct(body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":  fiber.StatusBadRequest,
+			"error": "Bad Request (Invalid Body)",
+		})
+	}
+
+	for _, scope := range body.Scopes {
+		if !utils.ValidAPIKeyScope(scope) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"code":  fiber.StatusBadRequest,
+				"error": "Unknown scope " + scope,
+			})
+		}
+	}
+
+	var count int64
+	if result := postgres.DB.Model(&models.APIKey{}).Where(&models.APIKey{AccountUUID: account.UUID}).Count(&count); result.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":  fiber.StatusInternalServerError,
+			"error": "Internal server error",
+		})
+	}
+	if count >= MaxAPIKeys {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"code":  fiber.StatusForbidden,
+			"error": "Too many API keys",
+		})
+	}
+
+	key, prefix, hash, err := utils.GenerateAPIKey()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":  fiber.StatusInternalServerError,
+			"error": "Internal server error",
+		})
+	}
+
+	apiKey := models.APIKey{
+		UUID:        uuid.New(),
+		AccountUUID: account.UUID,
+		Name:        body.Name,
+		Prefix:      prefix,
+		Hash:        hash,
+		Scopes:      body.Scopes,
+	}
+	if body.ExpiresIn > 0 {
+		expireAt := time.Now().Add(time.Duration(body.ExpiresIn) * time.Second)
+		apiKey.ExpireAt = &expireAt
+	}
+
+	if result := postgres.DB.Create(&apiKey); result.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":  fiber.StatusInternalServerError,
+			"error": "Internal server error",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"code": fiber.StatusCreated,
+		"data": fiber.Map{
+			"key":     key,
+			"api_key": apiKey,
+		},
+	})
+}
+
+// It revokes (deletes) an API key of the account
+func DeleteAPIKey(c *fiber.Ctx) error {
+	account := c.Locals("account").(models.Account)
+
+	id, err := uuid.Parse(c.Params("key_id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":  fiber.StatusBadRequest,
+			"error": "Invalid key ID",
+		})
+	}
+
+	result := postgres.DB.Where(&models.APIKey{UUID: id, AccountUUID: account.UUID}).Delete(&models.APIKey{})
+	if result.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":  fiber.StatusInternalServerError,
+			"error": "Internal server error",
+		})
+	}
+	if result.RowsAffected == 0 {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"code":  fiber.StatusNotFound,
+			"error": "API key not found",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"code": fiber.StatusOK,
+		"data": fiber.Map{
+			"message": "API key revoked",
+		},
+	})
+}
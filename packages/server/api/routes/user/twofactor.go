@@ -0,0 +1,271 @@
+package user
+
+import (
+	"area-server/db/postgres"
+	"area-server/db/postgres/models"
+	"area-server/utils"
+	"errors"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// `TwoFactorCodeBody` contains a code of the second factor.
+// @property {string} Code - A TOTP code (or a recovery code once enabled).
+type TwoFactorCodeBody struct {
+	Code string `json:"code" validate:"required,max=32"`
+}
+
+// It parses the code of the body and verifies it, it returns false if a response was sent
+func verifyTwoFactorCode(c *fiber.Ctx, account models.Account) (bool, error) {
+
+	validate := validator.New()
+	body := new(TwoFactorCodeBody)
+
+	if err := c.BodyParser(body); err != nil {
+		return false, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":  fiber.StatusBadRequest,
+			"error": "Bad Request (Wrong Body)",
+		})
+	}
+
+	if err := validate.Struct(body); err != nil {
+		return false, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":  fiber.StatusBadRequest,
+			"error": "Bad Request (Invalid Body)",
+		})
+	}
+
+	valid, err := utils.VerifySecondFactor(account.UUID, body.Code)
+	if err != nil {
+		return false, c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":  fiber.StatusInternalServerError,
+			"error": "Internal server error",
+		})
+	}
+	if !valid {
+		return false, c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"code":  fiber.StatusUnauthorized,
+			"error": "Invalid code",
+		})
+	}
+	return true, nil
+}
+
+// It returns the two-factor authentication of the account, or sends an error if it is not in the
+// expected state (enabled or pending)
+func findTwoFactor(c *fiber.Ctx, account models.Account, enabled bool) (*models.TwoFactor, error) {
+
+	twoFactor, err := utils.FindTwoFactor(account.UUID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":  fiber.StatusInternalServerError,
+			"error": "Internal server error",
+		})
+	}
+	if twoFactor == nil || twoFactor.Enabled != enabled {
+		message := "Two-factor authentication is not enabled"
+		if !enabled {
+			message = "No pending enrolment (or already enabled)"
+		}
+		return nil, c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"code":  fiber.StatusNotFound,
+			"error": message,
+		})
+	}
+	return twoFactor, nil
+}
+
+// It returns the state of the two-factor authentication of the account
+func GetTwoFactor(c *fiber.Ctx) error {
+
+	account := c.Locals("account").(models.Account)
+
+	twoFactor, err := utils.FindTwoFactor(account.UUID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":  fiber.StatusInternalServerError,
+			"error": "Internal server error",
+		})
+	}
+
+	enabled, remaining := false, 0
+	var enabledAt *time.Time
+	if twoFactor != nil && twoFactor.Enabled {
+		enabled, remaining, enabledAt = true, len(twoFactor.RecoveryCodes), twoFactor.EnabledAt
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"code": fiber.StatusOK,
+		"data": fiber.Map{
+			"enabled":        enabled,
+			"enabled_at":     enabledAt,
+			"recovery_codes": remaining,
+		},
+	})
+}
+
+// It starts the enrolment: a new secret is generated, the second factor is enabled once a code of the
+// authenticator app is verified
+func EnrollTwoFactor(c *fiber.Ctx) error {
+
+	account := c.Locals("account").(models.Account)
+
+	// The accounts of the external authenticators log in with the provider
+	if account.Authenticator != "@local" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"code":  fiber.StatusBadRequest,
+			"error": "Two-factor authentication is only available for the local accounts",
+		})
+	}
+
+	if twoFactor, err := utils.FindTwoFactor(account.UUID); err == nil && twoFactor.Enabled {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"code":  fiber.StatusConflict,
+			"error": "Two-factor authentication is already enabled",
+		})
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":  fiber.StatusInternalServerError,
+			"error": "Internal server error",
+		})
+	}
+
+	// A new enrolment replaces the pending one
+	if err := postgres.DB.Transaction(func(tx *gorm.DB) error {
+		if result := tx.Where(&models.TwoFactor{AccountUUID: account.UUID}).Delete(&models.TwoFactor{}); result.Error != nil {
+			return result.Error
+		}
+		return tx.Create(&models.TwoFactor{AccountUUID: account.UUID, Secret: secret}).Error
+	}); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":  fiber.StatusInternalServerError,
+			"error": "Internal server error",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"code": fiber.StatusCreated,
+		"data": fiber.Map{
+			"secret": secret,
+			"uri":    utils.TOTPProvisioningURI(utils.TOTPIssuer, account.Email, secret),
+		},
+	})
+}
+
+// It enables the second factor once a first code is verified, and returns the recovery codes (only
+// displayed once)
+func ConfirmTwoFactor(c *fiber.Ctx) error {
+
+	account := c.Locals("account").(models.Account)
+
+	twoFactor, err := findTwoFactor(c, account, false)
+	if twoFactor == nil {
+		return err
+	}
+
+	if ok, err := verifyTwoFactorCode(c, account); !ok {
+		return err
+	}
+
+	codes, hashes, err := utils.GenerateRecoveryCodes()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":  fiber.StatusInternalServerError,
+			"error": "Internal server error",
+		})
+	}
+
+	now := time.Now()
+	if result := postgres.DB.Model(twoFactor).Select("enabled", "enabled_at", "recovery_codes").Updates(&models.TwoFactor{
+		Enabled:       true,
+		EnabledAt:     &now,
+		RecoveryCodes: hashes,
+	}); result.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":  fiber.StatusInternalServerError,
+			"error": "Internal server error",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"code": fiber.StatusOK,
+		"data": fiber.Map{
+			"message":        "Two-factor authentication enabled",
+			"recovery_codes": codes,
+		},
+	})
+}
+
+// It replaces the recovery codes of the account (a valid code is required)
+func RegenerateRecoveryCodes(c *fiber.Ctx) error {
+
+	account := c.Locals("account").(models.Account)
+
+	twoFactor, err := findTwoFactor(c, account, true)
+	if twoFactor == nil {
+		return err
+	}
+
+	if ok, err := verifyTwoFactorCode(c, account); !ok {
+		return err
+	}
+
+	codes, hashes, err := utils.GenerateRecoveryCodes()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":  fiber.StatusInternalServerError,
+			"error": "Internal server error",
+		})
+	}
+
+	if result := postgres.DB.Model(twoFactor).Select("recovery_codes").Updates(&models.TwoFactor{
+		RecoveryCodes: hashes,
+	}); result.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":  fiber.StatusInternalServerError,
+			"error": "Internal server error",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"code": fiber.StatusOK,
+		"data": fiber.Map{
+			"recovery_codes": codes,
+		},
+	})
+}
+
+// It disables the second factor of the account (a valid code is required)
+func DisableTwoFactor(c *fiber.Ctx) error {
+
+	account := c.Locals("account").(models.Account)
+
+	twoFactor, err := findTwoFactor(c, account, true)
+	if twoFactor == nil {
+		return err
+	}
+
+	if ok, err := verifyTwoFactorCode(c, account); !ok {
+		return err
+	}
+
+	if result := postgres.DB.Where(&models.TwoFactor{AccountUUID: account.UUID}).Delete(&models.TwoFactor{}); result.Error != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":  fiber.StatusInternalServerError,
+			"error": "Internal server error",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"code": fiber.StatusOK,
+		"data": fiber.Map{
+			"message": "Two-factor authentication disabled",
+		},
+	})
+}
@@ -1,11 +1,10 @@
 package user
 
 import (
-	"area-server/authenticators"
+	"area-server/accounts"
 	"area-server/db/postgres"
 	"area-server/db/postgres/models"
 	sessionr "area-server/store"
-	"fmt"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
@@ -27,9 +26,25 @@ func GetUser(c *fiber.Ctx) error {
 	})
 }
 
-// It destroys the session of the user and returns a success message
+// It destroys the session of the user (or revokes its token and the refresh token issued with it) and
+// returns a success message
 func LogoutUser(c *fiber.Ctx) error {
 
+	if claims, ok := c.Locals("claims").(map[string]interface{}); ok {
+		if err := sessionr.RevokeToken(claims); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"code":  fiber.StatusInternalServerError,
+				"error": "Internal server error",
+			})
+		}
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"code": fiber.StatusOK,
+			"data": fiber.Map{
+				"message": "User logged out",
+			},
+		})
+	}
+
 	if c.Locals("session") == nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 			"code":  fiber.StatusNotFound,
@@ -53,6 +68,30 @@ func LogoutUser(c *fiber.Ctx) error {
 	})
 }
 
+// It revokes all the sessions and tokens of the user issued until now ("log out everywhere")
+func LogoutUserEverywhere(c *fiber.Ctx) error {
+
+	account := c.Locals("account").(models.Account)
+
+	if err := sessionr.RevokeAccount(account.UUID); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":  fiber.StatusInternalServerError,
+			"error": "Internal server error",
+		})
+	}
+
+	if current, ok := c.Locals("session").(*session.Session); ok {
+		sessionr.DestroySession(current)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"code": fiber.StatusOK,
+		"data": fiber.Map{
+			"message": "User logged out everywhere",
+		},
+	})
+}
+
 // `UpdateUserBody` is a struct with a single field, `Username`, which is a string.
 //
 // The `json:"username"` part is called a struct tag. It tells the JSON encoder/decoder how to map the
@@ -109,32 +148,12 @@ func UpdateUser(c *fiber.Ctx) error {
 	})
 }
 
-// It deletes the user's account, revokes all of their tokens, and destroys their session
+// It schedules the deletion of the user's account (its applets are stopped, its tokens revoked and its
+// files deleted in the background), and destroys their session
 func DeleteUser(c *fiber.Ctx) error {
 	account := c.Locals("account").(models.Account)
 
-	var Authorization []models.Authorization
-	if result := postgres.DB.Where(&models.Authorization{
-		AccountUUID: account.UUID,
-	}).Find(&Authorization); result.Error != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"code":  fiber.StatusInternalServerError,
-			"error": "Internal server error",
-		})
-	}
-
-	for _, authorization := range Authorization {
-		authenticator := authenticators.GetAuthenticator(authorization.AuthService)
-		if authenticator == nil || authenticator.AuthEndpoints.RevokeToken == nil {
-			continue
-		}
-		_, _, err := authenticator.AuthEndpoints.RevokeToken.CallEncode([]interface{}{authorization})
-		if err != nil {
-			fmt.Println("Could not revoke token for: ", authorization.AuthService)
-		}
-	}
-
-	if result := postgres.DB.Delete(&account); result.Error != nil {
+	if err := accounts.ScheduleTeardown(account); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"code":  fiber.StatusInternalServerError,
 			"error": "Internal server error",
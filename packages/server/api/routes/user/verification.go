@@ -0,0 +1,50 @@
+package user
+
+import (
+	"area-server/db/postgres/models"
+	sessionr "area-server/store"
+	"area-server/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// It sends a new verification link to the address of the account
+func SendVerificationEmail(c *fiber.Ctx) error {
+
+	account := c.Locals("account").(models.Account)
+
+	if account.EmailVerified {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"code":  fiber.StatusConflict,
+			"error": "Email already verified",
+		})
+	}
+
+	allowed, err := sessionr.AllowMail(utils.EmailVerificationToken, account.Email)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":  fiber.StatusInternalServerError,
+			"error": "Internal server error",
+		})
+	}
+	if !allowed {
+		return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+			"code":  fiber.StatusTooManyRequests,
+			"error": "A mail was sent recently, retry later",
+		})
+	}
+
+	if err := utils.SendVerificationEmail(&account); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"code":  fiber.StatusInternalServerError,
+			"error": "Internal server error",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"code": fiber.StatusOK,
+		"data": fiber.Map{
+			"message": "Verification mail sent",
+		},
+	})
+}
@@ -1,27 +1,82 @@
 package authenticators
 
 import (
+	"area-server/authenticators/declarative"
 	"area-server/authenticators/oauth2"
 	"area-server/classes/static"
+	"embed"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
 )
 
-// It's a list of all the authenticators.
-var List []static.OAuth2Authenticator = []static.OAuth2Authenticator{
-	oauth2.DiscordAuthenticator(),
-	oauth2.DropboxAuthenticator(),
-	oauth2.FacebookAuthenticator(),
-	oauth2.GithubAuthenticator(),
-	oauth2.GoogleAuthenticator(),
-	oauth2.MicrosoftAuthenticator(),
-	oauth2.RedditAuthenticator(),
-	oauth2.SpotifyAuthenticator(),
-	oauth2.TwitchAuthenticator(),
+// The declarative authenticators shipped with the server (see authenticators/declarative)
+//
+//go:embed definitions
+var definitions embed.FS
+
+var (
+	list     []static.OAuth2Authenticator
+	listOnce sync.Once
+)
+
+// It returns the declarative authenticators: the embedded ones and the ones of the directory
+// AREA_AUTHENTICATORS_DIR (e.g. to add a provider without rebuilding the server)
+func loadDefinitions() ([]*declarative.Definition, error) {
+	loaded, err := declarative.Load(definitions, "definitions")
+	if err != nil {
+		return nil, err
+	}
+	if dir := os.Getenv("AREA_AUTHENTICATORS_DIR"); dir != "" {
+		extra, err := declarative.Load(os.DirFS(dir), ".")
+		if err != nil {
+			return nil, err
+		}
+		loaded = append(loaded, extra...)
+	}
+	return loaded, nil
+}
+
+// It returns the list of all the authenticators, they are loaded on first use so the packages
+// importing them (e.g. the services) can be tested without the OAuth2 credentials. The providers with
+// quirks are written in Go (authenticators/oauth2), the others are declarative.
+func List() []static.OAuth2Authenticator {
+	listOnce.Do(func() {
+		list = []static.OAuth2Authenticator{
+			oauth2.DiscordAuthenticator(),
+			oauth2.FacebookAuthenticator(),
+			oauth2.GithubAuthenticator(),
+			oauth2.RedditAuthenticator(),
+		}
+
+		loaded, err := loadDefinitions()
+		if err != nil {
+			panic(err)
+		}
+		names := make(map[string]bool)
+		for _, authenticator := range list {
+			names[authenticator.Name] = true
+		}
+		for _, definition := range loaded {
+			if names[definition.Name] {
+				panic(fmt.Sprintf("Authenticator %s is defined twice", definition.Name))
+			}
+			authenticator, err := definition.Authenticator()
+			if err != nil {
+				panic(err)
+			}
+			names[definition.Name] = true
+			list = append(list, authenticator)
+		}
+		sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	})
+	return list
 }
 
-// TODO: Add "state" parameter to all authenticators
 // It returns the authenticator with the given name
 func GetAuthenticator(name string) *static.OAuth2Authenticator {
-	for _, authenticator := range List {
+	for _, authenticator := range List() {
 		if authenticator.Name == name {
 			return &authenticator
 		}
@@ -0,0 +1,69 @@
+package authenticators
+
+import (
+	"area-server/classes/static"
+	"area-server/config"
+	"area-server/db/postgres"
+	"area-server/db/postgres/models"
+	"context"
+	"log"
+	"time"
+)
+
+// It checks the authorizations that were not checked since `every` or whose last check failed (at
+// most `batch`), the revoked ones are marked with `NeedsReauth` with their applets and their owner is
+// notified (see `static.MarkReauthRequired`)
+func checkAuthorizations(every time.Duration, batch int) {
+	var authorizations []models.Authorization
+	if result := postgres.DB.Where(
+		"type = ? AND needs_reauth = ? AND (checked_at IS NULL OR checked_at < ? OR health = ?)", "oauth2", false, time.Now().Add(-every), models.HealthError,
+	).Order("checked_at NULLS FIRST").Limit(batch).Find(&authorizations); result.Error != nil {
+		log.Printf("[Health] Could not list the authorizations: %s", result.Error.Error())
+		return
+	}
+
+	for i := range authorizations {
+		authorization := &authorizations[i]
+
+		health, message := models.HealthUnknown, ""
+		if authenticator := GetAuthenticator(authorization.AuthService); authenticator != nil {
+			var err error
+			if health, err = authenticator.CheckHealth(authorization); err != nil {
+				message = err.Error()
+			}
+		}
+
+		if err := static.SaveHealth(authorization, health, message, time.Now()); err != nil {
+			log.Printf("[Health] Could not save the health of %s: %s", authorization.UUID, err.Error())
+			continue
+		}
+
+		switch health {
+		case models.HealthNeedsReconnect:
+			log.Printf("[Health] Authorization %s (%s) must be reconnected", authorization.UUID, authorization.AuthService)
+		case models.HealthError:
+			log.Printf("[Health] Checking %s (%s) failed: %s", authorization.UUID, authorization.AuthService, message)
+		}
+	}
+}
+
+// StartHealthChecker validates in the background the tokens of the authorizations with their provider,
+// so the applets using a revoked authorization are marked before they fail, until the context is done
+func StartHealthChecker(ctx context.Context) {
+	interval := time.Duration(config.CFG.HealthCheck.Interval) * time.Second
+	every := time.Duration(config.CFG.HealthCheck.Every) * time.Second
+	if interval <= 0 || config.CFG.HealthCheck.Batch <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		checkAuthorizations(every, config.CFG.HealthCheck.Batch)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
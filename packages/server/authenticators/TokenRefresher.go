@@ -0,0 +1,60 @@
+package authenticators
+
+import (
+	"area-server/classes/static"
+	"area-server/config"
+	"area-server/db/postgres"
+	"area-server/db/postgres/models"
+	"context"
+	"errors"
+	"log"
+	"time"
+)
+
+// It refreshes the authorizations expiring in the next `ahead`, so the triggers rarely have to refresh
+// a token themselves
+func refreshExpiring(ahead time.Duration) {
+	var authorizations []models.Authorization
+	if result := postgres.DB.Where(
+		"type = ? AND needs_reauth = ? AND expire_at < ?", "oauth2", false, time.Now().Add(ahead),
+	).Find(&authorizations); result.Error != nil {
+		log.Printf("[Tokens] Could not list the authorizations: %s", result.Error.Error())
+		return
+	}
+
+	for i := range authorizations {
+		authorization := &authorizations[i]
+		authenticator := GetAuthenticator(authorization.AuthService)
+		if authenticator == nil || authenticator.AuthEndpoints.RefreshToken == nil {
+			continue
+		}
+		if _, err := authenticator.RefreshTokenAhead(authorization, ahead); err != nil {
+			if errors.Is(err, static.ErrReauthRequired) {
+				log.Printf("[Tokens] Authorization %s (%s) must be renewed", authorization.UUID, authorization.AuthService)
+				continue
+			}
+			log.Printf("[Tokens] Refreshing %s (%s) failed: %s", authorization.UUID, authorization.AuthService, err.Error())
+		}
+	}
+}
+
+// StartTokenRefresher refreshes in the background the tokens before they expire, until the context
+// is done
+func StartTokenRefresher(ctx context.Context) {
+	interval := time.Duration(config.CFG.Refresher.Interval) * time.Second
+	ahead := time.Duration(config.CFG.Refresher.Ahead) * time.Second
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		refreshExpiring(ahead)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
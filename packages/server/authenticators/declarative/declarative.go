@@ -0,0 +1,487 @@
+package declarative
+
+import (
+	"area-server/classes/static"
+	"area-server/db/postgres/models"
+	"area-server/utils"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+/*
+ * Declarative OAuth2 authenticators, described in YAML (or JSON) instead of Go.
+ *
+ * Example (GitLab):
+ *
+ * name: gitlab
+ * enabled: true                        # Can be used to log in (needs the email endpoint)
+ * color: "#FC6D26"
+ * pkce: true
+ * client_id: ${GITLAB_CLIENT_ID}       # ${VAR} is replaced by the environment variable (required)
+ * client_secret: ${GITLAB_SECRET_ID}
+ * auth_style: body                     # Client credentials of the token requests: body | basic
+ * authorization_uri: https://gitlab.com/oauth/authorize
+ * authorization_params: {prompt: consent}
+ * scopes: [read_user, api]
+ * endpoints:
+ *   access_token: {url: https://gitlab.com/oauth/token}
+ *   refresh_token: {url: https://gitlab.com/oauth/token}
+ *   revoke_token: {url: https://gitlab.com/oauth/revoke, params: {client_id: "${client_id}"}}
+ *   email: {url: https://gitlab.com/api/v4/user}
+ *   profile: {url: https://gitlab.com/api/v4/user, fields: {login: username, id: id}}
+ * other_params: {login: login, id: id}  # Saved with the authorization (path in the responses)
+ *
+ * The token endpoints (access_token, refresh_token) send the standard form of the grant, the other
+ * endpoints send the access token (`token`: bearer | body | query | none, bearer by default and body
+ * for revoke_token). `params` are added to the body (or the query for GET), `headers` to the request,
+ * ${client_id} and ${client_secret} can be used in both. `fields` maps the keys of the response to
+ * their path ("emails.0.address"), the response is kept as is without it.
+ */
+
+// Ways of sending the access token to an endpoint
+const (
+	TokenBearer = "bearer"
+	TokenBody   = "body"
+	TokenQuery  = "query"
+	TokenNone   = "none"
+)
+
+// `Endpoint` describes a request of the authenticator.
+// @property {string} URL - The URL of the endpoint.
+// @property {string} Method - The HTTP method (POST for the token endpoints, GET otherwise).
+// @property Headers - The headers added to the request.
+// @property Params - The parameters added to the body (or the query of a GET request).
+// @property {string} Token - How the access token is sent (bearer, body, query or none).
+// @property {string} TokenParam - The name of the parameter of the token (body or query).
+// @property Fields - The keys of the result and their path in the response.
+// @property {[]int} Status - The expected status codes (200 by default).
+type Endpoint struct {
+	URL        string            `json:"url" yaml:"url"`
+	Method     string            `json:"method" yaml:"method"`
+	Headers    map[string]string `json:"headers" yaml:"headers"`
+	Params     map[string]string `json:"params" yaml:"params"`
+	Token      string            `json:"token" yaml:"token"`
+	TokenParam string            `json:"token_param" yaml:"token_param"`
+	Fields     map[string]string `json:"fields" yaml:"fields"`
+	Status     []int             `json:"status" yaml:"status"`
+}
+
+// `Endpoints` are the endpoints of the OAuth2 flow, only `AccessToken` is required.
+type Endpoints struct {
+	AccessToken   *Endpoint `json:"access_token" yaml:"access_token"`
+	RefreshToken  *Endpoint `json:"refresh_token" yaml:"refresh_token"`
+	RevokeToken   *Endpoint `json:"revoke_token" yaml:"revoke_token"`
+	ValidateToken *Endpoint `json:"validate_token" yaml:"validate_token"`
+	Profile       *Endpoint `json:"profile" yaml:"profile"`
+	Email         *Endpoint `json:"email" yaml:"email"`
+}
+
+// `Definition` is an authenticator described in a file.
+// @property {string} Name - The name of the authenticator.
+// @property {bool} Enabled - If true, the authenticator can be used to log in.
+// @property {string} Color - The color of the authenticator in the clients.
+// @property {bool} PKCE - If true, the provider supports PKCE (S256).
+// @property {string} ClientID - The client ID (usually `${<PROVIDER>_CLIENT_ID}`).
+// @property {string} ClientSecret - The client secret (usually `${<PROVIDER>_SECRET_ID}`).
+// @property {string} AuthStyle - How the client credentials are sent to the token endpoints.
+// @property {string} AuthorizationURI - The authorization endpoint, without the query.
+// @property AuthorizationParams - The parameters added to the authorization URI.
+// @property {[]string} Scopes - The scopes requested.
+// @property {string} ScopeSeparator - The separator of the scopes (a space by default).
+// @property {Endpoints} Endpoints - The endpoints of the flow.
+// @property OtherParams - The values saved with the authorization and their path in the responses.
+type Definition struct {
+	Name                string            `json:"name" yaml:"name"`
+	Enabled             bool              `json:"enabled" yaml:"enabled"`
+	Color               string            `json:"color" yaml:"color"`
+	PKCE                bool              `json:"pkce" yaml:"pkce"`
+	ClientID            string            `json:"client_id" yaml:"client_id"`
+	ClientSecret        string            `json:"client_secret" yaml:"client_secret"`
+	AuthStyle           string            `json:"auth_style" yaml:"auth_style"`
+	AuthorizationURI    string            `json:"authorization_uri" yaml:"authorization_uri"`
+	AuthorizationParams map[string]string `json:"authorization_params" yaml:"authorization_params"`
+	Scopes              []string          `json:"scopes" yaml:"scopes"`
+	ScopeSeparator      string            `json:"scope_separator" yaml:"scope_separator"`
+	Endpoints           Endpoints         `json:"endpoints" yaml:"endpoints"`
+	OtherParams         map[string]string `json:"other_params" yaml:"other_params"`
+}
+
+// Parse reads a definition, in JSON if the file name ends with `.json` and in YAML otherwise
+func Parse(name string, data []byte) (*Definition, error) {
+	definition := new(Definition)
+	var err error
+	if strings.HasSuffix(name, ".json") {
+		err = json.Unmarshal(data, definition)
+	} else {
+		err = yaml.Unmarshal(data, definition)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return definition, nil
+}
+
+// Load reads the definitions (.yaml, .yml and .json files) of a directory
+func Load(fsys fs.FS, dir string) ([]*Definition, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var definitions []*Definition
+	for _, entry := range entries {
+		ext := path.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml" && ext != ".json") {
+			continue
+		}
+		data, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		definition, err := Parse(entry.Name(), data)
+		if err != nil {
+			return nil, err
+		}
+		definitions = append(definitions, definition)
+	}
+	return definitions, nil
+}
+
+// It replaces ${VAR} by the environment variable (an error is returned if it is not set), and
+// ${client_id} / ${client_secret} by the credentials if they are given
+func expand(value string, credentials map[string]string, missing *[]string) string {
+	return os.Expand(value, func(name string) string {
+		if credential, ok := credentials[name]; ok {
+			return credential
+		}
+		env, present := os.LookupEnv(name)
+		if !present {
+			*missing = append(*missing, name)
+		}
+		return env
+	})
+}
+
+// It expands the values of a map
+func expandMap(values map[string]string, credentials map[string]string, missing *[]string) map[string]string {
+	expanded := make(map[string]string, len(values))
+	for key, value := range values {
+		expanded[key] = expand(value, credentials, missing)
+	}
+	return expanded
+}
+
+// Lookup returns the value at a path ("a.0.b") of a decoded JSON response
+func Lookup(value interface{}, path string) (interface{}, bool) {
+	if path == "" {
+		return value, true
+	}
+	for _, key := range strings.Split(path, ".") {
+		switch current := value.(type) {
+		case map[string]interface{}:
+			next, ok := current[key]
+			if !ok {
+				return nil, false
+			}
+			value = next
+		case []interface{}:
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || index >= len(current) {
+				return nil, false
+			}
+			value = current[index]
+		default:
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// It returns the function extracting the fields of a response (nil if the response is kept as is)
+func transform(fields map[string]string) func(response any) (map[string]interface{}, error) {
+	if len(fields) == 0 {
+		return nil
+	}
+	return func(response any) (map[string]interface{}, error) {
+		result := make(map[string]interface{}, len(fields))
+		for key, path := range fields {
+			if value, ok := Lookup(response, path); ok {
+				result[key] = value
+			}
+		}
+		return result, nil
+	}
+}
+
+// It returns the access token of the params of an endpoint (the oauth fields or an authorization)
+func accessToken(params []interface{}) string {
+	if len(params) == 0 {
+		return ""
+	}
+	switch param := params[0].(type) {
+	case map[string]interface{}:
+		token, _ := param["access_token"].(string)
+		return token
+	case models.Authorization:
+		return param.AccessToken
+	case *models.Authorization:
+		return param.AccessToken
+	}
+	return ""
+}
+
+// It copies a map of strings
+func copyMap(values map[string]string) map[string]string {
+	copied := make(map[string]string, len(values))
+	for key, value := range values {
+		copied[key] = value
+	}
+	return copied
+}
+
+// It builds the params of a request: the body is a form (or the query for GET)
+func buildParams(method string, headers map[string]string, body map[string]string) *utils.RequestParams {
+	p := &utils.RequestParams{Method: method, Headers: copyMap(headers)}
+	if method == "GET" {
+		p.QueryParams = copyMap(body)
+		return p
+	}
+	if len(body) > 0 {
+		values := url.Values{}
+		for key, value := range body {
+			values.Set(key, value)
+		}
+		p.Body = values.Encode()
+		p.Headers["Content-Type"] = "application/x-www-form-urlencoded"
+	}
+	return p
+}
+
+// It builds the descriptor of a token endpoint (grant of the access token or of the refresh token)
+func (d *Definition) tokenDescriptor(endpoint *Endpoint, grant string, credentials map[string]string, missing *[]string) *utils.RequestDescriptor {
+	method := strings.ToUpper(endpoint.Method)
+	if method == "" {
+		method = "POST"
+	}
+	headers := expandMap(endpoint.Headers, credentials, missing)
+	if _, ok := headers["Accept"]; !ok {
+		headers["Accept"] = "application/json"
+	}
+	extra := expandMap(endpoint.Params, credentials, missing)
+	if d.AuthStyle == "basic" {
+		headers["Authorization"] = "Basic " + base64.StdEncoding.EncodeToString([]byte(credentials["client_id"]+":"+credentials["client_secret"]))
+	}
+
+	return &utils.RequestDescriptor{
+		BaseURL:        endpoint.URL,
+		ExpectedStatus: expectedStatus(endpoint),
+		Params: func(params []interface{}) *utils.RequestParams {
+			body := copyMap(extra)
+			body["grant_type"] = grant
+			if grant == "authorization_code" {
+				body["code"], _ = params[0].(string)
+				body["redirect_uri"], _ = params[1].(string)
+			} else {
+				body["refresh_token"], _ = params[0].(string)
+			}
+			if d.AuthStyle != "basic" {
+				body["client_id"] = credentials["client_id"]
+				body["client_secret"] = credentials["client_secret"]
+			}
+			return buildParams(method, headers, body)
+		},
+		TransformResponse: transform(endpoint.Fields),
+	}
+}
+
+// It builds the descriptor of an endpoint called with the access token
+func (d *Definition) descriptor(endpoint *Endpoint, token string, credentials map[string]string, missing *[]string) *utils.RequestDescriptor {
+	if endpoint == nil {
+		return nil
+	}
+	if endpoint.Token != "" {
+		token = endpoint.Token
+	}
+	method := strings.ToUpper(endpoint.Method)
+	if method == "" {
+		method = "GET"
+		if token == TokenBody {
+			method = "POST"
+		}
+	}
+	tokenParam := endpoint.TokenParam
+	if tokenParam == "" {
+		tokenParam = "token"
+		if token == TokenQuery {
+			tokenParam = "access_token"
+		}
+	}
+	headers := expandMap(endpoint.Headers, credentials, missing)
+	if _, ok := headers["Accept"]; !ok {
+		headers["Accept"] = "application/json"
+	}
+	extra := expandMap(endpoint.Params, credentials, missing)
+
+	return &utils.RequestDescriptor{
+		BaseURL:        endpoint.URL,
+		ExpectedStatus: expectedStatus(endpoint),
+		Params: func(params []interface{}) *utils.RequestParams {
+			body := copyMap(extra)
+			requestHeaders := copyMap(headers)
+			var query map[string]string
+			switch token {
+			case TokenBearer:
+				requestHeaders["Authorization"] = "Bearer " + accessToken(params)
+			case TokenBody:
+				body[tokenParam] = accessToken(params)
+			case TokenQuery:
+				query = map[string]string{tokenParam: accessToken(params)}
+			}
+			p := buildParams(method, requestHeaders, body)
+			for key, value := range query {
+				if p.QueryParams == nil {
+					p.QueryParams = make(map[string]string)
+				}
+				p.QueryParams[key] = value
+			}
+			return p
+		},
+		TransformResponse: transform(endpoint.Fields),
+	}
+}
+
+// It returns the expected status codes of an endpoint
+func expectedStatus(endpoint *Endpoint) []int {
+	if len(endpoint.Status) == 0 {
+		return []int{200}
+	}
+	return endpoint.Status
+}
+
+// It returns the authorization URI with the client ID, the scopes and the other parameters
+func (d *Definition) authorizationURI(credentials map[string]string, missing *[]string) (string, error) {
+	u, err := url.Parse(d.AuthorizationURI)
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	query.Set("client_id", credentials["client_id"])
+	query.Set("response_type", "code")
+	if len(d.Scopes) > 0 {
+		separator := d.ScopeSeparator
+		if separator == "" {
+			separator = " "
+		}
+		query.Set("scope", strings.Join(d.Scopes, separator))
+	}
+	for key, value := range expandMap(d.AuthorizationParams, credentials, missing) {
+		query.Set(key, value)
+	}
+	// The spaces are encoded with %20, some providers do not decode the "+" of the scopes
+	u.RawQuery = strings.ReplaceAll(query.Encode(), "+", "%20")
+	return u.String(), nil
+}
+
+// Validate checks the definition before building it
+func (d *Definition) Validate() error {
+	switch {
+	case d.Name == "":
+		return errors.New("Authenticator: name is required")
+	case d.AuthorizationURI == "":
+		return fmt.Errorf("Authenticator %s: authorization_uri is required", d.Name)
+	case d.Endpoints.AccessToken == nil || d.Endpoints.AccessToken.URL == "":
+		return fmt.Errorf("Authenticator %s: endpoints.access_token is required", d.Name)
+	case d.Enabled && d.Endpoints.Email == nil:
+		return fmt.Errorf("Authenticator %s: endpoints.email is required to log in", d.Name)
+	case d.AuthStyle != "" && d.AuthStyle != "body" && d.AuthStyle != "basic":
+		return fmt.Errorf("Authenticator %s: unknown auth_style %q", d.Name, d.AuthStyle)
+	}
+	for _, endpoint := range []*Endpoint{d.Endpoints.RefreshToken, d.Endpoints.RevokeToken, d.Endpoints.ValidateToken, d.Endpoints.Profile, d.Endpoints.Email} {
+		if endpoint == nil {
+			continue
+		}
+		if endpoint.URL == "" {
+			return fmt.Errorf("Authenticator %s: an endpoint has no url", d.Name)
+		}
+		switch endpoint.Token {
+		case "", TokenBearer, TokenBody, TokenQuery, TokenNone:
+		default:
+			return fmt.Errorf("Authenticator %s: unknown token %q", d.Name, endpoint.Token)
+		}
+	}
+	return nil
+}
+
+// Authenticator builds the authenticator, the environment variables used by the definition must be set
+func (d *Definition) Authenticator() (static.OAuth2Authenticator, error) {
+	if err := d.Validate(); err != nil {
+		return static.OAuth2Authenticator{}, err
+	}
+
+	var missing []string
+	credentials := map[string]string{
+		"client_id":     expand(d.ClientID, nil, &missing),
+		"client_secret": expand(d.ClientSecret, nil, &missing),
+	}
+
+	uri, err := d.authorizationURI(credentials, &missing)
+	if err != nil {
+		return static.OAuth2Authenticator{}, fmt.Errorf("Authenticator %s: %w", d.Name, err)
+	}
+
+	endpoints := static.AuthEndpoints{
+		AccessToken:   *d.tokenDescriptor(d.Endpoints.AccessToken, "authorization_code", credentials, &missing),
+		RevokeToken:   d.descriptor(d.Endpoints.RevokeToken, TokenBody, credentials, &missing),
+		ValidateToken: d.descriptor(d.Endpoints.ValidateToken, TokenBearer, credentials, &missing),
+		Profile:       d.descriptor(d.Endpoints.Profile, TokenBearer, credentials, &missing),
+		Email:         d.descriptor(d.Endpoints.Email, TokenBearer, credentials, &missing),
+	}
+	if d.Endpoints.RefreshToken != nil {
+		endpoints.RefreshToken = d.tokenDescriptor(d.Endpoints.RefreshToken, "refresh_token", credentials, &missing)
+	}
+
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return static.OAuth2Authenticator{}, fmt.Errorf("%s is not set", strings.Join(missing, ", "))
+	}
+
+	color := d.Color
+	if color == "" {
+		color = "#000000"
+	}
+
+	authenticator := static.OAuth2Authenticator{
+		Name:    d.Name,
+		Enabled: d.Enabled,
+		More: static.More{
+			Avatar: true,
+			Color:  color,
+		},
+		AuthorizationURI: uri,
+		PKCE:             d.PKCE,
+		AuthEndpoints:    endpoints,
+	}
+
+	if len(d.OtherParams) > 0 {
+		other := d.OtherParams
+		authenticator.OtherParams = func(m map[string]interface{}) map[string]interface{} {
+			params := make(map[string]interface{}, len(other))
+			for key, path := range other {
+				params[key], _ = Lookup(m, path)
+			}
+			return params
+		}
+	}
+	return authenticator, nil
+}
@@ -0,0 +1,182 @@
+package declarative
+
+import (
+	"area-server/db/postgres/models"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+const gitlab = `
+name: gitlab
+enabled: true
+pkce: true
+client_id: ${TEST_GITLAB_CLIENT_ID}
+client_secret: ${TEST_GITLAB_SECRET_ID}
+authorization_uri: https://gitlab.com/oauth/authorize
+authorization_params: {prompt: consent}
+scopes: [read_user, api]
+endpoints:
+  access_token: {url: https://gitlab.com/oauth/token}
+  refresh_token: {url: https://gitlab.com/oauth/token}
+  revoke_token: {url: https://gitlab.com/oauth/revoke, params: {client_id: "${client_id}"}}
+  email: {url: https://gitlab.com/api/v4/user, fields: {email: emails.0.address}}
+  profile: {url: https://gitlab.com/api/v4/user, token: query}
+other_params: {login: username}
+`
+
+func TestDefinitionAuthenticator(t *testing.T) {
+	definition, err := Parse("gitlab.yaml", []byte(gitlab))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := definition.Authenticator(); err == nil || !strings.Contains(err.Error(), "TEST_GITLAB_CLIENT_ID") {
+		t.Fatalf("expected the missing variables to be reported, got %v", err)
+	}
+
+	t.Setenv("TEST_GITLAB_CLIENT_ID", "id")
+	t.Setenv("TEST_GITLAB_SECRET_ID", "secret")
+	authenticator, err := definition.Authenticator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if authenticator.Name != "gitlab" || !authenticator.Enabled || !authenticator.PKCE {
+		t.Errorf("unexpected authenticator %+v", authenticator)
+	}
+	if authenticator.AuthorizationURI != "https://gitlab.com/oauth/authorize?client_id=id&prompt=consent&response_type=code&scope=read_user%20api" {
+		t.Errorf("unexpected authorization URI %s", authenticator.AuthorizationURI)
+	}
+
+	// Access token: credentials in the body
+	p := authenticator.AuthEndpoints.AccessToken.Params([]interface{}{"code", "http://localhost/cb"})
+	body, _ := url.ParseQuery(p.Body)
+	if p.Method != "POST" || body.Get("grant_type") != "authorization_code" || body.Get("code") != "code" ||
+		body.Get("redirect_uri") != "http://localhost/cb" || body.Get("client_secret") != "secret" {
+		t.Errorf("unexpected access token request %+v", p)
+	}
+
+	// Refresh token
+	p = authenticator.AuthEndpoints.RefreshToken.Params([]interface{}{"refresh"})
+	body, _ = url.ParseQuery(p.Body)
+	if body.Get("grant_type") != "refresh_token" || body.Get("refresh_token") != "refresh" {
+		t.Errorf("unexpected refresh request %+v", p)
+	}
+
+	// Revoke: token in the body with the expanded client ID
+	p = authenticator.AuthEndpoints.RevokeToken.Params([]interface{}{models.Authorization{AccessToken: "access"}})
+	body, _ = url.ParseQuery(p.Body)
+	if p.Method != "POST" || body.Get("token") != "access" || body.Get("client_id") != "id" {
+		t.Errorf("unexpected revoke request %+v", p)
+	}
+
+	// Email: bearer token and fields
+	oauth := map[string]interface{}{"access_token": "access"}
+	p = authenticator.AuthEndpoints.Email.Params([]interface{}{oauth})
+	if p.Method != "GET" || p.Headers["Authorization"] != "Bearer access" {
+		t.Errorf("unexpected email request %+v", p)
+	}
+	email, _ := authenticator.AuthEndpoints.Email.TransformResponse(map[string]interface{}{
+		"emails": []interface{}{map[string]interface{}{"address": "user@example.com"}},
+	})
+	if email["email"] != "user@example.com" {
+		t.Errorf("unexpected email %v", email)
+	}
+
+	// Profile: token in the query
+	p = authenticator.AuthEndpoints.Profile.Params([]interface{}{oauth})
+	if p.QueryParams["access_token"] != "access" || p.Headers["Authorization"] != "" {
+		t.Errorf("unexpected profile request %+v", p)
+	}
+
+	if other := authenticator.OtherParams(map[string]interface{}{"username": "user"}); other["login"] != "user" {
+		t.Errorf("unexpected other params %v", other)
+	}
+}
+
+func TestDefinitionBasicAuth(t *testing.T) {
+	definition, err := Parse("basic.json", []byte(`{
+		"name": "basic",
+		"client_id": "id",
+		"client_secret": "secret",
+		"auth_style": "basic",
+		"authorization_uri": "https://example.com/authorize",
+		"endpoints": {"access_token": {"url": "https://example.com/token"}}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	authenticator, err := definition.Authenticator()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := authenticator.AuthEndpoints.AccessToken.Params([]interface{}{"code", "uri"})
+	body, _ := url.ParseQuery(p.Body)
+	if p.Headers["Authorization"] != "Basic aWQ6c2VjcmV0" || body.Get("client_secret") != "" {
+		t.Errorf("expected the credentials in the header, got %+v", p)
+	}
+	if authenticator.AuthEndpoints.RefreshToken != nil || authenticator.OtherParams != nil {
+		t.Error("expected the optional endpoints to be nil")
+	}
+}
+
+func TestDefinitionTokenInBody(t *testing.T) {
+	definition, err := Parse("body.json", []byte(`{
+		"name": "body",
+		"client_id": "id",
+		"client_secret": "secret",
+		"authorization_uri": "https://example.com/authorize",
+		"endpoints": {
+			"access_token": {"url": "https://example.com/token"},
+			"validate_token": {"url": "https://example.com/introspect", "token": "body"}
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	authenticator, err := definition.Authenticator()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The token sent in the body makes the default method POST, it is never in the query
+	p := authenticator.AuthEndpoints.ValidateToken.Params([]interface{}{map[string]interface{}{"access_token": "access"}})
+	body, _ := url.ParseQuery(p.Body)
+	if p.Method != "POST" || body.Get("token") != "access" || p.QueryParams["token"] != "" || p.Headers["Authorization"] != "" {
+		t.Errorf("expected the token in the body of a POST, got %+v", p)
+	}
+}
+
+func TestDefinitionValidate(t *testing.T) {
+	for _, invalid := range []string{
+		`{"authorization_uri": "https://example.com", "endpoints": {"access_token": {"url": "https://example.com"}}}`,
+		`{"name": "a", "endpoints": {"access_token": {"url": "https://example.com"}}}`,
+		`{"name": "a", "authorization_uri": "https://example.com"}`,
+		`{"name": "a", "enabled": true, "authorization_uri": "https://example.com", "endpoints": {"access_token": {"url": "https://example.com"}}}`,
+		`{"name": "a", "auth_style": "header", "authorization_uri": "https://example.com", "endpoints": {"access_token": {"url": "https://example.com"}}}`,
+		`{"name": "a", "authorization_uri": "https://example.com", "endpoints": {"access_token": {"url": "https://example.com"}, "profile": {"url": "https://example.com", "token": "cookie"}}}`,
+	} {
+		definition, err := Parse("invalid.json", []byte(invalid))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := definition.Validate(); err == nil {
+			t.Errorf("expected %s to be invalid", invalid)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	value := map[string]interface{}{
+		"a": []interface{}{map[string]interface{}{"b": "c"}},
+	}
+	if found, ok := Lookup(value, "a.0.b"); !ok || found != "c" {
+		t.Errorf("expected c, got %v", found)
+	}
+	for _, path := range []string{"a.1.b", "a.x", "b", "a.0.b.c"} {
+		if _, ok := Lookup(value, path); ok {
+			t.Errorf("expected %s to be missing", path)
+		}
+	}
+}
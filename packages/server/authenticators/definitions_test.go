@@ -0,0 +1,35 @@
+package authenticators
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+// The definitions shipped with the server must be valid
+func TestEmbeddedDefinitions(t *testing.T) {
+	loaded, err := loadDefinitions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded) == 0 {
+		t.Fatal("expected embedded definitions")
+	}
+
+	for _, definition := range loaded {
+		for _, value := range []string{definition.ClientID, definition.ClientSecret} {
+			os.Expand(value, func(name string) string {
+				t.Setenv(name, strings.ToLower(name))
+				return ""
+			})
+		}
+		authenticator, err := definition.Authenticator()
+		if err != nil {
+			t.Errorf("%s: %v", definition.Name, err)
+			continue
+		}
+		if !strings.Contains(authenticator.AuthorizationURI, "client_id=") {
+			t.Errorf("%s: unexpected authorization URI %s", definition.Name, authenticator.AuthorizationURI)
+		}
+	}
+}
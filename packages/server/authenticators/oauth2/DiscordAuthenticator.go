@@ -21,7 +21,6 @@ func getDiscordTokenRequestParams(params []interface{}) *utils.RequestParams {
 			"client_secret": {os.Getenv("DISCORD_SECRET_ID")},
 			"grant_type":    {"authorization_code"},
 			"code":          {params[0].(string)},
-			"state":         {os.Getenv("AREA_STATE")},
 			"redirect_uri":  {params[1].(string)},
 		}.Encode(),
 	}
@@ -86,7 +85,7 @@ func getDiscordAuthorizationURI() string {
 		"messages.read",
 		// Other
 		"role_connections.write",
-	}, "%20") + "&state=" + os.Getenv("AREA_STATE")
+	}, "%20")
 }
 
 // It returns a static.OAuth2Authenticator struct with the Name, Enabled, More, AuthorizationURI,
@@ -75,6 +75,7 @@ func FacebookAuthenticator() static.OAuth2Authenticator {
 			Color:  "#3b5998",
 		},
 		AuthorizationURI: getFacebookAuthorizationURI(),
+		PKCE:             true,
 		AuthEndpoints: static.AuthEndpoints{
 			AccessToken: utils.RequestDescriptor{
 				BaseURL:        "https://graph.facebook.com/v16.0/oauth/access_token",
@@ -19,7 +19,6 @@ func getGithubTokenRequestParams(params []interface{}) *utils.RequestParams {
 			"client_id":     os.Getenv("GITHUB_CLIENT_ID"),
 			"client_secret": os.Getenv("GITHUB_SECRET_ID"),
 			"code":          params[0].(string),
-			"state":         os.Getenv("AREA_STATE"),
 			"redirect_uri":  params[1].(string),
 		},
 	}
@@ -48,7 +47,7 @@ func getGithubAuthorizationURI() string {
 		"read:user",
 		"user:email",
 		"user:follow",
-	}, "%20") + "&state=" + os.Getenv("AREA_STATE")
+	}, "%20")
 }
 
 // It returns a static.OAuth2Authenticator that uses the Github API to authenticate users
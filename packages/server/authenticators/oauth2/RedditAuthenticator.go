@@ -58,7 +58,12 @@ func getRedditRevokeTokenParams(params []interface{}) *utils.RequestParams {
 		}.Encode(),
 	})
 
-	if err != nil || resp.StatusCode != 200 {
+	if err != nil {
+		return nil
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != 200 {
 		return nil
 	}
 
@@ -108,7 +113,7 @@ func getRedditAuthorizationURI() string {
 		"modposts",
 		"modwiki",
 		"privatemessages",
-	}, "%20") + "&state=123456789"
+	}, "%20")
 }
 
 // It returns a static.OAuth2Authenticator struct that contains all the information needed to
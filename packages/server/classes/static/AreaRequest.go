@@ -3,6 +3,7 @@ package static
 import (
 	"area-server/classes/shared"
 	"area-server/db/postgres/models"
+	"context"
 
 	"github.com/google/uuid"
 )
@@ -17,6 +18,7 @@ import (
 // interface{}.
 // @property AuthStore - This is a map of key/value pairs that are stored in the authorization.
 // @property ExternalData - This is the data that is passed to the applet from the outside world.
+// @property Context - The context of the trigger run, it is cancelled when the applet is stopped.
 type AreaRequest struct {
 	AppletID      uuid.UUID
 	Authorization *models.Authorization
@@ -25,4 +27,5 @@ type AreaRequest struct {
 	Store         *map[string]interface{}
 	AuthStore     map[string]interface{}
 	ExternalData  map[string]interface{}
+	Context       context.Context
 }
@@ -1,12 +1,16 @@
 package static
 
 import (
+	"area-server/db/encryption"
 	"area-server/db/postgres"
 	"area-server/db/postgres/models"
 	"area-server/utils"
 	"errors"
 	"fmt"
+	"strconv"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 // Authenticator is an interface that has three methods: Authenticate, ReAuthenticate, and Disprove.
@@ -49,11 +53,14 @@ type AuthEndpoints struct {
 // OAuth2 flow.
 // @property OtherParams - This is a function that takes a map of string to interface and returns a map
 // of string to interface. This is used to add additional parameters to the OAuth2 request.
+// @property {bool} PKCE - If true, the provider supports PKCE (S256), a code verifier is sent with the
+// access token request.
 type OAuth2Authenticator struct {
 	Name             string                                              `json:"name"`
 	Enabled          bool                                                `json:"enabled"` // If true, the service can be used for authentication
 	More             More                                                `json:"more"`
 	AuthorizationURI string                                              `json:"authorization_uri"`
+	PKCE             bool                                                `json:"pkce"`
 	AuthEndpoints    AuthEndpoints                                       `json:"-"`
 	OtherParams      func(map[string]interface{}) map[string]interface{} `json:"-"`
 }
@@ -78,8 +85,19 @@ type AuthenticateResponse struct {
 	Data map[string]interface{}
 }
 
+// AuthorizationURL returns the URI to which the user is redirected, with the one-time state, the
+// redirect URI and the PKCE challenge of the verifier (if the provider supports PKCE)
+func (a *OAuth2Authenticator) AuthorizationURL(state string, redirectURI string, verifier string) (string, error) {
+	challenge := ""
+	if a.PKCE && verifier != "" {
+		challenge = utils.PKCEChallenge(verifier)
+	}
+	return utils.AuthorizationURL(a.AuthorizationURI, state, redirectURI, challenge)
+}
+
 // A function that takes a slice of interfaces and returns a pointer to an AuthenticateResponse and a
 // pointer to an AuthenticateError.
+// The params are [needAuth, code, redirect_uri] and optionally the PKCE code verifier of the flow.
 func (a *OAuth2Authenticator) Authenticate(params []interface{}) (*AuthenticateResponse, *AuthenticateError) {
 
 	needAuth, okO := params[0].(bool)
@@ -103,7 +121,14 @@ func (a *OAuth2Authenticator) Authenticate(params []interface{}) (*AuthenticateR
 		return nil, &AuthenticateError{StatusCode: 500, ErrorDesc: "Service email endpoint not found"}
 	}
 
-	authr, _, err := a.AuthEndpoints.AccessToken.Call([]interface{}{code, redirectURI})
+	accessToken := a.AuthEndpoints.AccessToken
+	if len(params) > 3 {
+		if verifier, ok := params[3].(string); ok && a.PKCE && verifier != "" {
+			accessToken.Params = utils.WithCodeVerifier(accessToken.Params, verifier)
+		}
+	}
+
+	authr, _, err := accessToken.Call([]interface{}{code, redirectURI})
 
 	if err != nil {
 		return nil, &AuthenticateError{StatusCode: 500, ErrorDesc: "Service could not fetch access token"}
@@ -156,19 +181,9 @@ func (a *OAuth2Authenticator) Authenticate(params []interface{}) (*AuthenticateR
 		authr = utils.MergeMaps(authr, content)
 	}
 
-	var expiredAt time.Time
-	if oauth["expires_in"] == nil {
-		expiredAt = time.Unix(4072721567, 0)
-	} else {
-		expiredAt = time.Now().Add(time.Duration(int(oauth["expires_in"].(float64))) * time.Second)
-	}
+	expiredAt := expiresAt(oauth["expires_in"])
 
-	var refreshToken string
-	if oauth["refresh_token"] == nil {
-		refreshToken = ""
-	} else {
-		refreshToken = oauth["refresh_token"].(string)
-	}
+	refreshToken, _ := oauth["refresh_token"].(string)
 
 	var other map[string]interface{}
 	if a.OtherParams != nil {
@@ -188,13 +203,56 @@ func (a *OAuth2Authenticator) Authenticate(params []interface{}) (*AuthenticateR
 	}, nil
 }
 
-// Refresh Token
+// Returned when the authorization can not be refreshed anymore, the user must authorize the service
+// again (the applets using it are marked with `NeedsReauth`).
+var ErrReauthRequired = errors.New("Service: Authorization must be renewed")
+
+// The refreshes in progress, one per authorization
+var refreshes = utils.NewSingleFlight()
+
+// Expiration date of the tokens that do not expire
+var neverExpire = time.Unix(4072721567, 0)
+
+// It returns the expiration date of a token from the `expires_in` of the provider (a number or a
+// string), the token never expires if the provider does not send it
+func expiresAt(expiresIn interface{}) time.Time {
+	var seconds float64
+	switch value := expiresIn.(type) {
+	case float64:
+		seconds = value
+	case string:
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return neverExpire
+		}
+		seconds = parsed
+	default:
+		return neverExpire
+	}
+	if seconds <= 0 {
+		return neverExpire
+	}
+	return time.Now().Add(time.Duration(seconds) * time.Second)
+}
+
+// RefreshToken refreshes the access token of the authorization if it is expired
 func (a *OAuth2Authenticator) RefreshToken(authorization *models.Authorization) (*models.Authorization, error) {
+	return a.RefreshTokenAhead(authorization, 0)
+}
+
+// RefreshTokenAhead refreshes the access token of the authorization if it expires in less than
+// `ahead`. The concurrent refreshes of an authorization are deduplicated and the authorization is
+// reloaded before refreshing, so a refresh token rotated by another applet is never sent twice.
+func (a *OAuth2Authenticator) RefreshTokenAhead(authorization *models.Authorization, ahead time.Duration) (*models.Authorization, error) {
 	if authorization == nil {
 		return nil, fmt.Errorf("Service: Authorization is nil")
 	}
 
-	if !authorization.ExpireAt.Before(time.Now()) {
+	if authorization.NeedsReauth {
+		return nil, ErrReauthRequired
+	}
+
+	if authorization.ExpireAt.After(time.Now().Add(ahead)) {
 		return authorization, nil
 	}
 
@@ -202,25 +260,119 @@ func (a *OAuth2Authenticator) RefreshToken(authorization *models.Authorization)
 		return nil, fmt.Errorf("Service: Refresh token is not supported")
 	}
 
-	response, _, err := a.AuthEndpoints.RefreshToken.Call([]interface{}{authorization.RefreshToken})
+	value, err, _ := refreshes.Do(authorization.UUID.String(), func() (interface{}, error) {
+		return a.refresh(authorization.UUID, ahead)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	if result := postgres.DB.Model(authorization).Update("access_token", response["access_token"].(string)); result.Error != nil {
-		return nil, result.Error
+	refreshed := value.(models.Authorization)
+	authorization.AccessToken = refreshed.AccessToken
+	authorization.RefreshToken = refreshed.RefreshToken
+	authorization.ExpireAt = refreshed.ExpireAt
+	authorization.NeedsReauth = refreshed.NeedsReauth
+	return authorization, nil
+}
+
+// It refreshes the authorization stored in the database (unless another applet already did it)
+func (a *OAuth2Authenticator) refresh(id uuid.UUID, ahead time.Duration) (models.Authorization, error) {
+	var authorization models.Authorization
+	if result := postgres.DB.Where(&models.Authorization{UUID: id}).First(&authorization); result.Error != nil {
+		return authorization, result.Error
 	}
 
-	authorization.AccessToken = response["access_token"].(string)
+	if authorization.NeedsReauth {
+		return authorization, ErrReauthRequired
+	}
+	if authorization.ExpireAt.After(time.Now().Add(ahead)) {
+		return authorization, nil
+	}
+	if authorization.RefreshToken == "" {
+		return authorization, MarkReauthRequired(&authorization)
+	}
 
-	// Update refresh token if provided
-	if response["refresh_token"] != nil {
-		authorization.RefreshToken = response["refresh_token"].(string)
-		if result := postgres.DB.Model(authorization).Update("refresh_token", response["refresh_token"].(string)); result.Error != nil {
-			return nil, result.Error
+	response, res, err := a.AuthEndpoints.RefreshToken.Call([]interface{}{authorization.RefreshToken})
+	if err != nil {
+		if res != nil && (res.StatusCode == 400 || res.StatusCode == 401) {
+			// invalid_grant: the refresh token is revoked or expired
+			return authorization, MarkReauthRequired(&authorization)
 		}
+		return authorization, err
+	}
+
+	accessToken, ok := response["access_token"].(string)
+	if !ok || accessToken == "" {
+		return authorization, fmt.Errorf("Service: access_token not found")
 	}
 
-	authorization.ExpireAt = time.Now().Add(time.Duration(response["expires_in"].(float64)) * time.Second)
+	authorization.AccessToken = accessToken
+	authorization.ExpireAt = expiresAt(response["expires_in"])
+	// Update refresh token if provided (rotation), the previous one is kept otherwise
+	if refreshToken, ok := response["refresh_token"].(string); ok && refreshToken != "" {
+		authorization.RefreshToken = refreshToken
+	}
+	if err := saveTokens(&authorization); err != nil {
+		return authorization, err
+	}
 	return authorization, nil
 }
+
+// It saves the tokens of the authorization, they are encrypted by the serializer of the model (an
+// update with a map would store them in plain text)
+func saveTokens(authorization *models.Authorization) error {
+	authorization.AccessTokenHash = encryption.Fingerprint(authorization.AccessToken)
+	return postgres.DB.Model(authorization).
+		Select("access_token", "refresh_token", "expire_at", "needs_reauth", "access_token_hash").
+		Updates(authorization).Error
+}
+
+// MarkReauthRequired marks the authorization and the applets using it as needing a new authorization,
+// the owner is notified
+func MarkReauthRequired(authorization *models.Authorization) error {
+	authorization.NeedsReauth = true
+	authorization.Health = models.HealthNeedsReconnect
+	transition := postgres.DB.Model(&models.Authorization{}).
+		Where("uuid = ? AND needs_reauth = ?", authorization.UUID, false).
+		Update("needs_reauth", true)
+	if transition.Error != nil {
+		return transition.Error
+	}
+	if result := postgres.DB.Model(authorization).Update("health", models.HealthNeedsReconnect); result.Error != nil {
+		return result.Error
+	}
+	applets := postgres.DB.Model(&models.Area{}).Select("applet_uuid").Where("authorization_uuid = ?", authorization.UUID)
+	if result := postgres.DB.Model(&models.Applet{}).Where("uuid IN (?)", applets).Update("needs_reauth", true); result.Error != nil {
+		return result.Error
+	}
+
+	// The owner is told once, when the authorization stops working (by the refresh, a trigger or the
+	// health check)
+	if transition.RowsAffected > 0 {
+		notifyReconnect(authorization)
+	}
+	return ErrReauthRequired
+}
+
+// RenewAuthorization replaces the tokens of an authorization marked with `NeedsReauth` by the tokens
+// of a new authentication, the applets that do not use another expired authorization are cleared
+func RenewAuthorization(authorization *models.Authorization, response *AuthenticateResponse) error {
+	authorization.AccessToken, _ = response.Data["access_token"].(string)
+	authorization.RefreshToken, _ = response.Data["refresh_token"].(string)
+	authorization.ExpireAt, _ = response.Data["expired_at"].(time.Time)
+	authorization.NeedsReauth = false
+	if err := saveTokens(authorization); err != nil {
+		return err
+	}
+	if err := SaveHealth(authorization, models.HealthHealthy, "", time.Now()); err != nil {
+		return err
+	}
+
+	applets := postgres.DB.Model(&models.Area{}).Select("applet_uuid").Where("authorization_uuid = ?", authorization.UUID)
+	expired := postgres.DB.Model(&models.Area{}).Select("areas.applet_uuid").
+		Joins("JOIN authorizations ON authorizations.uuid = areas.authorization_uuid").
+		Where("authorizations.needs_reauth = ?", true)
+	return postgres.DB.Model(&models.Applet{}).
+		Where("uuid IN (?) AND uuid NOT IN (?)", applets, expired).
+		Update("needs_reauth", false).Error
+}
@@ -0,0 +1,135 @@
+package static
+
+import (
+	"area-server/db/postgres"
+	"area-server/db/postgres/models"
+	"area-server/mail"
+	"area-server/utils"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+)
+
+// Refreshes the token whatever its expiration date
+const forceRefresh = 100 * 365 * 24 * time.Hour
+
+// The refresh of a refused token and the marking of the revoked authorizations (replaced in the tests)
+var (
+	forceRefreshToken = func(a *OAuth2Authenticator, authorization *models.Authorization) (*models.Authorization, error) {
+		return a.RefreshTokenAhead(authorization, forceRefresh)
+	}
+	markReauthRequired = MarkReauthRequired
+)
+
+// It marks the authorization and its applets with `NeedsReauth`, it returns `HealthError` if they
+// could not be saved
+func markNeedsReconnect(authorization *models.Authorization) (string, error) {
+	if err := markReauthRequired(authorization); !errors.Is(err, ErrReauthRequired) {
+		return models.HealthError, err
+	}
+	return models.HealthNeedsReconnect, nil
+}
+
+// SaveHealth saves the result of a health check of the authorization
+func SaveHealth(authorization *models.Authorization, health string, message string, now time.Time) error {
+	authorization.Health = health
+	authorization.HealthError = message
+	authorization.CheckedAt = &now
+	return postgres.DB.Model(authorization).Updates(map[string]interface{}{
+		"health":       health,
+		"health_error": message,
+		"checked_at":   now,
+	}).Error
+}
+
+// It tells the owner of the authorization that the service must be reconnected, with the applets that
+// will stop
+func notifyReconnect(authorization *models.Authorization) {
+	var account models.Account
+	if result := postgres.DB.Where(&models.Account{UUID: authorization.AccountUUID}).First(&account); result.Error != nil {
+		log.Printf("[Health] Could not find the account of %s: %s", authorization.UUID, result.Error.Error())
+		return
+	}
+	if !account.EmailVerified || account.Disabled {
+		return
+	}
+
+	var applets []string
+	used := postgres.DB.Model(&models.Area{}).Select("applet_uuid").Where("authorization_uuid = ?", authorization.UUID)
+	if result := postgres.DB.Model(&models.Applet{}).Where("uuid IN (?)", used).Pluck("name", &applets); result.Error != nil {
+		log.Printf("[Health] Could not list the applets of %s: %s", authorization.UUID, result.Error.Error())
+	}
+
+	mail.SendAsync(mail.ReconnectMessage(account.Email, authorization.AuthService, authorization.Label, applets))
+}
+
+// It returns the endpoint used to check the token: the validation endpoint, else the profile or the
+// email of the user (nil if the provider has none)
+func (a *OAuth2Authenticator) healthEndpoint() *utils.RequestDescriptor {
+	switch {
+	case a.AuthEndpoints.ValidateToken != nil:
+		return a.AuthEndpoints.ValidateToken
+	case a.AuthEndpoints.Profile != nil:
+		return a.AuthEndpoints.Profile
+	}
+	return a.AuthEndpoints.Email
+}
+
+// It calls the endpoint with the access token, it returns true if the provider refused the token
+func callHealthEndpoint(endpoint *utils.RequestDescriptor, authorization *models.Authorization) (bool, error) {
+	_, res, err := endpoint.Call([]interface{}{map[string]interface{}{
+		"access_token": authorization.AccessToken,
+		"token_type":   "Bearer",
+	}})
+	if err != nil && res != nil && (res.StatusCode == http.StatusUnauthorized || res.StatusCode == http.StatusForbidden) {
+		return true, err
+	}
+	return false, err
+}
+
+// CheckHealth validates the token of the authorization with the provider and returns its health
+// (`models.Health*`) with the error of the check. A token refused by the provider is refreshed, the
+// authorization and its applets are marked with `NeedsReauth` if it can't be.
+func (a *OAuth2Authenticator) CheckHealth(authorization *models.Authorization) (string, error) {
+	if authorization.NeedsReauth {
+		return models.HealthNeedsReconnect, nil
+	}
+
+	// An expired token is refreshed first (as the trigger would do)
+	if _, err := a.RefreshToken(authorization); errors.Is(err, ErrReauthRequired) {
+		return models.HealthNeedsReconnect, nil
+	} else if err != nil {
+		return models.HealthError, err
+	}
+
+	endpoint := a.healthEndpoint()
+	if endpoint == nil {
+		return models.HealthUnknown, nil
+	}
+
+	refused, err := callHealthEndpoint(endpoint, authorization)
+	if err == nil {
+		return models.HealthHealthy, nil
+	}
+	if !refused {
+		return models.HealthError, err
+	}
+
+	// The token was revoked before its expiration: a new one is requested
+	if a.AuthEndpoints.RefreshToken == nil || authorization.RefreshToken == "" {
+		return markNeedsReconnect(authorization)
+	}
+	if _, err := forceRefreshToken(a, authorization); errors.Is(err, ErrReauthRequired) {
+		return models.HealthNeedsReconnect, nil
+	} else if err != nil {
+		return models.HealthError, err
+	}
+
+	if refused, err = callHealthEndpoint(endpoint, authorization); refused {
+		return markNeedsReconnect(authorization)
+	} else if err != nil {
+		return models.HealthError, err
+	}
+	return models.HealthHealthy, nil
+}
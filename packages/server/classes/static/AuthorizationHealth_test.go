@@ -0,0 +1,156 @@
+package static
+
+import (
+	"area-server/config"
+	"area-server/db/postgres/models"
+	"area-server/utils"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestMain(m *testing.M) {
+	policy, err := utils.NewEgressPolicy(config.EgressConfig{Allow: []string{"127.0.0.1"}, MaxResponseSize: 1 << 20, MaxRedirects: 5})
+	if err != nil {
+		panic(err)
+	}
+	utils.SetEgress(policy)
+	os.Exit(m.Run())
+}
+
+// It returns an authenticator whose profile endpoint answers with the status
+func profileAuthenticator(t *testing.T, status int) *OAuth2Authenticator {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			t.Errorf("unexpected authorization header %q", r.Header.Get("Authorization"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write([]byte(`{"login": "john"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	return &OAuth2Authenticator{
+		Name: "test",
+		AuthEndpoints: AuthEndpoints{
+			Profile: &utils.RequestDescriptor{
+				BaseURL: server.URL,
+				Params: func(params []interface{}) *utils.RequestParams {
+					fields := params[0].(map[string]interface{})
+					return &utils.RequestParams{
+						Method:  "GET",
+						Headers: map[string]string{"Authorization": "Bearer " + fields["access_token"].(string)},
+					}
+				},
+				ExpectedStatus: []int{200},
+			},
+		},
+	}
+}
+
+func TestCheckHealth(t *testing.T) {
+	valid := func() *models.Authorization {
+		return &models.Authorization{AccessToken: "token", ExpireAt: time.Now().Add(time.Hour)}
+	}
+
+	if health, err := profileAuthenticator(t, 200).CheckHealth(valid()); health != models.HealthHealthy || err != nil {
+		t.Errorf("expected a healthy authorization, got %s (%v)", health, err)
+	}
+	if health, err := profileAuthenticator(t, 502).CheckHealth(valid()); health != models.HealthError || err == nil {
+		t.Errorf("expected an error, got %s (%v)", health, err)
+	}
+	if health, _ := (&OAuth2Authenticator{Name: "none"}).CheckHealth(valid()); health != models.HealthUnknown {
+		t.Errorf("expected an unknown health without endpoint, got %s", health)
+	}
+
+	expired := valid()
+	expired.NeedsReauth = true
+	if health, _ := profileAuthenticator(t, 200).CheckHealth(expired); health != models.HealthNeedsReconnect {
+		t.Errorf("expected an authorization to reconnect, got %s", health)
+	}
+}
+
+// It returns an authenticator whose profile endpoint only accepts the `accepted` token, with a refresh
+// endpoint (the refresh and the marking are replaced by `refresh` and `mark` during the test)
+func refreshingAuthenticator(t *testing.T, accepted string, refresh func(*models.Authorization) error, mark func(*models.Authorization) error) *OAuth2Authenticator {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Header.Get("Authorization") != "Bearer "+accepted {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"message": "Bad credentials"}`))
+			return
+		}
+		w.Write([]byte(`{"login": "john"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	previousRefresh, previousMark := forceRefreshToken, markReauthRequired
+	forceRefreshToken = func(_ *OAuth2Authenticator, authorization *models.Authorization) (*models.Authorization, error) {
+		return authorization, refresh(authorization)
+	}
+	markReauthRequired = mark
+	t.Cleanup(func() { forceRefreshToken, markReauthRequired = previousRefresh, previousMark })
+
+	authenticator := profileAuthenticator(t, 200)
+	authenticator.AuthEndpoints.Profile.BaseURL = server.URL
+	authenticator.AuthEndpoints.RefreshToken = &utils.RequestDescriptor{BaseURL: server.URL}
+	return authenticator
+}
+
+// It marks the authorization as the database would
+func markedReauth(authorization *models.Authorization) error {
+	authorization.NeedsReauth = true
+	return ErrReauthRequired
+}
+
+func TestCheckHealthRefreshesRefusedToken(t *testing.T) {
+	authorization := &models.Authorization{AccessToken: "revoked", RefreshToken: "refresh", ExpireAt: time.Now().Add(time.Hour)}
+	refreshed := false
+	authenticator := refreshingAuthenticator(t, "new", func(authorization *models.Authorization) error {
+		refreshed = true
+		authorization.AccessToken = "new"
+		return nil
+	}, func(*models.Authorization) error {
+		t.Error("the authorization should not be marked")
+		return ErrReauthRequired
+	})
+
+	if health, err := authenticator.CheckHealth(authorization); health != models.HealthHealthy || err != nil {
+		t.Errorf("expected a healthy authorization, got %s (%v)", health, err)
+	}
+	if !refreshed {
+		t.Error("expected the refused token to be refreshed")
+	}
+}
+
+func TestCheckHealthRefreshedTokenRefused(t *testing.T) {
+	authorization := &models.Authorization{AccessToken: "revoked", RefreshToken: "refresh", ExpireAt: time.Now().Add(time.Hour)}
+	authenticator := refreshingAuthenticator(t, "new", func(authorization *models.Authorization) error {
+		authorization.AccessToken = "still-revoked"
+		return nil
+	}, markedReauth)
+
+	if health, err := authenticator.CheckHealth(authorization); health != models.HealthNeedsReconnect || err != nil {
+		t.Errorf("expected an authorization to reconnect, got %s (%v)", health, err)
+	}
+	if !authorization.NeedsReauth {
+		t.Error("expected the authorization to be marked")
+	}
+}
+
+func TestCheckHealthMarkFailure(t *testing.T) {
+	authorization := &models.Authorization{AccessToken: "revoked", ExpireAt: time.Now().Add(time.Hour)}
+	authenticator := refreshingAuthenticator(t, "new", func(*models.Authorization) error {
+		t.Error("the token should not be refreshed without refresh token")
+		return nil
+	}, func(*models.Authorization) error {
+		return errors.New("connection refused")
+	})
+
+	if health, err := authenticator.CheckHealth(authorization); health != models.HealthError || err == nil {
+		t.Errorf("expected an error when the authorization can't be marked, got %s (%v)", health, err)
+	}
+}
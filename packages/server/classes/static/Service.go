@@ -23,17 +23,20 @@ import (
 // @property Handler - The function that will be called when the route is hit.
 // @property {string} Method - The HTTP method to use for this route.
 // @property {bool} NeedAuth - If true, the request will be checked for a valid JWT token.
+// @property {string} Scope - The scope an API key needs to call the route (API keys are refused if
+// empty).
 type ServiceRoute struct {
 	Endpoint string                 `json:"endpoint"`
 	Handler  func(*fiber.Ctx) error `json:"-"`
 	Method   string                 `json:"method"`
 	NeedAuth bool                   `json:"need_auth"`
+	Scope    string                 `json:"scope,omitempty"`
 }
 
 // `ServiceValidator` is a map of strings to functions that take an `Authorization`, a `Service`, an
-// interface, and a map of strings to interfaces and return a boolean.
-//
-type ServiceValidator (map[string]func(*models.Authorization, *Service, interface{}, map[string]interface{}) bool)
+// interface, and a map of strings to interfaces and return a `ValidationError` (nil if the value is
+// valid).
+type ServiceValidator (map[string]func(*models.Authorization, *Service, interface{}, map[string]interface{}) *ValidationError)
 type ServiceEndpoint (map[string]*utils.RequestDescriptor)
 
 // More is a struct with two fields, Avatar and Color, both of which are exported.
@@ -49,6 +52,7 @@ type Service struct {
 	Description   string               `json:"description"`
 	Authenticator *OAuth2Authenticator `json:"authenticator"` // Authenticator used by the service
 	RateLimit     float64              `json:"-"`             // Number of requests per 30 seconds
+	Timeout       time.Duration        `json:"-"`             // Maximum duration of an action / reaction call (0 = no limit, each request still has its own timeout)
 	More          *More                `json:"more"`          // If equal to nil, the client must use the avatar_uri and color of the authenticator (if exists)
 	Validators    ServiceValidator     `json:"-"`             // Validators used by the service
 	Endpoints     ServiceEndpoint      `json:"-"`             // Endpoints used by the service
@@ -71,6 +75,19 @@ func (s *Service) String() string {
 	})
 }
 
+// LimitEndpoints applies the rate limit of the service to its endpoints (per authorization), the
+// endpoints with their own rate are not changed
+func (s *Service) LimitEndpoints() {
+	if s.RateLimit <= 0 {
+		return
+	}
+	for _, endpoint := range s.Endpoints {
+		if endpoint != nil && endpoint.Rate == 0 {
+			endpoint.Rate = s.RateLimit / 30
+		}
+	}
+}
+
 // It's a method that returns a pointer to a `ServiceArea` struct.
 func (s *Service) GetActionByName(name string) *ServiceArea {
 	for _, action := range s.Actions {
@@ -3,7 +3,6 @@ package static
 import (
 	"area-server/classes/shared"
 	"area-server/db/postgres/models"
-	"fmt"
 )
 
 // `ServiceArea` is a struct that contains a name, description, a boolean value, a slice of strings, a
@@ -51,33 +50,28 @@ func MergeStore(bs map[string]StoreElement, rs map[string]interface{}) map[strin
 	return result
 }
 
-// Validating the request.
+// Validating the request against the schema of the store, then with the validators of the service.
+// It returns nil if the store is valid.
 func (a *ServiceArea) Validate(
 	authorization *models.Authorization,
 	service *Service,
+	areaType string,
 	store map[string]interface{},
-) map[string]bool {
-	result := make(map[string]bool)
-	ok := true
-
-	fmt.Println("Store: ", store)
-	for key, value := range a.RequestStore {
-		if value.Required && store[key] == nil {
-			result[key] = false
-			ok = false
-		}
-	}
+) *ValidationResult {
+	result := a.Schema(service, areaType).Validate(store)
 
 	for key, value := range store {
+		if _, invalid := result.Invalid[key]; invalid || value == nil {
+			continue
+		}
 		if service.Validators[key] != nil {
-			result[key] = service.Validators[key](authorization, service, value, store)
-			if !result[key] {
-				ok = false
+			if err := service.Validators[key](authorization, service, value, store); err != nil {
+				result.AddInvalid(key, err)
 			}
 		}
 	}
 
-	if !ok {
+	if !result.OK() {
 		return result
 	}
 
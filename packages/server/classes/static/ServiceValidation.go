@@ -0,0 +1,128 @@
+package static
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Codes of the errors returned by the validators
+const (
+	ValidationMissing           = "missing"            // The field is required but not provided
+	ValidationMissingDependency = "missing_dependency" // A field needed by this one (NeedFields) is not provided
+	ValidationInvalidType       = "invalid_type"       // The value has not the expected type
+	ValidationInvalidFormat     = "invalid_format"     // The value is badly formatted
+	ValidationNotAllowed        = "not_allowed"        // The value is not one of the allowed values
+	ValidationOutOfRange        = "out_of_range"       // The value is too small / too big
+	ValidationInPast            = "in_past"            // The date is in the past
+	ValidationNotFound          = "not_found"          // The resource does not exist on the service
+	ValidationAlreadyExists     = "already_exists"     // The resource already exists on the service
+)
+
+// `ValidationError` is the error returned by a validator when a field is invalid.
+// @property {string} Code - The code of the error (one of the Validation* constants).
+// @property {string} Message - A human readable message.
+// @property {string} Expected - The expected format / values of the field (can be empty).
+type ValidationError struct {
+	Code     string `json:"code"`
+	Message  string `json:"message"`
+	Expected string `json:"expected,omitempty"`
+}
+
+// It creates a new validation error
+func NewValidationError(code string, message string, expected string) *ValidationError {
+	return &ValidationError{
+		Code:     code,
+		Message:  message,
+		Expected: expected,
+	}
+}
+
+// It returns the message of the validation error
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// `ValidationResult` is the result of the validation of a store.
+// @property {[]string} Missing - The required fields that are not provided.
+// @property Invalid - The fields that are provided but invalid, with the reason.
+type ValidationResult struct {
+	Missing []string                    `json:"missing"`
+	Invalid map[string]*ValidationError `json:"invalid"`
+}
+
+// It creates an empty validation result
+func NewValidationResult() *ValidationResult {
+	return &ValidationResult{
+		Missing: []string{},
+		Invalid: make(map[string]*ValidationError),
+	}
+}
+
+// OK returns true if no field is missing or invalid
+func (r *ValidationResult) OK() bool {
+	return len(r.Missing) == 0 && len(r.Invalid) == 0
+}
+
+// It marks a field as missing
+func (r *ValidationResult) AddMissing(key string) {
+	for _, k := range r.Missing {
+		if k == key {
+			return
+		}
+	}
+	r.Missing = append(r.Missing, key)
+}
+
+// It marks a field as invalid
+func (r *ValidationResult) AddInvalid(key string, err *ValidationError) {
+	r.Invalid[key] = err
+}
+
+// It creates an error for a required value that is not provided
+func MissingError() *ValidationError {
+	return NewValidationError(ValidationMissing, "The value is required", "")
+}
+
+// It creates an error for a field that needs other fields to be filled
+func MissingDependencyError(fields ...string) *ValidationError {
+	return NewValidationError(ValidationMissingDependency, "The field needs other fields to be filled", strings.Join(fields, ", "))
+}
+
+// It creates an error for a value that has not the expected type
+func InvalidTypeError(expected string) *ValidationError {
+	return NewValidationError(ValidationInvalidType, "The value must be a "+expected, expected)
+}
+
+// It creates an error for a value that is badly formatted
+func InvalidFormatError(expected string) *ValidationError {
+	return NewValidationError(ValidationInvalidFormat, "The value is badly formatted", expected)
+}
+
+// It creates an error for a value that is not one of the allowed values
+func NotAllowedError(allowed ...string) *ValidationError {
+	return NewValidationError(ValidationNotAllowed, "The value is not one of the allowed values", strings.Join(allowed, ", "))
+}
+
+// It creates an error for a number that is not between min and max
+func OutOfRangeError(min int, max int) *ValidationError {
+	return NewValidationError(
+		ValidationOutOfRange,
+		"The value is out of range",
+		"between "+strconv.Itoa(min)+" and "+strconv.Itoa(max),
+	)
+}
+
+// It creates an error for a date that is in the past
+func InPastError() *ValidationError {
+	return NewValidationError(ValidationInPast, "The date is in the past", "a date in the future")
+}
+
+// It creates an error for a resource that can't be found on the service
+func NotFoundError(resource string) *ValidationError {
+	return NewValidationError(ValidationNotFound, "The "+resource+" does not exist", "")
+}
+
+// It creates an error for a resource that already exists on the service
+func AlreadyExistsError(resource string) *ValidationError {
+	return NewValidationError(ValidationAlreadyExists, "The "+resource+" already exists", "")
+}
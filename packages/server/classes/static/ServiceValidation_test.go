@@ -0,0 +1,87 @@
+package static
+
+import (
+	"area-server/db/postgres/models"
+	"testing"
+)
+
+func TestStoreSchemaValidate(t *testing.T) {
+	schema := schemaTestArea().Schema(&Service{Name: "discord"}, "action")
+
+	result := schema.Validate(map[string]interface{}{
+		"req:channel:id": "42",
+		"req:mail":       "not an address",
+		"req:mode":       "everything",
+		"req:content":    12,
+	})
+	if result.OK() {
+		t.Fatal("expected the store to be invalid")
+	}
+	if len(result.Missing) != 1 || result.Missing[0] != "req:guild:id" {
+		t.Errorf("expected req:guild:id to be missing, got %v", result.Missing)
+	}
+
+	expected := map[string]string{
+		"req:channel:id": ValidationMissingDependency,
+		"req:mail":       ValidationInvalidFormat,
+		"req:mode":       ValidationNotAllowed,
+		"req:content":    ValidationInvalidType,
+	}
+	for key, code := range expected {
+		if err := result.Invalid[key]; err == nil || err.Code != code {
+			t.Errorf("%s: expected %s, got %+v", key, code, err)
+		}
+	}
+	if err := result.Invalid["req:mode"]; err != nil && err.Expected != "all, mentions" {
+		t.Errorf("expected the allowed values, got %q", err.Expected)
+	}
+	if len(result.Invalid) != len(expected) {
+		t.Errorf("unexpected invalid fields %v", result.Invalid)
+	}
+
+	valid := schema.Validate(map[string]interface{}{"req:guild:id": "1", "req:channel:id": "42", "req:mode": "all"})
+	if !valid.OK() {
+		t.Errorf("expected the store to be valid, got %+v", valid)
+	}
+}
+
+func TestServiceAreaValidate(t *testing.T) {
+	called := map[string]bool{}
+	service := &Service{
+		Name: "discord",
+		Validators: ServiceValidator{
+			"req:channel:id": func(_ *models.Authorization, _ *Service, value interface{}, _ map[string]interface{}) *ValidationError {
+				called["req:channel:id"] = true
+				if value != "42" {
+					return NotFoundError("channel")
+				}
+				return nil
+			},
+			"req:mail": func(*models.Authorization, *Service, interface{}, map[string]interface{}) *ValidationError {
+				called["req:mail"] = true
+				return nil
+			},
+		},
+	}
+	area := schemaTestArea()
+
+	if result := area.Validate(nil, service, "action", map[string]interface{}{"req:guild:id": "1", "req:channel:id": "42"}); result != nil {
+		t.Errorf("expected a valid store, got %+v", result)
+	}
+
+	// The validators of the service are not called on the fields rejected by the schema
+	called = map[string]bool{}
+	result := area.Validate(nil, service, "action", map[string]interface{}{"req:guild:id": "1", "req:channel:id": "7", "req:mail": "invalid"})
+	if result == nil {
+		t.Fatal("expected the store to be invalid")
+	}
+	if err := result.Invalid["req:channel:id"]; err == nil || err.Code != ValidationNotFound || err.Message != "The channel does not exist" {
+		t.Errorf("expected the channel to be not found, got %+v", err)
+	}
+	if err := result.Invalid["req:mail"]; err == nil || err.Code != ValidationInvalidFormat {
+		t.Errorf("expected an invalid address, got %+v", err)
+	}
+	if called["req:mail"] {
+		t.Error("the validator of an invalid field must not be called")
+	}
+}
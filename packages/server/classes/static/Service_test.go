@@ -0,0 +1,23 @@
+package static
+
+import (
+	"area-server/utils"
+	"testing"
+)
+
+func TestServiceLimitEndpoints(t *testing.T) {
+	service := &Service{
+		RateLimit: 3,
+		Endpoints: ServiceEndpoint{
+			"list":   &utils.RequestDescriptor{},
+			"custom": &utils.RequestDescriptor{Rate: 1},
+		},
+	}
+	service.LimitEndpoints()
+	if rate := service.Endpoints["list"].Rate; rate != 0.1 {
+		t.Errorf("expected 3 requests per 30 seconds, got %v per second", rate)
+	}
+	if rate := service.Endpoints["custom"].Rate; rate != 1 {
+		t.Errorf("expected the rate of the endpoint to be kept, got %v", rate)
+	}
+}
@@ -0,0 +1,331 @@
+package static
+
+import (
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// JSONSchemaDialect is the JSON Schema version used to describe the request stores.
+const JSONSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// Regex used to find the `${...}` placeholders inside a select_uri endpoint
+var placeholderRegex = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// `StoreSchema` is the JSON Schema of the `RequestStore` of an action or a reaction.
+// @property {string} Schema - The JSON Schema dialect.
+// @property {string} ID - The route where the schema can be retrieved.
+// @property {string} Title - The name of the action / reaction.
+// @property {string} Description - The description of the action / reaction.
+// @property {string} Type - Always "object".
+// @property Properties - The schema of each field of the store.
+// @property {[]string} Required - The fields that must be provided.
+// @property DependentRequired - For each field, the fields that must be provided with it (NeedFields).
+// @property {[]string} Order - The fields sorted by priority, used by clients to render the form.
+type StoreSchema struct {
+	Schema            string                     `json:"$schema"`
+	ID                string                     `json:"$id"`
+	Title             string                     `json:"title"`
+	Description       string                     `json:"description"`
+	Type              string                     `json:"type"`
+	Properties        map[string]*PropertySchema `json:"properties"`
+	Required          []string                   `json:"required,omitempty"`
+	DependentRequired map[string][]string        `json:"dependentRequired,omitempty"`
+	Order             []string                   `json:"x-order"`
+}
+
+// `PropertySchema` is the JSON Schema of a single field of the store.
+// @property {string} Type - The JSON type of the field (values of the store are always strings).
+// @property {string} Description - The description of the field.
+// @property {string} Format - The format of the field (email, ...).
+// @property {string} Pattern - The regex that the value must match.
+// @property {[]string} Enum - The values that can be selected.
+// @property AnyOf - The value must match one of these schemas (used to accept components).
+// @property UI - Hints used by the clients to render the field.
+type PropertySchema struct {
+	Type        string            `json:"type,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Format      string            `json:"format,omitempty"`
+	Pattern     string            `json:"pattern,omitempty"`
+	Enum        []string          `json:"enum,omitempty"`
+	AnyOf       []*PropertySchema `json:"anyOf,omitempty"`
+	UI          *UIHint           `json:"x-ui,omitempty"`
+	expected    string            // Human readable format, returned in the validation errors
+}
+
+// `UIHint` contains the information that can't be expressed with JSON Schema.
+// @property {string} Widget - The widget to use (text, textarea, email, datetime, select, remote_select).
+// @property {int} Order - The priority of the field (0 = highest).
+// @property {[]string} VisibleIf - The field must only be shown when these fields are filled.
+// @property {[]string} AllowedComponents - The components that can be used to fill the field.
+// @property Options - For remote_select, where the options must be fetched.
+type UIHint struct {
+	Widget            string         `json:"widget"`
+	Order             int            `json:"order"`
+	VisibleIf         []string       `json:"visible_if,omitempty"`
+	AllowedComponents []string       `json:"allowed_components,omitempty"`
+	Options           *OptionsSource `json:"options,omitempty"`
+}
+
+// `OptionsSource` describes the service route that provides the options of a select_uri field.
+// @property {string} URI - The route, the `${...}` placeholders must be replaced by the value of the
+// fields (or "default" if the field is empty).
+// @property {[]string} DependsOn - The fields used by the placeholders of the route.
+// @property {string} Endpoint - The route resolving the placeholders on the server side (the fields are
+// sent as query parameters).
+type OptionsSource struct {
+	URI       string   `json:"uri"`
+	DependsOn []string `json:"depends_on,omitempty"`
+	Endpoint  string   `json:"endpoint"`
+}
+
+// It builds the JSON Schema of a single field depending on its type
+func (e *StoreElement) baseSchema() *PropertySchema {
+	prop := &PropertySchema{Type: "string"}
+
+	switch e.Type {
+	case "email":
+		prop.Format = "email"
+		prop.expected = "user@example.com"
+	case "date":
+		prop.Pattern = `^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}$`
+		prop.expected = "YYYY-MM-DDTHH:MM:SS"
+	case "number":
+		prop.Pattern = `^\d+(\.\d+)?$`
+		prop.expected = "positive number"
+	case "bool":
+		prop.Enum = []string{"true", "false"}
+	case "select":
+		if len(e.Values) > 0 {
+			prop.Enum = e.Values
+		}
+	}
+	return prop
+}
+
+// It returns the widget that must be used by the client to render the field
+func (e *StoreElement) widget() string {
+	switch e.Type {
+	case "long_string", "text":
+		return "textarea"
+	case "email":
+		return "email"
+	case "date":
+		return "datetime"
+	case "select", "bool":
+		return "select"
+	case "select_uri":
+		return "remote_select"
+	}
+	return "text"
+}
+
+// It returns the regex matching a component (e.g. `{{discord:channel:id}}`) that can fill the field
+func (e *StoreElement) componentPattern() string {
+	if len(e.AllowedComponents) == 0 {
+		return `^\{\{[a-zA-Z0-9:_]+\}\}$`
+	}
+	quoted := make([]string, len(e.AllowedComponents))
+	for i, component := range e.AllowedComponents {
+		quoted[i] = regexp.QuoteMeta(component)
+	}
+	return `^\{\{(` + strings.Join(quoted, "|") + `)\}\}$`
+}
+
+// OptionsURI returns the route of a select_uri field, the `${...}` placeholders are replaced by the
+// value of the fields ("default" is used when the field is not filled). The values are escaped (path
+// segment before the `?`, query value after), so they can't change the route.
+func (e *StoreElement) OptionsURI(store func(key string) string) string {
+	if len(e.Values) == 0 {
+		return ""
+	}
+	query := strings.Index(e.Values[0], "?")
+	return replacePlaceholders(e.Values[0], func(start int, placeholder string) string {
+		value := store(placeholder[2 : len(placeholder)-1])
+		if query != -1 && start > query {
+			if value == "" {
+				value = "default"
+			}
+			return url.QueryEscape(value)
+		}
+		// The path is normalized by the router, a segment can't be "." or ".."
+		if value == "" || strings.Trim(value, ".") == "" {
+			return "default"
+		}
+		return url.PathEscape(value)
+	})
+}
+
+// It replaces the `${...}` placeholders of the template with the result of `replace` (called with
+// the position of the placeholder)
+func replacePlaceholders(template string, replace func(start int, placeholder string) string) string {
+	var builder strings.Builder
+	last := 0
+	for _, match := range placeholderRegex.FindAllStringIndex(template, -1) {
+		builder.WriteString(template[last:match[0]])
+		builder.WriteString(replace(match[0], template[match[0]:match[1]]))
+		last = match[1]
+	}
+	builder.WriteString(template[last:])
+	return builder.String()
+}
+
+// Schema builds the JSON Schema of the RequestStore, areaType is "action" or "reaction" (only the
+// fields of a reaction can be filled with components)
+func (a *ServiceArea) Schema(service *Service, areaType string) *StoreSchema {
+	schema := &StoreSchema{
+		Schema:      JSONSchemaDialect,
+		ID:          "/services/" + service.Name + "/" + areaType + "s/" + a.Name + "/schema",
+		Title:       a.Name,
+		Description: a.Description,
+		Type:        "object",
+		Properties:  make(map[string]*PropertySchema),
+		Order:       []string{},
+	}
+
+	for key, element := range a.RequestStore {
+		ui := &UIHint{
+			Widget:            element.widget(),
+			Order:             element.Priority,
+			VisibleIf:         element.NeedFields,
+			AllowedComponents: element.AllowedComponents,
+		}
+
+		if element.Type == "select_uri" && len(element.Values) > 0 {
+			var dependsOn []string
+			for _, match := range placeholderRegex.FindAllStringSubmatch(element.Values[0], -1) {
+				dependsOn = append(dependsOn, match[1])
+			}
+			ui.Options = &OptionsSource{
+				URI:       "/services/" + service.Name + "/api" + element.Values[0],
+				DependsOn: dependsOn,
+				Endpoint:  "/services/" + service.Name + "/options/" + a.Name + "/" + key,
+			}
+		}
+
+		prop := element.baseSchema()
+		if areaType == "reaction" {
+			prop = &PropertySchema{
+				AnyOf: []*PropertySchema{
+					prop,
+					{Type: "string", Pattern: element.componentPattern(), expected: "{{component}}"},
+				},
+			}
+		}
+		prop.Description = element.Description
+		prop.UI = ui
+		schema.Properties[key] = prop
+
+		if element.Required {
+			schema.Required = append(schema.Required, key)
+		}
+		if len(element.NeedFields) > 0 {
+			if schema.DependentRequired == nil {
+				schema.DependentRequired = make(map[string][]string)
+			}
+			schema.DependentRequired[key] = element.NeedFields
+		}
+		schema.Order = append(schema.Order, key)
+	}
+
+	sort.Strings(schema.Required)
+	sort.Slice(schema.Order, func(i, j int) bool {
+		pi, pj := a.RequestStore[schema.Order[i]].Priority, a.RequestStore[schema.Order[j]].Priority
+		if pi != pj {
+			return pi < pj
+		}
+		return schema.Order[i] < schema.Order[j]
+	})
+	return schema
+}
+
+// Check checks if the value respects the schema of the field, it returns nil if the value is valid
+func (p *PropertySchema) Check(value interface{}) *ValidationError {
+	if len(p.AnyOf) > 0 {
+		var first *ValidationError
+		for _, sub := range p.AnyOf {
+			err := sub.Check(value)
+			if err == nil {
+				return nil
+			}
+			if first == nil {
+				first = err
+			}
+		}
+		return first
+	}
+
+	str, ok := value.(string)
+	if p.Type == "string" && !ok {
+		return InvalidTypeError("string")
+	}
+
+	if len(p.Enum) > 0 {
+		found := false
+		for _, v := range p.Enum {
+			if v == str {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return NotAllowedError(p.Enum...)
+		}
+	}
+
+	if p.Pattern != "" {
+		if match, err := regexp.MatchString(p.Pattern, str); err != nil || !match {
+			return InvalidFormatError(p.expected)
+		}
+	}
+
+	if p.Format == "email" {
+		address, err := mail.ParseAddress(str)
+		if err != nil || address.Address != str {
+			return NewValidationError(ValidationInvalidFormat, "The value is not a valid email address", p.expected)
+		}
+	}
+
+	return nil
+}
+
+// Validate checks the store against the schema, missing fields are reported separately from the
+// invalid ones
+func (s *StoreSchema) Validate(store map[string]interface{}) *ValidationResult {
+	result := NewValidationResult()
+
+	for _, key := range s.Required {
+		if store[key] == nil {
+			result.AddMissing(key)
+		}
+	}
+
+	for key, value := range store {
+		prop, ok := s.Properties[key]
+		if !ok || value == nil {
+			continue
+		}
+		if err := prop.Check(value); err != nil {
+			result.AddInvalid(key, err)
+		}
+	}
+
+	for key, needFields := range s.DependentRequired {
+		if store[key] == nil {
+			continue
+		}
+		var missing []string
+		for _, field := range needFields {
+			if store[field] == nil {
+				missing = append(missing, field)
+			}
+		}
+		if len(missing) > 0 {
+			result.AddInvalid(key, MissingDependencyError(missing...))
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,107 @@
+package static
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOptionsURIEscapesValues(t *testing.T) {
+	element := &StoreElement{Type: "select_uri", Values: []string{"/guilds/${guild}/channels?search=${search}"}}
+
+	tests := []struct {
+		values map[string]string
+		want   string
+	}{
+		{map[string]string{"guild": "42", "search": "general"}, "/guilds/42/channels?search=general"},
+		{map[string]string{}, "/guilds/default/channels?search=default"},
+		{map[string]string{"guild": "../../users/@me", "search": "a&b=c"}, "/guilds/..%2F..%2Fusers%2F@me/channels?search=a%26b%3Dc"},
+		{map[string]string{"guild": "1?admin=true#x", "search": "x y"}, "/guilds/1%3Fadmin=true%23x/channels?search=x+y"},
+		{map[string]string{"guild": "..", "search": ".."}, "/guilds/default/channels?search=.."},
+	}
+	for _, tt := range tests {
+		got := element.OptionsURI(func(key string) string { return tt.values[key] })
+		if got != tt.want {
+			t.Errorf("OptionsURI(%v) = %q, want %q", tt.values, got, tt.want)
+		}
+	}
+}
+
+// It returns an area with a field of each kind
+func schemaTestArea() *ServiceArea {
+	return &ServiceArea{
+		Name:        "new_message",
+		Description: "When a message is posted",
+		RequestStore: map[string]StoreElement{
+			"req:guild:id":   {Priority: 0, Type: "select_uri", Required: true, Values: []string{"/guilds"}},
+			"req:channel:id": {Priority: 1, Type: "select_uri", Required: true, NeedFields: []string{"req:guild:id"}, Values: []string{"/guilds/${req:guild:id}/channels"}},
+			"req:mail":       {Priority: 2, Type: "email", Description: "Notified address"},
+			"req:mode":       {Priority: 2, Type: "select", Values: []string{"all", "mentions"}},
+			"req:content":    {Priority: 3, Type: "long_string", AllowedComponents: []string{"discord:message:content"}},
+		},
+	}
+}
+
+func TestServiceAreaSchema(t *testing.T) {
+	service := &Service{Name: "discord"}
+	schema := schemaTestArea().Schema(service, "action")
+
+	if schema.Schema != JSONSchemaDialect || schema.ID != "/services/discord/actions/new_message/schema" || schema.Type != "object" {
+		t.Errorf("unexpected header %q %q %q", schema.Schema, schema.ID, schema.Type)
+	}
+	if !reflect.DeepEqual(schema.Required, []string{"req:channel:id", "req:guild:id"}) {
+		t.Errorf("unexpected required fields %v", schema.Required)
+	}
+	if !reflect.DeepEqual(schema.DependentRequired, map[string][]string{"req:channel:id": {"req:guild:id"}}) {
+		t.Errorf("unexpected dependencies %v", schema.DependentRequired)
+	}
+	// Sorted by priority, then by name
+	if !reflect.DeepEqual(schema.Order, []string{"req:guild:id", "req:channel:id", "req:mail", "req:mode", "req:content"}) {
+		t.Errorf("unexpected order %v", schema.Order)
+	}
+
+	channel := schema.Properties["req:channel:id"]
+	if channel.UI.Widget != "remote_select" || channel.UI.Options == nil {
+		t.Fatalf("expected a remote select, got %+v", channel.UI)
+	}
+	expected := &OptionsSource{
+		URI:       "/services/discord/api/guilds/${req:guild:id}/channels",
+		DependsOn: []string{"req:guild:id"},
+		Endpoint:  "/services/discord/options/new_message/req:channel:id",
+	}
+	if !reflect.DeepEqual(channel.UI.Options, expected) {
+		t.Errorf("expected the options %+v, got %+v", expected, channel.UI.Options)
+	}
+
+	if mail := schema.Properties["req:mail"]; mail.Format != "email" || mail.UI.Widget != "email" || mail.Description != "Notified address" {
+		t.Errorf("unexpected email field %+v", mail)
+	}
+	if mode := schema.Properties["req:mode"]; !reflect.DeepEqual(mode.Enum, []string{"all", "mentions"}) || mode.UI.Widget != "select" {
+		t.Errorf("unexpected select field %+v", mode)
+	}
+	if content := schema.Properties["req:content"]; content.AnyOf != nil || content.UI.Widget != "textarea" {
+		t.Errorf("the fields of an action can't be filled with components, got %+v", content)
+	}
+}
+
+func TestServiceAreaSchemaReaction(t *testing.T) {
+	schema := schemaTestArea().Schema(&Service{Name: "discord"}, "reaction")
+	if schema.ID != "/services/discord/reactions/new_message/schema" {
+		t.Errorf("unexpected ID %q", schema.ID)
+	}
+
+	// The fields of a reaction accept the components, or only the allowed ones
+	content := schema.Properties["req:content"]
+	if len(content.AnyOf) != 2 || content.UI == nil || content.UI.Widget != "textarea" {
+		t.Fatalf("expected the value or a component, got %+v", content)
+	}
+	if content.Check("{{discord:message:content}}") != nil || content.Check("plain text") != nil {
+		t.Error("expected a text or the allowed component to be valid")
+	}
+	mail := schema.Properties["req:mail"]
+	if mail.Check("{{github:user:email}}") != nil || mail.Check("john@example.com") != nil {
+		t.Error("expected an address or any component to be valid")
+	}
+	if mail.Check("not an address") == nil {
+		t.Error("expected an invalid address to be rejected")
+	}
+}
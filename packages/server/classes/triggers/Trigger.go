@@ -2,10 +2,14 @@ package triggers
 
 import (
 	"area-server/classes/shared"
+	"area-server/classes/static"
 	"area-server/db/postgres"
 	"area-server/db/postgres/models"
 	"area-server/utils"
+	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -34,6 +38,9 @@ type Trigger struct {
 	OnOff         chan bool
 	Interrupt     chan bool
 	Update        chan []string
+
+	mutex  sync.Mutex
+	cancel context.CancelFunc // Cancels the requests of the current run
 }
 
 // It creates a new trigger for an applet
@@ -93,9 +100,29 @@ func (t *Trigger) Stop() error {
 	return nil
 }
 
+// It cancels the requests in progress of the current run
+func (t *Trigger) Cancel() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.cancel != nil {
+		t.cancel()
+	}
+}
+
 // A function that listens to the trigger.
 func (t *Trigger) Listen() error {
 
+	ctx, cancel := context.WithCancel(context.Background())
+	// The validators of the previous runs are dropped, the first check must see the whole resources
+	ctx = utils.WithConditionalScope(ctx, t.AppletID.String())
+	utils.Validators.Forget(t.AppletID.String())
+	defer utils.Validators.Forget(t.AppletID.String())
+	t.mutex.Lock()
+	t.cancel = cancel
+	t.mutex.Unlock()
+	defer cancel()
+
 	logger := shared.NewLogger(t.AppletID)
 	if logger == nil {
 		return fmt.Errorf("Trigger: Error while creatin logger !")
@@ -135,12 +162,29 @@ func (t *Trigger) Listen() error {
 			}
 
 			if err := t.EmitterArea.Refresh(); err != nil {
-				logger.WriteError("Refreshing Token Failed !")
+				if errors.Is(err, static.ErrReauthRequired) {
+					logger.WriteError("Authorization must be renewed, reconnect the service !")
+				} else {
+					logger.WriteError("Refreshing Token Failed :> " + err.Error())
+				}
 				return t.Stop()
 			}
 
 			logger.WriteInfo("Check if action is triggered !", false)
-			emResponse := t.EmitterArea.Call(t.AppletID, logger, nil)
+			emResponse := t.EmitterArea.Call(ctx, t.AppletID, logger, nil)
+			if ctx.Err() != nil {
+				logger.WriteInfo("Stop Application !", true)
+				return t.Stop()
+			}
+			var rateErr *utils.RateLimitError
+			if errors.As(emResponse.Error, &rateErr) {
+				logger.WriteInfo("Action rate limited by the service, retry in "+wtime.String(), false)
+				continue
+			}
+			if errors.Is(emResponse.Error, utils.ErrNotModified) {
+				logger.WriteInfo("Action not modified retry in "+wtime.String(), false)
+				continue
+			}
 			if emResponse.Error != nil {
 				logger.WriteError("Action provide an error :> " + emResponse.Error.Error())
 				return t.Stop()
@@ -158,7 +202,16 @@ func (t *Trigger) Listen() error {
 					return t.Stop()
 				}
 
-				if rcResponse := receiver.Call(t.AppletID, logger, emResponse.Data); rcResponse.Error != nil {
+				rcResponse := receiver.Call(ctx, t.AppletID, logger, emResponse.Data)
+				if ctx.Err() != nil {
+					logger.WriteInfo("Stop Application !", true)
+					return t.Stop()
+				}
+				if errors.As(rcResponse.Error, &rateErr) {
+					logger.WriteError("Reaction rate limited by the service :> " + rcResponse.Error.Error())
+					continue
+				}
+				if rcResponse.Error != nil {
 					logger.WriteError("Reaction provide an error :> " + rcResponse.Error.Error())
 					return t.Stop()
 				}
@@ -6,8 +6,11 @@ import (
 	"area-server/db/postgres"
 	"area-server/db/postgres/models"
 	"area-server/services"
+	"area-server/utils"
+	"context"
 	"encoding/json"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -127,13 +130,21 @@ func (a *TriggerArea) Update(appletID uuid.UUID, area *models.Area, areatype str
 	return nil
 }
 
-// Calling the `Method` function of the `Area` struct.
+// Calling the `Method` function of the `Area` struct, the requests sent to the service are cancelled
+// with the context (or after the timeout of the service).
 func (a *TriggerArea) Call(
+	ctx context.Context,
 	appletID uuid.UUID,
 	logger *shared.Logger,
 	externaldata map[string]interface{},
 ) shared.AreaResponse {
-	return a.Area.Method(static.AreaRequest{
+	if a.Service.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, a.Service.Timeout)
+		defer cancel()
+	}
+
+	response := a.Area.Method(static.AreaRequest{
 		AppletID:      appletID,
 		Authorization: a.Authorization,
 		Service:       a.Service,
@@ -141,7 +152,14 @@ func (a *TriggerArea) Call(
 		Store:         &a.Store,
 		AuthStore:     a.AuthStore,
 		ExternalData:  externaldata,
+		Context:       ctx,
 	})
+
+	// The calls interrupted by the stop of the applet are not counted
+	if !errors.Is(ctx.Err(), context.Canceled) {
+		utils.Stats.Record(a.Service.Name, response.Error, time.Now())
+	}
+	return response
 }
 
 // This function is refreshing the authorization.
@@ -8,19 +8,171 @@ const (
 	Token
 )
 
+// `EmailConfig` configures how the email addresses of the area settings are validated.
+// @property {bool} CheckMX - If true, the domain of the address must have a MX (or A) record.
+// @property {bool} RemoteCheck - If true, the address is also verified by isitarealemail.com.
+// @property {int} CacheDuration - The duration (in seconds) a validation result is kept in cache.
+type EmailConfig struct {
+	CheckMX       bool
+	RemoteCheck   bool
+	CacheDuration int
+}
+
+// `EgressConfig` is the policy applied to the requests sent by the server (services, webhooks).
+// @property {bool} AllowPrivate - If true, the private, loopback and link-local addresses can be reached.
+// @property {[]string} Allow - The hosts (e.g. `api.example.com`, `.example.com` for the sub domains) or
+// CIDRs (e.g. `10.0.0.5/32`) that can be reached even if they are private.
+// @property {[]string} Deny - The hosts or CIDRs that can never be reached.
+// @property {int64} MaxResponseSize - The maximum size (in bytes) of a response body.
+// @property {int} MaxRedirects - The maximum number of redirects followed by a request.
+type EgressConfig struct {
+	AllowPrivate    bool
+	Allow           []string
+	Deny            []string
+	MaxResponseSize int64
+	MaxRedirects    int
+}
+
+// `PasswordConfig` configures how the passwords of the local accounts are hashed.
+// @property {string} Algorithm - The algorithm of the new hashes ("argon2id" or "bcrypt").
+// @property {uint32} Argon2Time - The number of iterations of argon2id.
+// @property {uint32} Argon2Memory - The memory (in KiB) used by argon2id.
+// @property {uint8} Argon2Threads - The number of threads used by argon2id.
+// @property {uint32} Argon2KeyLength - The length (in bytes) of the argon2id hash.
+// @property {int} BcryptCost - The cost of bcrypt.
+type PasswordConfig struct {
+	Algorithm       string
+	Argon2Time      uint32
+	Argon2Memory    uint32
+	Argon2Threads   uint8
+	Argon2KeyLength uint32
+	BcryptCost      int
+}
+
+// `RefresherConfig` is the configuration of the background refresh of the OAuth2 tokens.
+// @property {int} Interval - The time (in seconds) between two checks of the authorizations.
+// @property {int} Ahead - The tokens expiring in less than `Ahead` seconds are refreshed.
+type RefresherConfig struct {
+	Interval int
+	Ahead    int
+}
+
+// `HealthCheckConfig` is the configuration of the background health checks of the authorizations.
+// @property {int} Interval - The time (in seconds) between two runs of the checks.
+// @property {int} Every - The time (in seconds) between two checks of the same authorization.
+// @property {int} Batch - The maximum number of authorizations checked by a run.
+type HealthCheckConfig struct {
+	Interval int
+	Every    int
+	Batch    int
+}
+
+// `TeardownConfig` is the configuration of the background deletion of the accounts.
+// @property {int} Interval - The time (in seconds) between two runs of the pending deletions.
+// @property {string} Archive - If not empty, the logs and the avatar of a deleted account are moved in
+// `<Archive>/<account uuid>` instead of being deleted.
+type TeardownConfig struct {
+	Interval int
+	Archive  string
+}
+
+// `AccountConfig` configures the verification and the recovery of the local accounts.
+// @property {int} VerificationDuration - The lifetime (in seconds) of an email verification token.
+// @property {int} ResetDuration - The lifetime (in seconds) of a password reset token.
+// @property {int} MailCooldown - The minimum time (in seconds) between two mails of the same kind to
+// an address.
+type AccountConfig struct {
+	VerificationDuration int
+	ResetDuration        int
+	MailCooldown         int
+}
+
+// `CookieConfig` configures the session cookie (session mode).
+// @property {string} SameSite - The SameSite attribute ("Lax", "Strict" or "None" if the client is served
+// from another site, the cookie is then only sent over HTTPS).
+// @property {bool} Secure - If true, the cookie is only sent over HTTPS (forced by `HTTPS` and "None").
+type CookieConfig struct {
+	SameSite string
+	Secure   bool
+}
+
 // `Config` is a struct that contains a `ServerMode` (which is an enum), an `int`, and a `bool`.
 // @property {ServerMode} Mode - This is the mode of the server. It can be either "dev" or "prod".
-// @property {int} TokenDuration - The duration of the token in seconds.
+// @property {int} TokenDuration - The duration of the refresh token (token mode) in seconds.
+// @property {int} AccessTokenDuration - The duration of the access token (token mode) in seconds.
 // @property {bool} HTTPS - If true, the server will run on HTTPS.
+// @property {EmailConfig} Email - The configuration of the email validation.
+// @property {bool} Debug - If true, the requests sent to the services are logged.
+// @property {EgressConfig} Egress - The policy of the requests sent by the server.
+// @property {PasswordConfig} Password - The hashing of the passwords.
+// @property {RefresherConfig} Refresher - The background refresh of the OAuth2 tokens.
+// @property {HealthCheckConfig} HealthCheck - The background health checks of the authorizations.
+// @property {TeardownConfig} Teardown - The background deletion of the accounts.
+// @property {AccountConfig} Account - The verification and the recovery of the accounts.
+// @property {CookieConfig} Cookie - The session cookie (session mode).
 type Config struct {
-	Mode          ServerMode
-	TokenDuration int
-	HTTPS         bool
+	Mode                ServerMode
+	TokenDuration       int
+	AccessTokenDuration int
+	HTTPS               bool
+	Email               EmailConfig
+	Debug               bool
+	Egress              EgressConfig
+	Password            PasswordConfig
+	Refresher           RefresherConfig
+	HealthCheck         HealthCheckConfig
+	Teardown            TeardownConfig
+	Account             AccountConfig
+	Cookie              CookieConfig
 }
 
 // Creating a global variable called CFG that is a pointer to a Config struct.
 var CFG = &Config{
-	Mode:          Token,
-	TokenDuration: 60 * 60 * 24 * 7,
-	HTTPS:         false,
+	Mode:                Token,
+	TokenDuration:       60 * 60 * 24 * 7,
+	AccessTokenDuration: 60 * 15,
+	HTTPS:               false,
+	Email: EmailConfig{
+		CheckMX:       false,
+		RemoteCheck:   false,
+		CacheDuration: 60 * 60,
+	},
+	Debug: false,
+	Egress: EgressConfig{
+		AllowPrivate:    false,
+		Allow:           []string{},
+		Deny:            []string{},
+		MaxResponseSize: 10 << 20,
+		MaxRedirects:    5,
+	},
+	Password: PasswordConfig{
+		Algorithm:       "argon2id",
+		Argon2Time:      2,
+		Argon2Memory:    19 * 1024,
+		Argon2Threads:   1,
+		Argon2KeyLength: 32,
+		BcryptCost:      12,
+	},
+	Refresher: RefresherConfig{
+		Interval: 60,
+		Ahead:    5 * 60,
+	},
+	HealthCheck: HealthCheckConfig{
+		Interval: 5 * 60,
+		Every:    6 * 60 * 60,
+		Batch:    50,
+	},
+	Teardown: TeardownConfig{
+		Interval: 60,
+		Archive:  "",
+	},
+	Account: AccountConfig{
+		VerificationDuration: 60 * 60 * 24,
+		ResetDuration:        60 * 60,
+		MailCooldown:         60,
+	},
+	Cookie: CookieConfig{
+		SameSite: "Lax",
+		Secure:   false,
+	},
 }
@@ -0,0 +1,265 @@
+package encryption
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+)
+
+/*
+ * Envelope encryption of the secrets stored in the database (e.g. the OAuth2 tokens).
+ *
+ * Each value is encrypted with its own data key (AES-256-GCM), the data key is encrypted with a key
+ * of the keyring (the key encryption key). The ID of this key is stored with the ciphertext:
+ *
+ * enc:v1:<key id>:<encrypted data key>:<nonce + encrypted value>
+ *
+ * The keys are loaded from the environment:
+ * AREA_ENCRYPTION_KEYS="<id>:<base64 key>,<id>:<base64 key>" or AREA_ENCRYPTION_KEY_FILE=<path> (one
+ * "<id>:<base64 key>" per line), the keys must be 32 bytes long. The active key (used to encrypt) is
+ * AREA_ENCRYPTION_KEY_ID, or the first key. The other keys are only used to decrypt, until the values
+ * are encrypted again with the active key (`go run ./migration -rotate-keys`).
+ */
+
+// Prefix of the encrypted values, the values without it are stored in plain text (before encryption)
+const Prefix = "enc:v1:"
+
+// Length (in bytes) of the keys
+const KeyLength = 32
+
+var (
+	ErrUnknownKey        = errors.New("Encryption: unknown key")
+	ErrInvalidCiphertext = errors.New("Encryption: invalid ciphertext")
+)
+
+// `Keyring` contains the key encryption keys, indexed by their ID.
+// @property {string} Active - The ID of the key used to encrypt the new values.
+type Keyring struct {
+	Active string
+	keys   map[string][]byte
+}
+
+var (
+	keyring     *Keyring
+	keyringOnce sync.Once
+	keyringLock sync.RWMutex
+)
+
+// It creates a keyring from a list of "<id>:<base64 key>", the active key is the first one if not set
+func NewKeyring(entries []string, active string) (*Keyring, error) {
+	k := &Keyring{Active: active, keys: make(map[string][]byte)}
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+		id, encoded, ok := strings.Cut(entry, ":")
+		if !ok || id == "" {
+			return nil, fmt.Errorf("Encryption: invalid key entry (expected <id>:<base64 key>)")
+		}
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil || len(key) != KeyLength {
+			return nil, fmt.Errorf("Encryption: key %q must be %d bytes encoded in base64", id, KeyLength)
+		}
+		if _, exists := k.keys[id]; exists {
+			return nil, fmt.Errorf("Encryption: duplicate key %q", id)
+		}
+		k.keys[id] = key
+		if k.Active == "" {
+			k.Active = id
+		}
+	}
+	if _, ok := k.keys[k.Active]; len(k.keys) > 0 && !ok {
+		return nil, fmt.Errorf("Encryption: active key %q not found", k.Active)
+	}
+	return k, nil
+}
+
+// It loads the keyring from the environment (the keys of the file are added after the variable)
+func LoadKeyring() (*Keyring, error) {
+	var entries []string
+	if keys := os.Getenv("AREA_ENCRYPTION_KEYS"); keys != "" {
+		entries = append(entries, strings.Split(keys, ",")...)
+	}
+	if path := os.Getenv("AREA_ENCRYPTION_KEY_FILE"); path != "" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			entries = append(entries, scanner.Text())
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return NewKeyring(entries, os.Getenv("AREA_ENCRYPTION_KEY_ID"))
+}
+
+// It returns the keyring of the server, loaded on first use (panics if the keys are invalid)
+func Keys() *Keyring {
+	keyringOnce.Do(func() {
+		k, err := LoadKeyring()
+		if err != nil {
+			panic(err)
+		}
+		if !k.Enabled() {
+			log.Println("[Encryption] No key set (AREA_ENCRYPTION_KEYS), the tokens are stored in plain text !")
+		}
+		keyringLock.Lock()
+		if keyring == nil {
+			keyring = k
+		}
+		keyringLock.Unlock()
+	})
+	keyringLock.RLock()
+	defer keyringLock.RUnlock()
+	return keyring
+}
+
+// It replaces the keyring of the server (e.g. in the tests)
+func SetKeys(k *Keyring) {
+	keyringOnce.Do(func() {})
+	keyringLock.Lock()
+	defer keyringLock.Unlock()
+	keyring = k
+}
+
+// Enabled returns true if the keyring has an active key
+func (k *Keyring) Enabled() bool {
+	return k != nil && len(k.keys) > 0
+}
+
+// It encrypts the value with AES-256-GCM, the nonce is prepended to the ciphertext
+func seal(key []byte, plaintext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// It decrypts a value encrypted by `seal`
+func open(key []byte, ciphertext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, ErrInvalidCiphertext
+	}
+	plaintext, err := gcm.Open(nil, ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, ErrInvalidCiphertext
+	}
+	return plaintext, nil
+}
+
+// Encrypt encrypts the value with a new data key wrapped by the active key, the value is returned as
+// is if the keyring has no key
+func (k *Keyring) Encrypt(plaintext []byte) (string, error) {
+	if !k.Enabled() {
+		return string(plaintext), nil
+	}
+	dataKey := make([]byte, KeyLength)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", err
+	}
+	wrapped, err := seal(k.keys[k.Active], dataKey)
+	if err != nil {
+		return "", err
+	}
+	payload, err := seal(dataKey, plaintext)
+	if err != nil {
+		return "", err
+	}
+	return Prefix + k.Active + ":" +
+		base64.RawStdEncoding.EncodeToString(wrapped) + ":" +
+		base64.RawStdEncoding.EncodeToString(payload), nil
+}
+
+// Decrypt decrypts a value encrypted by `Encrypt`, the values stored in plain text are returned as is
+func (k *Keyring) Decrypt(value string) ([]byte, error) {
+	if !IsEncrypted(value) {
+		return []byte(value), nil
+	}
+	parts := strings.Split(strings.TrimPrefix(value, Prefix), ":")
+	if len(parts) != 3 {
+		return nil, ErrInvalidCiphertext
+	}
+	var key []byte
+	if k != nil {
+		key = k.keys[parts[0]]
+	}
+	if key == nil {
+		return nil, fmt.Errorf("%w %q", ErrUnknownKey, parts[0])
+	}
+	wrapped, err := base64.RawStdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrInvalidCiphertext
+	}
+	payload, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrInvalidCiphertext
+	}
+	dataKey, err := open(key, wrapped)
+	if err != nil {
+		return nil, err
+	}
+	return open(dataKey, payload)
+}
+
+// NeedsRotation returns true if the value is not encrypted with the active key
+func (k *Keyring) NeedsRotation(value string) bool {
+	if value == "" || !k.Enabled() {
+		return false
+	}
+	return KeyID(value) != k.Active
+}
+
+// IsEncrypted returns true if the value was encrypted by `Encrypt`
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, Prefix)
+}
+
+// KeyID returns the ID of the key that encrypted the value (empty if the value is in plain text)
+func KeyID(value string) string {
+	if !IsEncrypted(value) {
+		return ""
+	}
+	id, _, _ := strings.Cut(strings.TrimPrefix(value, Prefix), ":")
+	return id
+}
+
+// Fingerprint returns the SHA-256 of a token, to find a token without decrypting all of them
+func Fingerprint(token string) string {
+	if token == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,136 @@
+package encryption
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"gorm.io/gorm/schema"
+)
+
+// It returns a "<id>:<base64 key>" entry filled with the byte b
+func keyEntry(id string, b byte) string {
+	return id + ":" + base64.StdEncoding.EncodeToString([]byte(strings.Repeat(string(b), KeyLength)))
+}
+
+// It replaces the keyring of the server for the duration of the test
+func useKeys(t *testing.T, k *Keyring) {
+	previous := Keys()
+	SetKeys(k)
+	t.Cleanup(func() { SetKeys(previous) })
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	keys, err := NewKeyring([]string{keyEntry("2023-01", 'a')}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	value, err := keys.Encrypt([]byte("gho_secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(value, "enc:v1:2023-01:") || strings.Contains(value, "gho_secret") || KeyID(value) != "2023-01" {
+		t.Fatalf("unexpected ciphertext %q", value)
+	}
+	if other, _ := keys.Encrypt([]byte("gho_secret")); other == value {
+		t.Error("each value must be encrypted with its own data key")
+	}
+	if plaintext, err := keys.Decrypt(value); err != nil || string(plaintext) != "gho_secret" {
+		t.Errorf("unexpected plaintext %q %v", plaintext, err)
+	}
+
+	// Stored before the encryption
+	if plaintext, err := keys.Decrypt("legacy_token"); err != nil || string(plaintext) != "legacy_token" {
+		t.Errorf("expected the plain text value, got %q %v", plaintext, err)
+	}
+
+	// Tampered ciphertext
+	if _, err := keys.Decrypt(value[:len(value)-2] + "AA"); !errors.Is(err, ErrInvalidCiphertext) {
+		t.Errorf("expected an invalid ciphertext, got %v", err)
+	}
+}
+
+func TestKeyRotation(t *testing.T) {
+	old, _ := NewKeyring([]string{keyEntry("old", 'a')}, "")
+	value, _ := old.Encrypt([]byte("token"))
+
+	// The new key is active, the old one is kept to decrypt
+	rotated, err := NewKeyring([]string{keyEntry("old", 'a'), keyEntry("new", 'b')}, "new")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rotated.NeedsRotation(value) || !rotated.NeedsRotation("plain") || rotated.NeedsRotation("") {
+		t.Error("expected the values of the old key and in plain text to be rotated")
+	}
+	if plaintext, err := rotated.Decrypt(value); err != nil || string(plaintext) != "token" {
+		t.Errorf("unexpected plaintext %q %v", plaintext, err)
+	}
+	reencrypted, _ := rotated.Encrypt([]byte("token"))
+	if rotated.NeedsRotation(reencrypted) {
+		t.Error("the value is encrypted with the active key")
+	}
+
+	// The old key is removed
+	current, _ := NewKeyring([]string{keyEntry("new", 'b')}, "")
+	if _, err := current.Decrypt(value); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("expected an unknown key, got %v", err)
+	}
+}
+
+func TestInvalidKeyring(t *testing.T) {
+	for _, entries := range [][]string{
+		{"no-separator"},
+		{"short:" + base64.StdEncoding.EncodeToString([]byte("short"))},
+		{keyEntry("dup", 'a'), keyEntry("dup", 'b')},
+	} {
+		if _, err := NewKeyring(entries, ""); err == nil {
+			t.Errorf("expected %v to be rejected", entries)
+		}
+	}
+	if _, err := NewKeyring([]string{keyEntry("a", 'a')}, "missing"); err == nil {
+		t.Error("expected the active key to be required")
+	}
+	if keys, err := NewKeyring(nil, ""); err != nil || keys.Enabled() {
+		t.Error("an empty keyring is disabled")
+	}
+}
+
+func TestSerializer(t *testing.T) {
+	keys, _ := NewKeyring([]string{keyEntry("k1", 'a')}, "")
+	useKeys(t, keys)
+
+	type row struct {
+		Token string `gorm:"serializer:encrypted"`
+		Other []byte `gorm:"serializer:encrypted"`
+	}
+	s, err := schema.Parse(&row{}, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	token, other := s.LookUpField("Token"), s.LookUpField("Other")
+
+	stored, err := Serializer{}.Value(ctx, token, reflect.ValueOf(&row{}), "secret")
+	if err != nil || !IsEncrypted(stored.(string)) {
+		t.Fatalf("expected an encrypted value, got %v %v", stored, err)
+	}
+	if empty, _ := (Serializer{}).Value(ctx, other, reflect.ValueOf(&row{}), []byte(nil)); empty != nil {
+		t.Errorf("expected NULL for an empty value, got %v", empty)
+	}
+
+	loaded := &row{}
+	if err := (Serializer{}).Scan(ctx, token, reflect.ValueOf(loaded), []byte(stored.(string))); err != nil {
+		t.Fatal(err)
+	}
+	if err := (Serializer{}).Scan(ctx, other, reflect.ValueOf(loaded), `{"legacy":true}`); err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Token != "secret" || string(loaded.Other) != `{"legacy":true}` {
+		t.Errorf("unexpected row %+v", loaded)
+	}
+}
@@ -0,0 +1,72 @@
+package encryption
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm/schema"
+)
+
+// `Serializer` encrypts the fields tagged with `serializer:encrypted` when they are saved and decrypts
+// them when they are loaded, the field keeps its type (string or bytes).
+type Serializer struct{}
+
+func init() {
+	schema.RegisterSerializer("encrypted", Serializer{})
+}
+
+// Scan decrypts the value of the database into the field
+func (Serializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	fieldValue := reflect.New(field.FieldType).Elem()
+
+	var stored string
+	switch value := dbValue.(type) {
+	case nil:
+	case string:
+		stored = value
+	case []byte:
+		stored = string(value)
+	default:
+		return fmt.Errorf("Encryption: unsupported value %T for %s", dbValue, field.Name)
+	}
+
+	if stored != "" {
+		plaintext, err := Keys().Decrypt(stored)
+		if err != nil {
+			return fmt.Errorf("%s: %w", field.Name, err)
+		}
+		switch field.FieldType.Kind() {
+		case reflect.String:
+			fieldValue = reflect.ValueOf(string(plaintext)).Convert(field.FieldType)
+		case reflect.Slice:
+			fieldValue = reflect.ValueOf(plaintext).Convert(field.FieldType)
+		default:
+			return fmt.Errorf("Encryption: unsupported field %s", field.Name)
+		}
+	}
+
+	field.ReflectValueOf(ctx, dst).Set(fieldValue)
+	return nil
+}
+
+// Value encrypts the field, the empty values are not encrypted (NULL for the bytes)
+func (Serializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	value := reflect.ValueOf(fieldValue)
+	var plaintext []byte
+	switch value.Kind() {
+	case reflect.String:
+		if value.Len() == 0 {
+			return "", nil
+		}
+		plaintext = []byte(value.String())
+	case reflect.Slice:
+		if value.Len() == 0 {
+			return nil, nil
+		}
+		plaintext = value.Bytes()
+	default:
+		return nil, fmt.Errorf("Encryption: unsupported field %s", field.Name)
+	}
+	return Keys().Encrypt(plaintext)
+}
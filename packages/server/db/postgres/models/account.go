@@ -13,6 +13,10 @@ import (
 // @property {string} Email - The email address of the account.
 // @property {string} Username - The username of the account.
 // @property {string} Password - The password for the account. This is only used for local accounts.
+// @property {bool} EmailVerified - If true, the owner of the account proved they own the email address
+// (always true for the accounts created with an external authenticator).
+// @property {string} Role - The role of the account (`RoleUser` or `RoleAdmin`).
+// @property {bool} Disabled - If true, the account was disabled by an administrator and can't be used.
 // @property CreatedAt - The time the account was created.
 // @property UpdatedAt - This is the time the account was last updated.
 type Account struct {
@@ -21,6 +25,20 @@ type Account struct {
 	Email         string    `gorm:"unique" json:"email"`
 	Username      string    `gorm:"default:'noob'" json:"username"`
 	Password      string    `json:"-"` // Only used for local accounts (Service = "@local")
+	EmailVerified bool      `gorm:"default:false" json:"email_verified"`
+	Role          string    `gorm:"not null;default:'user'" json:"role"`
+	Disabled      bool      `gorm:"default:false" json:"disabled"`
 	CreatedAt     time.Time `gorm:"autoCreateTime" json:"-"`
 	UpdatedAt     time.Time `gorm:"autoUpdateTime" json:"-"`
 }
+
+// Roles of the accounts
+const (
+	RoleUser  = "user"  // Default role
+	RoleAdmin = "admin" // Can use the administration routes (`/admin`)
+)
+
+// IsAdmin returns true if the account can use the administration routes
+func (a *Account) IsAdmin() bool {
+	return a.Role == RoleAdmin && !a.Disabled
+}
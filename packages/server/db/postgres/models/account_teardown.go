@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+/*
+ * Deletion of an account, done in the background by steps (see accounts/teardown.go):
+ *
+ * triggers - The triggers of the applets are stopped and unregistered, with their webhooks
+ * tokens   - The tokens of the authorizations are revoked at the providers, the sessions and tokens
+ *            of the account are revoked
+ * account  - The account is deleted (with its applets, areas, authorizations, ...)
+ * files    - The logs of the applets and the avatar are deleted (or archived)
+ *
+ * The teardown is deleted once the last step is done. A step that fails is tried again later
+ * (NextRunAt), from where it stopped.
+ */
+
+// The steps of a teardown, in order
+const (
+	TeardownTriggers = "triggers"
+	TeardownTokens   = "tokens"
+	TeardownAccount  = "account"
+	TeardownFiles    = "files"
+)
+
+// AccountTeardown -> the deletion in progress of an account (no foreign key, the account is deleted
+// before the end)
+type AccountTeardown struct {
+	AccountUUID uuid.UUID `gorm:"primaryKey" json:"account_id"`
+	Email       string    `gorm:"not null" json:"email"`
+	Applets     []string  `gorm:"type:text;serializer:json" json:"applets"`
+	Step        string    `gorm:"not null;default:'triggers'" json:"step"`
+	Attempts    int       `gorm:"default:0" json:"attempts"`
+	LastError   string    `json:"last_error,omitempty"`
+	NextRunAt   time.Time `gorm:"index" json:"next_run_at"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+/*
+ * Example of an API key:
+ *
+ * A key used by a CI script to trigger the webhooks of the account:
+ * UUID: <uuid>
+ * AccountUUID: <account_uuid>
+ * Name: "ci"
+ * Prefix: "area_3f9a1c2b" - Displayed to the user to recognize the key
+ * Hash: <sha256 of the key> - The key itself is only returned when it is created
+ * Scopes: ["webhooks:trigger"]
+ * ExpireAt: null - The key never expires
+ */
+
+// APIKey -> Many to One -> Account
+type APIKey struct {
+	UUID        uuid.UUID  `gorm:"primaryKey" json:"id"`
+	Account     Account    `gorm:"foreignKey:AccountUUID;references:UUID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+	AccountUUID uuid.UUID  `gorm:"not null;index" json:"-"`
+	Name        string     `gorm:"not null" json:"name"`
+	Prefix      string     `gorm:"not null" json:"prefix"`
+	Hash        string     `gorm:"not null;uniqueIndex" json:"-"`
+	Scopes      []string   `gorm:"serializer:json;not null" json:"scopes"`
+	ExpireAt    *time.Time `json:"expire_at"`    // Null if the key never expires
+	LastUsedAt  *time.Time `json:"last_used_at"` // Updated at most once per minute
+	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// HasScope returns true if the key grants the scope
+func (k *APIKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Expired returns true if the key can not be used anymore
+func (k *APIKey) Expired(now time.Time) bool {
+	return k.ExpireAt != nil && !now.Before(*k.ExpireAt)
+}
@@ -33,6 +33,7 @@ type Applet struct {
 	Public      bool      `gorm:"default:false" json:"public"`                                                                  // If the applet is public, it can be copied by anyone
 	Active      bool      `gorm:"default:true" json:"active"`                                                                   // If the applet is active, it can be triggered
 	Status      string    `gorm:"default:'stopped'" json:"status"`                                                              // Status of the applet (stopped, running)
+	NeedsReauth bool      `gorm:"default:false" json:"needs_reauth"`                                                            // If true, an authorization used by the applet must be renewed
 	CreatedAt   time.Time `gorm:"autoCreateTime" json:"-"`
 	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"-"`
 }
@@ -1,10 +1,12 @@
 package models
 
 import (
+	"area-server/db/encryption"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/datatypes"
+	"gorm.io/gorm"
 )
 
 /*
@@ -40,21 +42,59 @@ import (
  * Permanent option is used to know if the authorization is linked directly to the account or not.
  * Example: if the user register itself with the Service, the authorization is permanent.
  * but if the user link the service to his account, the authorization is not permanent.
+ *
+ * NeedsReauth is set when the refresh token is revoked (or missing), the user must authorize the
+ * service again.
+ *
+ * Health is the result of the last health check (`CheckedAt`), the tokens are validated in the
+ * background so the applets are marked before they fail.
+ *
+ * An account can have several authorizations for the same service (e.g. a personal and a bot GitHub
+ * account), they are distinguished by their Label (unique per account and service).
+ *
+ * AccessToken, RefreshToken and Other are encrypted in the database (see db/encryption), they are
+ * decrypted when the authorization is loaded. AccessTokenHash is used to find an access token.
  */
 
 // Authorization -> Many to One -> Account
 
 type Authorization struct {
-	UUID         uuid.UUID      `gorm:"primaryKey" json:"-"`
-	Account      Account        `gorm:"foreignKey:AccountUUID;references:UUID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
-	AccountUUID  uuid.UUID      `gorm:"not null" json:"-"`
-	Type         string         `gorm:"not null" json:"type"` // "oauth2", ...
-	AuthService  string         `gorm:"not null" json:"name"` // authenticator
-	AccessToken  string         `gorm:"not null" json:"-"`
-	RefreshToken string         `json:"-"`
-	Other        datatypes.JSON `json:"-"`
-	Permanent    bool           `gorm:"default:false" json:"permanent"` // Permanent=true, means that it can't be deleted
-	ExpireAt     time.Time      `gorm:"not null" json:"expire_at"`
-	CreatedAt    time.Time      `gorm:"autoCreateTime" json:"-"`
-	UpdatedAt    time.Time      `gorm:"autoUpdateTime" json:"-"`
+	UUID            uuid.UUID      `gorm:"primaryKey" json:"-"`
+	Account         Account        `gorm:"foreignKey:AccountUUID;references:UUID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+	AccountUUID     uuid.UUID      `gorm:"not null;uniqueIndex:idx_authorization_label" json:"-"`
+	Type            string         `gorm:"not null" json:"type"`                                                        // "oauth2", ...
+	AuthService     string         `gorm:"not null;uniqueIndex:idx_authorization_label" json:"name"`                    // authenticator
+	Label           string         `gorm:"not null;default:'default';uniqueIndex:idx_authorization_label" json:"label"` // e.g. "personal", "bot"
+	AccessToken     string         `gorm:"not null;serializer:encrypted" json:"-"`
+	RefreshToken    string         `gorm:"serializer:encrypted" json:"-"`
+	Other           datatypes.JSON `gorm:"type:text;serializer:encrypted" json:"-"`
+	Permanent       bool           `gorm:"default:false" json:"permanent"` // Permanent=true, means that it can't be deleted
+	ExpireAt        time.Time      `gorm:"not null" json:"expire_at"`
+	NeedsReauth     bool           `gorm:"default:false" json:"needs_reauth"`
+	Health          string         `gorm:"not null;default:'unknown'" json:"health"` // unknown, healthy, needs_reconnect, error
+	HealthError     string         `json:"health_error,omitempty"`                   // Error of the last check (error)
+	CheckedAt       *time.Time     `json:"checked_at"`                               // Date of the last health check
+	AccessTokenHash string         `gorm:"index" json:"-"`
+	CreatedAt       time.Time      `gorm:"autoCreateTime" json:"-"`
+	UpdatedAt       time.Time      `gorm:"autoUpdateTime" json:"-"`
+}
+
+// Label of the authorizations created without label
+const DefaultAuthorizationLabel = "default"
+
+// Health of the authorizations
+const (
+	HealthUnknown        = "unknown"         // Never checked, or the provider has no endpoint to check it
+	HealthHealthy        = "healthy"         // The provider accepted the token
+	HealthNeedsReconnect = "needs_reconnect" // The token is revoked, the user must authorize the service again
+	HealthError          = "error"           // The check failed (it is retried on the next run of the checks)
+)
+
+// It saves the fingerprint of the access token with the new authorization
+func (a *Authorization) BeforeCreate(tx *gorm.DB) error {
+	if a.Label == "" {
+		a.Label = DefaultAuthorizationLabel
+	}
+	a.AccessTokenHash = encryption.Fingerprint(a.AccessToken)
+	return nil
 }
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+/*
+ * Example of an identity:
+ *
+ * A GitHub login linked to an account registered with a password:
+ * UUID: <uuid>
+ * AccountUUID: <account_uuid>
+ * Authenticator: "github"
+ * Email: "john@example.com" - The email returned by the provider (can differ from the email of the account)
+ */
+
+// Identity -> Many to One -> Account
+// An identity is an external login linked to an account, in addition to the authenticator the account
+// was created with (`Account.Authenticator`).
+type Identity struct {
+	UUID          uuid.UUID `gorm:"primaryKey" json:"id"`
+	Account       Account   `gorm:"foreignKey:AccountUUID;references:UUID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+	AccountUUID   uuid.UUID `gorm:"not null;index" json:"-"`
+	Authenticator string    `gorm:"not null;uniqueIndex:idx_identity_login" json:"authenticator"`
+	Email         string    `gorm:"not null;uniqueIndex:idx_identity_login" json:"email"`
+	CreatedAt     time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+/*
+ * Two-factor authentication of a local account (TOTP).
+ *
+ * The secret is created by the enrolment and encrypted in the database (see db/encryption), the
+ * second factor is only required once a first code is verified (Enabled). LastStep is the period
+ * of the last code used, so a code can't be used twice. RecoveryCodes contains the hashes of the
+ * recovery codes that can still be used (each one once).
+ */
+
+// TwoFactor -> One to One -> Account
+type TwoFactor struct {
+	AccountUUID   uuid.UUID  `gorm:"primaryKey" json:"-"`
+	Account       Account    `gorm:"foreignKey:AccountUUID;references:UUID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+	Secret        string     `gorm:"not null;serializer:encrypted" json:"-"`
+	Enabled       bool       `gorm:"default:false" json:"enabled"`
+	LastStep      int64      `gorm:"default:0" json:"-"`
+	RecoveryCodes []string   `gorm:"type:text;serializer:json" json:"-"`
+	EnabledAt     *time.Time `json:"enabled_at"`
+	CreatedAt     time.Time  `gorm:"autoCreateTime" json:"-"`
+}
@@ -1,6 +1,7 @@
 package postgres
 
 import (
+	"area-server/db/encryption"
 	"area-server/db/postgres/models"
 	"fmt"
 	"os"
@@ -83,16 +84,51 @@ func (d *PSDatabase) String() string {
 // Dropping the tables and then creating them again.
 func (d *PSDatabase) Migrate() error {
 	fmt.Println("Dropping tables...")
-	if DB.Migrator().DropTable(&models.Account{}, &models.Authorization{}, &models.Applet{}, &models.Area{}) != nil {
+	if DB.Migrator().DropTable(&models.Account{}, &models.Authorization{}, &models.Applet{}, &models.Area{}, &models.APIKey{}, &models.TwoFactor{}, &models.Identity{}, &models.AccountTeardown{}) != nil {
 		panic("Failed to drop tables")
 	}
 	fmt.Println("Creating tables...")
-	if DB.AutoMigrate(&models.Account{}, &models.Authorization{}, &models.Applet{}, &models.Area{}, &models.Area{}) != nil {
+	if DB.AutoMigrate(&models.Account{}, &models.Authorization{}, &models.Applet{}, &models.Area{}, &models.Area{}, &models.APIKey{}, &models.TwoFactor{}, &models.Identity{}, &models.AccountTeardown{}) != nil {
 		panic("Failed to migrate databases")
 	}
 	return nil
 }
 
+// Creating the missing tables and columns (added since the database was created), nothing is dropped.
+func (d *PSDatabase) Upgrade() error {
+	verification := DB.Migrator().HasColumn(&models.Account{}, "EmailVerified")
+	if err := DB.AutoMigrate(&models.Account{}, &models.Authorization{}, &models.Applet{}, &models.Area{}, &models.APIKey{}, &models.TwoFactor{}, &models.Identity{}, &models.AccountTeardown{}); err != nil {
+		return err
+	}
+
+	// The addresses of the accounts created with an authenticator were verified by the provider
+	if !verification {
+		if err := DB.Model(&models.Account{}).Where("authenticator <> ?", "@local").Update("email_verified", true).Error; err != nil {
+			return err
+		}
+	}
+	return backfillAccessTokenHashes()
+}
+
+// It fingerprints the access tokens of the authorizations created before `AccessTokenHash`, so they
+// are found by the duplicate check
+func backfillAccessTokenHashes() error {
+	var authorizations []models.Authorization
+	return DB.Select("uuid", "access_token").Where("access_token_hash IS NULL OR access_token_hash = ?", "").
+		FindInBatches(&authorizations, 100, func(tx *gorm.DB, batch int) error {
+			for i := range authorizations {
+				hash := encryption.Fingerprint(authorizations[i].AccessToken)
+				if hash == "" {
+					continue
+				}
+				if result := DB.Model(&authorizations[i]).Update("access_token_hash", hash); result.Error != nil {
+					return result.Error
+				}
+			}
+			return nil
+		}).Error
+}
+
 // Checking if the database is ok.
 func (d *PSDatabase) OK() bool {
 
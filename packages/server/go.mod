@@ -9,6 +9,9 @@ require (
 	github.com/golang-jwt/jwt/v4 v4.4.3
 	github.com/google/uuid v1.3.0
 	github.com/stretchr/testify v1.8.1
+	github.com/valyala/fasthttp v1.44.0
+	golang.org/x/crypto v0.5.0
+	gopkg.in/yaml.v3 v3.0.1
 	gorm.io/datatypes v1.1.0
 	gorm.io/driver/postgres v1.4.6
 	gorm.io/gorm v1.24.3
@@ -51,11 +54,8 @@ require (
 	github.com/sloonz/go-mime-message v0.0.0-20210417175330-cb2e834a9b3b
 	github.com/sloonz/go-qprintable v0.0.0-20160203160305-775b3a4592d5
 	github.com/valyala/bytebufferpool v1.0.0 // indirect
-	github.com/valyala/fasthttp v1.44.0 // indirect
 	github.com/valyala/tcplisten v1.0.0 // indirect
-	golang.org/x/crypto v0.5.0 // indirect
 	golang.org/x/sys v0.5.0 // indirect
 	golang.org/x/text v0.6.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 	gorm.io/driver/mysql v1.4.5 // indirect
 )
@@ -12,7 +12,9 @@ import (
 // It loads all the applets from the database and adds them to the store
 func LoadApplets() error {
 	var applets []models.Applet
-	if result := postgres.DB.Where(&models.Applet{State: "complete"}).Find(&applets); result.Error != nil {
+	// The applets of the accounts being deleted are not loaded again (see accounts/teardown.go)
+	deleted := postgres.DB.Model(&models.AccountTeardown{}).Select("account_uuid")
+	if result := postgres.DB.Where(&models.Applet{State: "complete"}).Where("account_uuid NOT IN (?)", deleted).Find(&applets); result.Error != nil {
 		if result.RowsAffected == 0 {
 			return nil
 		}
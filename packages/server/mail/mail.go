@@ -0,0 +1,249 @@
+package mail
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+/*
+ * Outbound mails (account verification, password reset...).
+ *
+ * The mailer is selected from the environment with MAIL_DRIVER:
+ * - "smtp": SMTP_HOST, SMTP_PORT (587), SMTP_USERNAME and SMTP_PASSWORD (optional)
+ * - "file": the mails are written in MAIL_DIR (./mails), one `.eml` file per mail
+ * - "log": the mails are only printed, for the development (the queries of the links are redacted,
+ *   they contain the verification and reset tokens)
+ * MAIL_DRIVER is required, the server doesn't start without it.
+ * The sender is MAIL_FROM (no-reply@area.local).
+ */
+
+var ErrInvalidHeader = errors.New("Mail: invalid header (line break)")
+var ErrNoDriver = errors.New("Mail: MAIL_DRIVER is not set (smtp, file or log)")
+
+// It matches the query of the links in the mails
+var linkQuery = regexp.MustCompile(`(https?://[^\s?]*)\?\S*`)
+
+// `Message` is a plain text mail.
+// @property {string} To - The address of the recipient.
+// @property {string} Subject - The subject of the mail.
+// @property {string} Body - The content of the mail (plain text).
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// `Mailer` sends the messages.
+type Mailer interface {
+	Send(message Message) error
+}
+
+var (
+	mailer     Mailer
+	mailerOnce sync.Once
+	mailerLock sync.RWMutex
+)
+
+// It returns the sender of the mails
+func from() string {
+	if sender := os.Getenv("MAIL_FROM"); sender != "" {
+		return sender
+	}
+	return "no-reply@area.local"
+}
+
+// It creates the mailer configured in the environment
+func Load() (Mailer, error) {
+	switch driver := os.Getenv("MAIL_DRIVER"); driver {
+	case "smtp":
+		host := os.Getenv("SMTP_HOST")
+		if host == "" {
+			return nil, errors.New("Mail: SMTP_HOST is required by the smtp driver")
+		}
+		port := os.Getenv("SMTP_PORT")
+		if port == "" {
+			port = "587"
+		}
+		return &SMTPMailer{
+			Host:     host,
+			Port:     port,
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     from(),
+		}, nil
+	case "file":
+		dir := os.Getenv("MAIL_DIR")
+		if dir == "" {
+			dir = "./mails"
+		}
+		return &FileMailer{Dir: dir, From: from()}, nil
+	case "log":
+		return &LogMailer{From: from()}, nil
+	case "":
+		return nil, ErrNoDriver
+	default:
+		return nil, fmt.Errorf("Mail: unknown driver %q", driver)
+	}
+}
+
+// It returns the mailer of the server (loaded from the environment on the first call, the mails are
+// only printed without their links if it is invalid)
+func Default() Mailer {
+	mailerOnce.Do(func() {
+		loaded, err := Load()
+		if err != nil {
+			fmt.Println(err.Error() + ", the mails are only printed")
+			loaded = &LogMailer{From: from()}
+		}
+		mailerLock.Lock()
+		if mailer == nil {
+			mailer = loaded
+		}
+		mailerLock.Unlock()
+	})
+
+	mailerLock.RLock()
+	defer mailerLock.RUnlock()
+	return mailer
+}
+
+// It replaces the mailer of the server (e.g. in the tests)
+func SetMailer(m Mailer) {
+	mailerOnce.Do(func() {})
+	mailerLock.Lock()
+	mailer = m
+	mailerLock.Unlock()
+}
+
+// Send sends a message with the mailer of the server
+func Send(message Message) error {
+	return Default().Send(message)
+}
+
+// SendAsync sends a message in the background, the errors are printed
+func SendAsync(message Message) {
+	go func() {
+		if err := Send(message); err != nil {
+			fmt.Println("Mail: could not send \""+message.Subject+"\" :>", err)
+		}
+	}()
+}
+
+// It encodes the message (RFC 5322), the headers can't contain line breaks
+func (m Message) Bytes(sender string) ([]byte, error) {
+	for _, header := range []string{sender, m.To, m.Subject} {
+		if strings.ContainsAny(header, "\r\n") {
+			return nil, ErrInvalidHeader
+		}
+	}
+
+	domain := "area.local"
+	if at := strings.LastIndex(sender, "@"); at != -1 {
+		domain = strings.Trim(sender[at+1:], "> ")
+	}
+
+	var buffer bytes.Buffer
+	fmt.Fprintf(&buffer, "From: %s\r\n", sender)
+	fmt.Fprintf(&buffer, "To: %s\r\n", m.To)
+	fmt.Fprintf(&buffer, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", m.Subject))
+	fmt.Fprintf(&buffer, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&buffer, "Message-ID: <%s@%s>\r\n", uuid.New().String(), domain)
+	buffer.WriteString("MIME-Version: 1.0\r\n")
+	buffer.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buffer.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	body := strings.ReplaceAll(strings.ReplaceAll(m.Body, "\r\n", "\n"), "\n", "\r\n")
+	buffer.WriteString(body)
+	if !strings.HasSuffix(body, "\r\n") {
+		buffer.WriteString("\r\n")
+	}
+	return buffer.Bytes(), nil
+}
+
+// `SMTPMailer` sends the messages to a SMTP server (STARTTLS is used if the server supports it).
+// @property {string} Host - The host of the SMTP server.
+// @property {string} Port - The port of the SMTP server.
+// @property {string} Username - The username (no authentication if empty).
+// @property {string} Password - The password.
+// @property {string} From - The sender of the mails.
+type SMTPMailer struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// It sends the message to the SMTP server
+func (m *SMTPMailer) Send(message Message) error {
+	content, err := message.Bytes(m.From)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if m.Username != "" {
+		auth = smtp.PlainAuth("", m.Username, m.Password, m.Host)
+	}
+	return smtp.SendMail(net.JoinHostPort(m.Host, m.Port), auth, m.From, []string{message.To}, content)
+}
+
+// `FileMailer` writes the messages in a directory, for the development and the tests.
+// @property {string} Dir - The directory of the `.eml` files.
+// @property {string} From - The sender of the mails.
+type FileMailer struct {
+	Dir  string
+	From string
+}
+
+// It writes the message in the directory
+func (m *FileMailer) Send(message Message) error {
+	content, err := message.Bytes(m.From)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(m.Dir, 0o700); err != nil {
+		return err
+	}
+	path := filepath.Join(m.Dir, time.Now().Format("20060102-150405")+"-"+uuid.New().String()+".eml")
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		return err
+	}
+	fmt.Println("Mail: \"" + message.Subject + "\" to " + message.To + " written in " + path)
+	return nil
+}
+
+// `LogMailer` prints the messages, for the development. The queries of the links are redacted, so the
+// tokens they contain can't be used by the readers of the logs.
+// @property {string} From - The sender of the mails.
+type LogMailer struct {
+	From string
+}
+
+// It returns the body with the queries of the links redacted
+func Redact(body string) string {
+	return linkQuery.ReplaceAllString(body, "$1?<redacted>")
+}
+
+// It prints the message with its links redacted
+func (m *LogMailer) Send(message Message) error {
+	message.Body = Redact(message.Body)
+	content, err := message.Bytes(m.From)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Mail: to " + message.To + "\n" + string(content))
+	return nil
+}
@@ -0,0 +1,98 @@
+package mail
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMessageBytes(t *testing.T) {
+	content, err := Message{
+		To:      "user@example.com",
+		Subject: "Vérification",
+		Body:    "line 1\nline 2",
+	}.Bytes("no-reply@area.local")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encoded := string(content)
+	for _, expected := range []string{
+		"From: no-reply@area.local\r\n",
+		"To: user@example.com\r\n",
+		"Subject: =?utf-8?q?V=C3=A9rification?=\r\n",
+		"@area.local>\r\n",
+		"\r\n\r\nline 1\r\nline 2\r\n",
+	} {
+		if !strings.Contains(encoded, expected) {
+			t.Errorf("expected %q in:\n%s", expected, encoded)
+		}
+	}
+
+	if _, err := (Message{To: "a@b.c\r\nBcc: x@y.z", Subject: "s"}).Bytes("no-reply@area.local"); err != ErrInvalidHeader {
+		t.Errorf("expected the header injection to be refused, got %v", err)
+	}
+}
+
+func TestFileMailer(t *testing.T) {
+	dir := t.TempDir()
+	m := &FileMailer{Dir: dir, From: "no-reply@area.local"}
+	if err := m.Send(VerificationMessage("user@example.com", "a token")); err != nil {
+		t.Fatal(err)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.eml"))
+	if len(files) != 1 {
+		t.Fatalf("expected 1 mail, got %d", len(files))
+	}
+	content, _ := os.ReadFile(files[0])
+	if !strings.Contains(string(content), "http://localhost:8081/verify-email?token=a+token") {
+		t.Errorf("expected the link in the mail:\n%s", content)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	t.Setenv("MAIL_DRIVER", "smtp")
+	t.Setenv("SMTP_HOST", "")
+	if _, err := Load(); err == nil {
+		t.Error("expected an error without SMTP_HOST")
+	}
+
+	t.Setenv("SMTP_HOST", "smtp.example.com")
+	m, err := Load()
+	if smtpMailer, ok := m.(*SMTPMailer); err != nil || !ok || smtpMailer.Port != "587" {
+		t.Errorf("expected a SMTP mailer, got %#v %v", m, err)
+	}
+
+	t.Setenv("MAIL_DRIVER", "")
+	if _, err := Load(); err != ErrNoDriver {
+		t.Errorf("expected ErrNoDriver without a driver, got %v", err)
+	}
+
+	t.Setenv("MAIL_DRIVER", "log")
+	if m, err := Load(); err != nil {
+		t.Errorf("expected a log mailer, got %v", err)
+	} else if _, ok := m.(*LogMailer); !ok {
+		t.Errorf("expected a log mailer, got %#v", m)
+	}
+
+	t.Setenv("MAIL_DRIVER", "pigeon")
+	if _, err := Load(); err == nil {
+		t.Error("expected an error with an unknown driver")
+	}
+}
+
+func TestRedact(t *testing.T) {
+	body := PasswordResetMessage("user@example.com", "secret-token").Body
+	redacted := Redact(body)
+	if strings.Contains(redacted, "secret-token") {
+		t.Errorf("expected the token to be redacted:\n%s", redacted)
+	}
+	if !strings.Contains(redacted, "/reset-password?<redacted>") {
+		t.Errorf("expected the link without its query:\n%s", redacted)
+	}
+	if plain := "No link here.\n"; Redact(plain) != plain {
+		t.Errorf("expected the text to be unchanged, got %q", Redact(plain))
+	}
+}
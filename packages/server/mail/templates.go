@@ -0,0 +1,60 @@
+package mail
+
+import (
+	"net/url"
+	"os"
+	"strings"
+)
+
+// It returns the link to a page of the client with the token in the query
+func clientLink(path string, token string) string {
+	return clientURL(path) + "?token=" + url.QueryEscape(token)
+}
+
+// It returns the URL of a page of the client (AREA_CLIENT_URL, http://localhost:8081 by default)
+func clientURL(path string) string {
+	base := os.Getenv("AREA_CLIENT_URL")
+	if base == "" {
+		base = "http://localhost:8081"
+	}
+	return strings.TrimRight(base, "/") + path
+}
+
+// VerificationMessage returns the mail sent to verify the address of an account
+func VerificationMessage(to string, token string) Message {
+	return Message{
+		To:      to,
+		Subject: "Verify your email address",
+		Body: "Welcome to Area !\n\n" +
+			"Open the following link to verify your email address:\n" +
+			clientLink("/verify-email", token) + "\n\n" +
+			"If you didn't create an account, you can ignore this mail.\n",
+	}
+}
+
+// PasswordResetMessage returns the mail sent to reset the password of an account
+func PasswordResetMessage(to string, token string) Message {
+	return Message{
+		To:      to,
+		Subject: "Reset your password",
+		Body: "A password reset was requested for your Area account.\n\n" +
+			"Open the following link to choose a new password:\n" +
+			clientLink("/reset-password", token) + "\n\n" +
+			"If you didn't request it, you can ignore this mail, your password is unchanged.\n",
+	}
+}
+
+// ReconnectMessage returns the mail sent when an authorization was revoked by the provider, the applets
+// using it stop until the service is authorized again
+func ReconnectMessage(to string, service string, label string, applets []string) Message {
+	body := "Your " + service + " authorization (" + label + ") is no longer accepted by " + service + ".\n\n"
+	if len(applets) > 0 {
+		body += "The following applets will stop until you reconnect it:\n- " + strings.Join(applets, "\n- ") + "\n\n"
+	}
+	body += "Open the following page to reconnect " + service + ":\n" + clientURL("/authorizations") + "\n"
+	return Message{
+		To:      to,
+		Subject: "Reconnect " + service + " to Area",
+		Body:    body,
+	}
+}
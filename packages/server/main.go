@@ -1,9 +1,13 @@
 package main
 
 import (
+	"area-server/accounts"
 	routes "area-server/api"
+	"area-server/authenticators"
 	config "area-server/config"
 	"area-server/db/postgres"
+	"area-server/mail"
+	"context"
 	"os"
 
 	"github.com/gofiber/fiber/v2"
@@ -20,10 +24,13 @@ func main() {
 	if !pg.OK() {
 		// Auto migrate if not exist
 		pg.Migrate()
+	} else if err := pg.Upgrade(); err != nil {
+		panic(err)
 	}
 
-	if _, ok := os.LookupEnv("AREA_STATE"); !ok {
-		panic("AREA_STATE not set")
+	// The mails must be sent somewhere (MAIL_DRIVER)
+	if _, err := mail.Load(); err != nil {
+		panic(err)
 	}
 
 	// Load triggers - If exist
@@ -31,6 +38,13 @@ func main() {
 		panic(err)
 	}
 
+	// Refresh the OAuth2 tokens before they expire, and check they are still accepted by the providers
+	go authenticators.StartTokenRefresher(context.Background())
+	go authenticators.StartHealthChecker(context.Background())
+
+	// Finish the deletions of accounts interrupted by a failure or a restart
+	go accounts.StartTeardownWorker(context.Background())
+
 	// Create folder avatars if not exist
 	os.Mkdir("./avatars", 666)
 
@@ -43,7 +57,7 @@ func main() {
 		app.Use(cors.New(cors.Config{
 			AllowOrigins:     "http://localhost:8081",
 			AllowCredentials: true,
-			AllowHeaders:     "Origin, Content-Type, Accept, Authorization, X-Session",
+			AllowHeaders:     "Origin, Content-Type, Accept, Authorization, X-Session, X-CSRF-Token",
 		}))
 	} else {
 		app.Use(cors.New())
@@ -1,17 +1,183 @@
 package main
 
 import (
+	"area-server/db/encryption"
 	"area-server/db/postgres"
+	"area-server/db/postgres/models"
+	"area-server/utils"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
 )
 
+// `storedTokens` are the columns of an authorization as stored in the database (not decrypted).
+type storedTokens struct {
+	UUID            string
+	AccessToken     string
+	RefreshToken    string
+	Other           string
+	AccessTokenHash string
+}
+
+// It encrypts the tokens of the authorizations with the active key: the tokens stored in plain text
+// (before the encryption) or with a previous key are encrypted again. The previous keys can be removed
+// from the keyring once it is done.
+func rotateTokenKeys() error {
+	keys := encryption.Keys()
+	if !keys.Enabled() {
+		return fmt.Errorf("No encryption key set (AREA_ENCRYPTION_KEYS or AREA_ENCRYPTION_KEY_FILE)")
+	}
+
+	// Adds the fingerprint column and stores the other fields as text
+	if err := postgres.DB.AutoMigrate(&models.Authorization{}); err != nil {
+		return err
+	}
+
+	var rows []storedTokens
+	if result := postgres.DB.Table("authorizations").
+		Select("uuid, access_token, coalesce(refresh_token, '') AS refresh_token, coalesce(other::text, '') AS other, coalesce(access_token_hash, '') AS access_token_hash").
+		Scan(&rows); result.Error != nil {
+		return result.Error
+	}
+
+	rotated := 0
+	for _, row := range rows {
+		if !keys.NeedsRotation(row.AccessToken) && !keys.NeedsRotation(row.RefreshToken) &&
+			!keys.NeedsRotation(row.Other) && row.AccessTokenHash != "" {
+			continue
+		}
+
+		// Loaded with the serializer (decrypted) and saved with the active key
+		var authorization models.Authorization
+		if result := postgres.DB.Where("uuid = ?", row.UUID).First(&authorization); result.Error != nil {
+			return result.Error
+		}
+		authorization.AccessTokenHash = encryption.Fingerprint(authorization.AccessToken)
+		if result := postgres.DB.Model(&authorization).
+			Select("access_token", "refresh_token", "other", "access_token_hash").
+			Updates(&authorization); result.Error != nil {
+			return result.Error
+		}
+		rotated++
+	}
+	fmt.Printf("Authorizations encrypted with the key %q: %d\n", keys.Active, rotated)
+	return nil
+}
+
+// It hashes the passwords of the local accounts that are still stored as sent by the client
+func hashLegacyPasswords() error {
+	var accounts []models.Account
+	if result := postgres.DB.Where(&models.Account{Authenticator: "@local"}).Find(&accounts); result.Error != nil {
+		return result.Error
+	}
+
+	migrated := 0
+	for _, account := range accounts {
+		if account.Password == "" || utils.IsPasswordHash(account.Password) {
+			continue
+		}
+		hash, err := utils.HashPassword(account.Password)
+		if err != nil {
+			return err
+		}
+		if result := postgres.DB.Model(&account).Update("password", hash); result.Error != nil {
+			return result.Error
+		}
+		migrated++
+	}
+	fmt.Printf("Passwords hashed: %d\n", migrated)
+	return nil
+}
+
+// It merges the source account into the target account (`-merge-accounts <source>,<target>`), the
+// source account is deleted
+func mergeAccounts(accounts string) error {
+	ids := strings.Split(accounts, ",")
+	if len(ids) != 2 {
+		return fmt.Errorf("Expected <source>,<target>, got %q", accounts)
+	}
+	source, err := uuid.Parse(strings.TrimSpace(ids[0]))
+	if err != nil {
+		return fmt.Errorf("Invalid source account: %w", err)
+	}
+	target, err := uuid.Parse(strings.TrimSpace(ids[1]))
+	if err != nil {
+		return fmt.Errorf("Invalid target account: %w", err)
+	}
+	if err := utils.MergeAccounts(source, target); err != nil {
+		return err
+	}
+	fmt.Printf("Account %s merged into %s\n", source, target)
+	return nil
+}
+
+// It gives the admin role to the account of the email (`-set-admin <email>`), the first administrator
+// can't be promoted from the API
+func setAdmin(email string) error {
+	result := postgres.DB.Model(&models.Account{}).Where(&models.Account{Email: email}).Update("role", models.RoleAdmin)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("No account with the email %q", email)
+	}
+	fmt.Printf("Account %s is now an administrator\n", email)
+	return nil
+}
+
 func main() {
 	/* err := godotenv.Load("../../.env")
 	if err != nil {
 		panic(err)
 	} */
+	rotate := flag.Bool("rotate-keys", false, "Encrypt the tokens with the active key (the tables are not dropped)")
+	merge := flag.String("merge-accounts", "", "Merge the first account into the second one: <source_uuid>,<target_uuid> (the tables are not dropped)")
+	hashPasswords := flag.Bool("hash-passwords", false, "Hash the passwords of the local accounts still stored in plain text (the tables are not dropped)")
+	admin := flag.String("set-admin", "", "Give the admin role to the account of the email (the tables are not dropped)")
+	flag.Parse()
+
 	// Do nothing
 	pg := postgres.Init()
 	pg.Connect()
 
+	if *rotate {
+		if err := rotateTokenKeys(); err != nil {
+			panic(err)
+		}
+		return
+	}
+
+	if *hashPasswords {
+		if err := pg.Upgrade(); err != nil {
+			panic(err)
+		}
+		if err := hashLegacyPasswords(); err != nil {
+			panic(err)
+		}
+		return
+	}
+
+	if *admin != "" {
+		if err := pg.Upgrade(); err != nil {
+			panic(err)
+		}
+		if err := setAdmin(*admin); err != nil {
+			panic(err)
+		}
+		return
+	}
+
+	if *merge != "" {
+		if err := pg.Upgrade(); err != nil {
+			panic(err)
+		}
+		if err := mergeAccounts(*merge); err != nil {
+			panic(err)
+		}
+		return
+	}
+
 	pg.Migrate() // Migrate database - Change to false in config if you don't want to migrate
 }
@@ -30,6 +30,13 @@ var List []static.Service = []static.Service{
 	youtube.Descriptor(),     // YouTube
 }
 
+// The requests of the services are limited with their own rate
+func init() {
+	for i := range List {
+		List[i].LimitEndpoints()
+	}
+}
+
 // GetServiceByName returns a pointer to a static.Service struct if the name of the service matches the
 // name passed in as an argument.
 func GetServiceByName(name string) *static.Service {
@@ -0,0 +1,216 @@
+package common
+
+import (
+	"area-server/classes/static"
+	"area-server/config"
+	"area-server/utils"
+	"context"
+	"net"
+	"net/mail"
+	"strings"
+	"sync"
+	"time"
+)
+
+// `EmailResolver` is the DNS resolver used to check the domain of an email address (`net.Resolver`
+// implements it, tests can use a fake one).
+type EmailResolver interface {
+	LookupMX(ctx context.Context, name string) ([]*net.MX, error)
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// `EmailRemoteCheck` verifies an email address with a remote service, it returns true if the address
+// exists.
+type EmailRemoteCheck func(address string) (bool, error)
+
+// `emailCacheEntry` is a validation result kept in the cache of `EmailValidation`.
+type emailCacheEntry struct {
+	err     *static.ValidationError
+	expires time.Time
+}
+
+// `EmailValidation` validates email addresses in three steps, the syntax (RFC 5322), the domain (MX
+// lookup, optional) and the remote verification (optional). Results are cached by address.
+// @property {bool} CheckMX - If true, the domain must have a MX (or A) record.
+// @property {EmailResolver} Resolver - The resolver used for the MX lookup.
+// @property {EmailRemoteCheck} Remote - The remote verification, nil to disable it.
+// @property {time.Duration} CacheDuration - How long a result is kept in cache (0 = no cache).
+// @property {time.Duration} Timeout - The timeout of the DNS lookups.
+type EmailValidation struct {
+	CheckMX       bool
+	Resolver      EmailResolver
+	Remote        EmailRemoteCheck
+	CacheDuration time.Duration
+	Timeout       time.Duration
+
+	mutex sync.Mutex
+	cache map[string]emailCacheEntry
+}
+
+var (
+	defaultEmailValidation     *EmailValidation
+	defaultEmailValidationOnce sync.Once
+)
+
+// It returns the email validation built from `config.CFG.Email`
+func DefaultEmailValidation() *EmailValidation {
+	defaultEmailValidationOnce.Do(func() {
+		defaultEmailValidation = &EmailValidation{
+			CheckMX:       config.CFG.Email.CheckMX,
+			Resolver:      net.DefaultResolver,
+			CacheDuration: time.Duration(config.CFG.Email.CacheDuration) * time.Second,
+			Timeout:       5 * time.Second,
+		}
+		if config.CFG.Email.RemoteCheck {
+			defaultEmailValidation.Remote = IsItARealEmailCheck
+		}
+	})
+	return defaultEmailValidation
+}
+
+// It validates the address, nil is returned if the address is valid
+func (v *EmailValidation) Validate(address string) *static.ValidationError {
+	key := strings.ToLower(address)
+
+	if err, ok := v.cached(key); ok {
+		return err
+	}
+
+	err, temporary := v.validate(address)
+
+	// The failures of the remote service are not cached, they may be temporary
+	if !temporary {
+		v.store(key, err)
+	}
+	return err
+}
+
+// It runs the validation steps without the cache, the bool is true if the result may be temporary
+func (v *EmailValidation) validate(address string) (*static.ValidationError, bool) {
+	domain, err := CheckEmailSyntax(address)
+	if err != nil {
+		return err, false
+	}
+
+	if v.CheckMX && v.Resolver != nil {
+		if err := v.checkDomain(domain); err != nil {
+			return err, false
+		}
+	}
+
+	if v.Remote != nil {
+		exists, rerr := v.Remote(address)
+		if rerr != nil {
+			return static.NewValidationError(static.ValidationNotFound, "The email address can't be verified", ""), true
+		}
+		if !exists {
+			return static.NotFoundError("email address"), false
+		}
+	}
+
+	return nil, false
+}
+
+// It checks that the domain can receive emails, a MX record is expected, otherwise the domain must
+// resolve to an address (RFC 5321 implicit MX)
+func (v *EmailValidation) checkDomain(domain string) *static.ValidationError {
+	timeout := v.Timeout
+	if timeout == 0 {
+		timeout = 5 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if records, err := v.Resolver.LookupMX(ctx, domain); err == nil && len(records) > 0 {
+		return nil
+	}
+	if hosts, err := v.Resolver.LookupHost(ctx, domain); err == nil && len(hosts) > 0 {
+		return nil
+	}
+	return static.NewValidationError(static.ValidationNotFound, "The domain of the email address can't receive emails", "")
+}
+
+// It returns the cached result of the address, if it exists and is not expired
+func (v *EmailValidation) cached(key string) (*static.ValidationError, bool) {
+	if v.CacheDuration <= 0 {
+		return nil, false
+	}
+
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	entry, ok := v.cache[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expires) {
+		delete(v.cache, key)
+		return nil, false
+	}
+	return entry.err, true
+}
+
+// It stores the result of the address in the cache
+func (v *EmailValidation) store(key string, err *static.ValidationError) {
+	if v.CacheDuration <= 0 {
+		return
+	}
+
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	if v.cache == nil {
+		v.cache = make(map[string]emailCacheEntry)
+	}
+	v.cache[key] = emailCacheEntry{err: err, expires: time.Now().Add(v.CacheDuration)}
+}
+
+// It checks the syntax of a bare email address (RFC 5322 addr-spec, without display name) and
+// returns its domain
+func CheckEmailSyntax(address string) (string, *static.ValidationError) {
+	invalid := static.NewValidationError(static.ValidationInvalidFormat, "The value is not a valid email address", "user@example.com")
+
+	if len(address) > 254 {
+		return "", invalid
+	}
+
+	parsed, err := mail.ParseAddress(address)
+	if err != nil || parsed.Name != "" || parsed.Address != address {
+		return "", invalid
+	}
+
+	at := strings.LastIndex(address, "@")
+	local, domain := address[:at], address[at+1:]
+	if len(local) > 64 || len(domain) > 253 {
+		return "", invalid
+	}
+
+	// The domain must be a fully qualified domain name
+	labels := strings.Split(domain, ".")
+	if len(labels) < 2 {
+		return "", invalid
+	}
+	for _, label := range labels {
+		if len(label) == 0 || len(label) > 63 || strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+			return "", invalid
+		}
+	}
+
+	return domain, nil
+}
+
+// It verifies the address with isitarealemail.com
+func IsItARealEmailCheck(address string) (bool, error) {
+	body, _, _, err := utils.DoRequest("https://isitarealemail.com/api/email/validate", &utils.RequestParams{
+		Method: "GET",
+		QueryParams: map[string]string{
+			"email": address,
+		},
+	}, []int{200}, true)
+	if err != nil {
+		return false, err
+	}
+
+	status, _ := body.(map[string]interface{})["status"].(string)
+	return status == "valid", nil
+}
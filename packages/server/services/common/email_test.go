@@ -0,0 +1,134 @@
+package common
+
+import (
+	"area-server/classes/static"
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+// fakeResolver is an offline `EmailResolver`, domains are resolved from the maps
+type fakeResolver struct {
+	mx      map[string][]*net.MX
+	hosts   map[string][]string
+	lookups int
+}
+
+func (r *fakeResolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	r.lookups++
+	if records, ok := r.mx[name]; ok {
+		return records, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
+
+func (r *fakeResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	if hosts, ok := r.hosts[host]; ok {
+		return hosts, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+func TestCheckEmailSyntax(t *testing.T) {
+	valid := []string{
+		"user@example.com",
+		"first.last+tag@sub.example.co.uk",
+		"o'brien@example.org",
+	}
+	invalid := []string{
+		"",
+		"user",
+		"user@",
+		"@example.com",
+		"user@localhost",
+		"John <user@example.com>",
+		"user@-example.com",
+		"user@example..com",
+		"user name@example.com",
+	}
+
+	for _, address := range valid {
+		if _, err := CheckEmailSyntax(address); err != nil {
+			t.Errorf("%q should be valid, got %s", address, err.Code)
+		}
+	}
+	for _, address := range invalid {
+		if _, err := CheckEmailSyntax(address); err == nil || err.Code != static.ValidationInvalidFormat {
+			t.Errorf("%q should be invalid", address)
+		}
+	}
+}
+
+func TestEmailValidationMX(t *testing.T) {
+	resolver := &fakeResolver{
+		mx:    map[string][]*net.MX{"example.com": {{Host: "mx.example.com.", Pref: 10}}},
+		hosts: map[string][]string{"implicit.org": {"192.0.2.1"}},
+	}
+	validation := &EmailValidation{CheckMX: true, Resolver: resolver}
+
+	if err := validation.Validate("user@example.com"); err != nil {
+		t.Errorf("domain with MX should be valid, got %s", err.Code)
+	}
+	if err := validation.Validate("user@implicit.org"); err != nil {
+		t.Errorf("domain with A record should be valid, got %s", err.Code)
+	}
+	if err := validation.Validate("user@nowhere.invalid"); err == nil || err.Code != static.ValidationNotFound {
+		t.Errorf("unknown domain should be not_found")
+	}
+}
+
+func TestEmailValidationRemote(t *testing.T) {
+	calls := 0
+	validation := &EmailValidation{
+		Remote: func(address string) (bool, error) {
+			calls++
+			switch address {
+			case "down@example.com":
+				return false, errors.New("timeout")
+			case "ghost@example.com":
+				return false, nil
+			}
+			return true, nil
+		},
+		CacheDuration: time.Minute,
+	}
+
+	if err := validation.Validate("user@example.com"); err != nil {
+		t.Errorf("existing address should be valid, got %s", err.Code)
+	}
+	if err := validation.Validate("ghost@example.com"); err == nil || err.Code != static.ValidationNotFound {
+		t.Errorf("unknown address should be not_found")
+	}
+
+	// Temporary failures are not cached
+	validation.Validate("down@example.com")
+	validation.Validate("down@example.com")
+	if calls != 4 {
+		t.Errorf("expected 4 remote calls, got %d", calls)
+	}
+
+	// Syntax errors never reach the remote service
+	validation.Validate("not an email")
+	if calls != 4 {
+		t.Errorf("invalid syntax should not call the remote service")
+	}
+}
+
+func TestEmailValidationCache(t *testing.T) {
+	resolver := &fakeResolver{mx: map[string][]*net.MX{"example.com": {{Host: "mx.example.com.", Pref: 10}}}}
+	validation := &EmailValidation{CheckMX: true, Resolver: resolver, CacheDuration: time.Minute}
+
+	validation.Validate("user@example.com")
+	validation.Validate("USER@example.com")
+	if resolver.lookups != 1 {
+		t.Errorf("expected the second validation to be cached, got %d lookups", resolver.lookups)
+	}
+
+	validation.cache["user@example.com"] = emailCacheEntry{expires: time.Now().Add(-time.Second)}
+	validation.Validate("user@example.com")
+	if resolver.lookups != 2 {
+		t.Errorf("expected the expired entry to be refreshed, got %d lookups", resolver.lookups)
+	}
+}
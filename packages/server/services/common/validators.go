@@ -8,89 +8,86 @@ import (
 	"strconv"
 )
 
-// If the value is nil, return false. If the value is "true" or "false", return true. Otherwise, return
-// false
-func BoolValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) bool {
+// If the value is "true" or "false", it's valid
+func BoolValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) *static.ValidationError {
 	if value == nil {
-		return false
+		return static.MissingError()
 	}
 
 	if value == "true" || value == "false" {
-		return true
+		return nil
 	}
 
-	return false
+	return static.NotAllowedError("true", "false")
 }
 
 // If the value is not nil, not an empty string, and can be converted to an integer, then it's valid
-func IntValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) bool {
+func IntValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) *static.ValidationError {
 	if value == nil {
-		return false
+		return static.MissingError()
 	}
 
-	if value.(string) == "" {
-		return false
+	if _, ok := value.(string); !ok {
+		return static.InvalidTypeError("string")
 	}
 
 	if v, err := strconv.Atoi(value.(string)); err != nil || v < 0 {
-		return false
+		return static.InvalidFormatError("positive integer")
 	}
 
-	return true
+	return nil
 }
 
 // If the value is not nil, not an empty string, and can be parsed as a float, then return true
-func FloatValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) bool {
+func FloatValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) *static.ValidationError {
 	if value == nil {
-		return false
+		return static.MissingError()
 	}
 
-	if value.(string) == "" {
-		return false
+	if _, ok := value.(string); !ok {
+		return static.InvalidTypeError("string")
 	}
 
 	if v, err := strconv.ParseFloat(value.(string), 64); err != nil || v < 0 {
-		return false
+		return static.InvalidFormatError("positive number")
 	}
 
-	return true
+	return nil
 }
 
-// It takes the value of the field, and checks if it's a valid email address
-func EmailValidator(authorization *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) bool {
+// It takes the value of the field, and checks if it's a valid email address (see `EmailValidation`)
+func EmailValidator(authorization *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) *static.ValidationError {
 	if value == nil {
-		return false
+		return static.MissingError()
 	}
 
-	body, _, _, err := utils.DoRequest("https://isitarealemail.com/api/email/validate", &utils.RequestParams{
-		Method: "GET",
-		QueryParams: map[string]string{
-			"email": value.(string),
-		},
-	}, []int{200}, true)
-
-	if err != nil || body.(map[string]interface{})["status"] == nil || body.(map[string]interface{})["status"].(string) != "valid" {
-		return false
+	if _, ok := value.(string); !ok {
+		return static.InvalidTypeError("string")
 	}
 
-	return true
+	return DefaultEmailValidation().Validate(value.(string))
 }
 
 // It checks if the value is a string and if it's a valid URL
-func URLValidator(authorization *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) bool {
+func URLValidator(authorization *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) *static.ValidationError {
 	if value == nil {
-		return false
+		return static.MissingError()
 	}
 
 	if _, ok := value.(string); !ok {
-		return false
+		return static.InvalidTypeError("string")
 	}
 
 	// Regex to check if the url is valid (http or https allowed)
 	regex := regexp.MustCompile(`^(http|https):\/\/[a-z0-9]+([\-\.]{1}[a-z0-9]+)*\.[a-z]{2,5}(:[0-9]{1,5})?(\/.*)?$`)
 	if !regex.MatchString(value.(string)) {
-		return false
+		return static.InvalidFormatError("http(s)://domain.tld/path")
+	}
+
+	// The addresses are checked again when the request is sent (the host can resolve elsewhere later)
+	if err := utils.Egress().CheckURL(value.(string)); err != nil {
+		return static.NewValidationError(static.ValidationNotAllowed, err.Error(), "a public http(s) URL")
 	}
 
-	return true
+	return nil
 }
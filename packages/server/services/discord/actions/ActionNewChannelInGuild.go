@@ -30,7 +30,7 @@ func onNewChannelInGuild(req static.AreaRequest) shared.AreaResponse {
 		guildID = (*req.Store)["req:guild:id"]
 	}
 
-	encode, _, err := req.Service.Endpoints["ListGuildChannelsEndpoint"].CallEncode([]interface{}{
+	encode, _, err := req.Service.Endpoints["ListGuildChannelsEndpoint"].CallEncodeContext(req.Context, []interface{}{
 		guildID,
 	})
 	if err != nil {
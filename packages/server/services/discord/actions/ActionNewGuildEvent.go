@@ -57,7 +57,7 @@ func onNewGuildEvent(req static.AreaRequest) shared.AreaResponse {
 		guildID = (*req.Store)["req:guild:id"]
 	}
 
-	encode, _, err := req.Service.Endpoints["ListGuildEventsEndpoint"].CallEncode([]interface{}{
+	encode, _, err := req.Service.Endpoints["ListGuildEventsEndpoint"].CallEncodeContext(req.Context, []interface{}{
 		guildID,
 	})
 	if err != nil {
@@ -43,7 +43,7 @@ type UserGuildsResponse struct {
 // It checks if the user has joined a new guild, and if so, it returns the guild's information
 func onNewGuildJoined(req static.AreaRequest) shared.AreaResponse {
 
-	encode, _, err := req.Service.Endpoints["GetUserGuildsEndpoint"].CallEncode([]interface{}{req.Authorization})
+	encode, _, err := req.Service.Endpoints["GetUserGuildsEndpoint"].CallEncodeContext(req.Context, []interface{}{req.Authorization})
 	if err != nil {
 		return shared.AreaResponse{Error: err}
 	}
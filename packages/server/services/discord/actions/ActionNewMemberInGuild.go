@@ -37,7 +37,7 @@ func onNewMemberInGuild(req static.AreaRequest) shared.AreaResponse {
 		guildID = (*req.Store)["req:guild:id"]
 	}
 
-	encode, _, err := req.Service.Endpoints["ListGuildMembersEndpoint"].CallEncode([]interface{}{
+	encode, _, err := req.Service.Endpoints["ListGuildMembersEndpoint"].CallEncodeContext(req.Context, []interface{}{
 		guildID,
 	})
 
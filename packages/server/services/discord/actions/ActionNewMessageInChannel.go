@@ -56,7 +56,7 @@ func onNewMessageInChannel(req static.AreaRequest) shared.AreaResponse {
 		return shared.AreaResponse{Success: false}
 	}
 
-	encode, httpResp, err := req.Service.Endpoints["ListChannelMessagesEndpoint"].CallEncode([]interface{}{
+	encode, httpResp, err := req.Service.Endpoints["ListChannelMessagesEndpoint"].CallEncodeContext(req.Context, []interface{}{
 		(*req.Store)["req:channel:id"],
 		query,
 	})
@@ -15,7 +15,7 @@ func onNewPinnedMessageInChannel(req static.AreaRequest) shared.AreaResponse {
 		return shared.AreaResponse{Success: false}
 	}
 
-	encode, httpResp, err := req.Service.Endpoints["ListPinnedMessagesEndpoint"].CallEncode([]interface{}{
+	encode, httpResp, err := req.Service.Endpoints["ListPinnedMessagesEndpoint"].CallEncodeContext(req.Context, []interface{}{
 		(*req.Store)["req:channel:id"],
 	})
 	if err != nil {
@@ -38,7 +38,7 @@ func onNewReactionAddedToMessage(req static.AreaRequest) shared.AreaResponse {
 		query["after"] = (*req.Store)["ctx:last:react"].(string)
 	}
 
-	encode, _, err := req.Service.Endpoints["ListUserThatReactedEndpoint"].CallEncode([]interface{}{
+	encode, _, err := req.Service.Endpoints["ListUserThatReactedEndpoint"].CallEncodeContext(req.Context, []interface{}{
 		(*req.Store)["req:channel:id"],
 		(*req.Store)["req:message:id"],
 		emoji[0].Character,
@@ -21,7 +21,7 @@ func addReactionToMessage(req static.AreaRequest) shared.AreaResponse {
 		return shared.AreaResponse{Error: errors.New("Invalid emoji")}
 	}
 
-	_, _, err := req.Service.Endpoints["CreateReactionEndpoint"].CallEncode([]interface{}{
+	_, _, err := req.Service.Endpoints["CreateReactionEndpoint"].CallEncodeContext(req.Context, []interface{}{
 		channelID,
 		messageID,
 		emojis[0].Character,
@@ -17,7 +17,7 @@ func banUserFromGuild(req static.AreaRequest) shared.AreaResponse {
 
 	userID := utils.GenerateFinalComponent((*req.Store)["req:user:id"].(string), req.ExternalData, []string{"discord:user:id"})
 
-	_, _, err := req.Service.Endpoints["BanUserEndpoint"].CallEncode([]interface{}{guildID, userID})
+	_, _, err := req.Service.Endpoints["BanUserEndpoint"].CallEncodeContext(req.Context, []interface{}{guildID, userID})
 
 	if err != nil {
 		return shared.AreaResponse{Error: err}
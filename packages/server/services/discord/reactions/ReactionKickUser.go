@@ -17,7 +17,7 @@ func kickUserFromGuild(req static.AreaRequest) shared.AreaResponse {
 
 	userID := utils.GenerateFinalComponent((*req.Store)["req:user:id"].(string), req.ExternalData, []string{"discord:user:id"})
 
-	_, _, err := req.Service.Endpoints["KickUserEndpoint"].CallEncode([]interface{}{
+	_, _, err := req.Service.Endpoints["KickUserEndpoint"].CallEncodeContext(req.Context, []interface{}{
 		guildID,
 		userID,
 	})
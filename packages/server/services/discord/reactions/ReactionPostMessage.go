@@ -21,7 +21,7 @@ func postMessage(req static.AreaRequest) shared.AreaResponse {
 
 	if req.ExternalData["discord:message:id"] != nil && (*req.Store)["req:not:reply"] != "true" {
 
-		_, _, err0 := req.Service.Endpoints["GetChannelMessageEndpoint"].CallEncode([]interface{}{
+		_, _, err0 := req.Service.Endpoints["GetChannelMessageEndpoint"].CallEncodeContext(req.Context, []interface{}{
 			channelID,
 			req.ExternalData["discord:message:id"],
 		})
@@ -38,7 +38,7 @@ func postMessage(req static.AreaRequest) shared.AreaResponse {
 		return shared.AreaResponse{Error: err}
 	}
 
-	_, _, errr := req.Service.Endpoints["CreateMessageEndpoint"].CallEncode([]interface{}{
+	_, _, errr := req.Service.Endpoints["CreateMessageEndpoint"].CallEncodeContext(req.Context, []interface{}{
 		channelID,
 		string(fbody),
 	})
@@ -24,7 +24,7 @@ func sendPrivateMessage(req static.AreaRequest) shared.AreaResponse {
 		"discord:user:id",
 	})
 
-	body, _, err0 := req.Service.Endpoints["CreateDMEndpoint"].Call([]interface{}{
+	body, _, err0 := req.Service.Endpoints["CreateDMEndpoint"].CallContext(req.Context, []interface{}{
 		userID,
 	})
 	if err0 != nil {
@@ -38,7 +38,7 @@ func sendPrivateMessage(req static.AreaRequest) shared.AreaResponse {
 		return shared.AreaResponse{Error: err1}
 	}
 
-	emessage, _, err2 := req.Service.Endpoints["CreateMessageEndpoint"].CallEncode([]interface{}{
+	emessage, _, err2 := req.Service.Endpoints["CreateMessageEndpoint"].CallEncodeContext(req.Context, []interface{}{
 		body["id"].(string),
 		string(fbody),
 	})
@@ -4,9 +4,7 @@ import (
 	"area-server/classes/shared"
 	"area-server/classes/static"
 	"area-server/services/dropbox/common"
-	"area-server/utils"
 	"encoding/json"
-	"fmt"
 	"strconv"
 )
 
@@ -71,7 +69,7 @@ func onNewFileInDirectory(req static.AreaRequest) shared.AreaResponse {
 			return "", err
 		}
 
-		lcursor, _, err0 := req.Service.Endpoints["ListFoldersGetLatestCursorEndpoint"].Call([]interface{}{
+		lcursor, _, err0 := req.Service.Endpoints["ListFoldersGetLatestCursorEndpoint"].CallContext(req.Context, []interface{}{
 			req.Authorization,
 			string(body),
 		})
@@ -90,41 +88,30 @@ func onNewFileInDirectory(req static.AreaRequest) shared.AreaResponse {
 		}
 	}
 
-	elements, _, errr := req.Service.Endpoints["ListFoldersContinueEndpoint"].CallEncode([]interface{}{
+	// Every page since the cursor is walked, the last cursor is saved for the next check
+	it := req.Service.Endpoints["ListFoldersContinueEndpoint"].Paginate(req.Context, []interface{}{
 		req.Authorization,
 		(*req.Store)["ctx:cursor"],
 	})
-	if errr != nil {
-		return shared.AreaResponse{Error: errr}
-	}
-
-	var response ListFoldersContinueResponse
-	err := json.Unmarshal(elements, &response)
-	if err != nil {
-		return shared.AreaResponse{Error: err}
-	}
-
-	fmt.Println("Elements: ", response.Entries)
-	nbElements := len(response.Entries)
-	ok, errL := utils.IsLatestBasic(req.Store, nbElements)
-	if errL != nil {
-		return shared.AreaResponse{Error: errL}
-	}
-	if !ok {
-		return shared.AreaResponse{Success: false}
-	}
 
 	var latestElement *common.Entry
-	// Latest element correspond to the first file in the list at the end
-	for i := nbElements - 1; i >= 0; i-- {
-		if response.Entries[i].Tag == "file" {
-			latestElement = &response.Entries[i]
+	for it.Next() {
+		for _, item := range it.Items() {
+			var entry common.Entry
+			if err := json.Unmarshal(item, &entry); err != nil {
+				return shared.AreaResponse{Error: err}
+			}
+			// Latest element correspond to the last file in the list
+			if entry.Tag == "file" {
+				latestElement = &entry
+			}
 		}
 	}
-
-	(*req.Store)["ctx:cursor"], err = getLastCursor()
-	if err != nil {
-		return shared.AreaResponse{Error: err}
+	if it.Err() != nil {
+		return shared.AreaResponse{Error: it.Err()}
+	}
+	if it.Cursor() != "" {
+		(*req.Store)["ctx:cursor"] = it.Cursor()
 	}
 
 	if latestElement == nil {
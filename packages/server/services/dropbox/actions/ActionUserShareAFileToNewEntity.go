@@ -141,7 +141,7 @@ func onUserShareAFileToNewPerson(req static.AreaRequest) shared.AreaResponse {
 		}
 	}
 
-	entities, _, errr := req.Service.Endpoints["ListFileMembersEndpoint"].CallEncode([]interface{}{
+	entities, _, errr := req.Service.Endpoints["ListFileMembersEndpoint"].CallEncodeContext(req.Context, []interface{}{
 		req.Authorization,
 		string(body),
 	})
@@ -26,6 +26,13 @@ func DropboxEndpoints() static.ServiceEndpoint {
 			BaseURL:        "https://api.dropboxapi.com/2/files/list_folder/continue",
 			Params:         ListFoldersContinueEndpointParams,
 			ExpectedStatus: []int{200},
+			Pagination: &utils.Pagination{
+				Type:        utils.PaginationHasMore,
+				Items:       "entries",
+				Cursor:      "cursor",
+				HasMore:     "has_more",
+				ContinueURL: "https://api.dropboxapi.com/2/files/list_folder/continue",
+			},
 		},
 		"ListFoldersGetLatestCursorEndpoint": {
 			BaseURL:        "https://api.dropboxapi.com/2/files/list_folder/get_latest_cursor",
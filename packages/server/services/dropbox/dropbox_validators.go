@@ -31,40 +31,40 @@ func DirectoryPathValidator(
 	service *static.Service,
 	value interface{},
 	store map[string]interface{},
-) bool {
+) *static.ValidationError {
 	if value == nil {
-		return false
+		return static.MissingError()
 	}
 
 	if _, ok := value.(string); !ok {
-		return false
+		return static.InvalidTypeError("string")
 	}
 
 	if value.(string) == "/" {
-		return true
+		return nil
 	}
 
 	_, _, err := service.Endpoints["ListFoldersEndpoint"].CallEncode([]interface{}{authorization, "{\"path\": \"" + value.(string) + "\"}"})
 	if err != nil {
-		return false
+		return static.NotFoundError("directory")
 	}
 
-	return true
+	return nil
 }
 
-// It takes a file path, and returns true if the file exists
+// It takes a file path, and returns an error if the file does not exist
 func FileIdValidator(
 	authorization *models.Authorization,
 	service *static.Service,
 	value interface{},
 	store map[string]interface{},
-) bool {
+) *static.ValidationError {
 	if value == nil {
-		return false
+		return static.MissingError()
 	}
 
 	if _, ok := value.(string); !ok {
-		return false
+		return static.InvalidTypeError("string")
 	}
 
 	body, errJ := json.Marshal(map[string]interface{}{
@@ -74,15 +74,15 @@ func FileIdValidator(
 		"include_has_explicit_shared_members": false,
 	})
 	if errJ != nil {
-		return false
+		return static.InvalidFormatError("file path")
 	}
 
 	_, _, err := service.Endpoints["GetFileMetadataEndpoint"].CallEncode([]interface{}{authorization, string(body)})
 	if err != nil {
-		return false
+		return static.NotFoundError("file")
 	}
 
-	return true
+	return nil
 }
 
 // "If the value is a string, and it's either `group`, `user`, or `invitee`, then return true."
@@ -96,20 +96,20 @@ func EntityTypeValidator(
 	service *static.Service,
 	value interface{},
 	store map[string]interface{},
-) bool {
+) *static.ValidationError {
 	if value == nil {
-		return false
+		return static.MissingError()
 	}
 
 	if _, ok := value.(string); !ok {
-		return false
+		return static.InvalidTypeError("string")
 	}
 
 	switch value.(string) {
 	case "group", "user", "invitee":
-		return true
+		return nil
 	default:
-		return false
+		return static.NotAllowedError("group", "user", "invitee")
 	}
 }
 
@@ -122,19 +122,19 @@ func AccessLevelValidator(
 	service *static.Service,
 	value interface{},
 	store map[string]interface{},
-) bool {
+) *static.ValidationError {
 	if value == nil {
-		return false
+		return static.MissingError()
 	}
 
 	if _, ok := value.(string); !ok {
-		return false
+		return static.InvalidTypeError("string")
 	}
 
 	switch value.(string) {
 	case "viewer", "editor", "owner":
-		return true
+		return nil
 	default:
-		return false
+		return static.NotAllowedError("viewer", "editor", "owner")
 	}
 }
@@ -53,7 +53,7 @@ func addFileToDirectory(req static.AreaRequest) shared.AreaResponse {
 		return shared.AreaResponse{Error: err}
 	}
 
-	_, _, err = req.Service.Endpoints["UploadFileEndpoint"].CallEncode([]interface{}{
+	_, _, err = req.Service.Endpoints["UploadFileEndpoint"].CallEncodeContext(req.Context, []interface{}{
 		req.Authorization,
 		string(dropboxArg),
 		fileContent,
@@ -47,7 +47,7 @@ func createOrUpdatePaper(req static.AreaRequest) shared.AreaResponse {
 			return shared.AreaResponse{Error: err}
 		}
 
-		rbody, _, rerr = req.Service.Endpoints["CreatePaperEndpoint"].Call([]interface{}{
+		rbody, _, rerr = req.Service.Endpoints["CreatePaperEndpoint"].CallContext(req.Context, []interface{}{
 			req.Authorization,
 			string(body),
 			paperContent,
@@ -64,7 +64,7 @@ func createOrUpdatePaper(req static.AreaRequest) shared.AreaResponse {
 			return shared.AreaResponse{Error: err}
 		}
 
-		rbody, _, rerr = req.Service.Endpoints["UpdatePaperEndpoint"].Call([]interface{}{
+		rbody, _, rerr = req.Service.Endpoints["UpdatePaperEndpoint"].CallContext(req.Context, []interface{}{
 			req.Authorization,
 			string(body),
 			paperContent,
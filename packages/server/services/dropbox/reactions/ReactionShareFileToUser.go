@@ -40,7 +40,7 @@ func shareFileToUser(req static.AreaRequest) shared.AreaResponse {
 		return shared.AreaResponse{Error: err}
 	}
 
-	_, _, err = req.Service.Endpoints["ShareFileToUserEndpoint"].CallEncode([]interface{}{
+	_, _, err = req.Service.Endpoints["ShareFileToUserEndpoint"].CallEncodeContext(req.Context, []interface{}{
 		req.Authorization,
 		string(body),
 	})
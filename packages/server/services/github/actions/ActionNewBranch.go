@@ -33,7 +33,7 @@ func hasANewBranch(req static.AreaRequest) shared.AreaResponse {
 		query["protected"] = (*req.Store)["req:only:protected"].(string)
 	}
 
-	encode, httpResp, err := req.Service.Endpoints["GetAllBranchFromRepositoryEndpoint"].CallEncode([]interface{}{
+	encode, httpResp, err := req.Service.Endpoints["GetAllBranchFromRepositoryEndpoint"].CallEncodeContext(req.Context, []interface{}{
 		req.Authorization,
 		userLogin,
 		(*req.Store)["req:repository:name"],
@@ -45,7 +45,7 @@ func hasANewCollaborator(req static.AreaRequest) shared.AreaResponse {
 		query["affiliation"] = (*req.Store)["req:collaborator:affiliation"].(string)
 	}
 
-	encode, httpResp, err := req.Service.Endpoints["GetAllCollaboratorsFromRepositoryEndpoint"].CallEncode([]interface{}{
+	encode, httpResp, err := req.Service.Endpoints["GetAllCollaboratorsFromRepositoryEndpoint"].CallEncodeContext(req.Context, []interface{}{
 		req.Authorization,
 		userLogin,
 		(*req.Store)["req:repository:name"],
@@ -39,7 +39,7 @@ func hasANewCommit(req static.AreaRequest) shared.AreaResponse {
 		query["author"] = (*req.Store)["req:commit:author"].(string)
 	}
 
-	encode, _, err := req.Service.Endpoints["GetAllCommitFromRepositoryEndpoint"].CallEncode([]interface{}{
+	encode, _, err := req.Service.Endpoints["GetAllCommitFromRepositoryEndpoint"].CallEncodeContext(req.Context, []interface{}{
 		req.Authorization,
 		userLogin,
 		(*req.Store)["req:repository:name"],
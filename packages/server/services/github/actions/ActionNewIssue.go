@@ -41,7 +41,7 @@ func hasANewIssue(req static.AreaRequest) shared.AreaResponse {
 		query["filter"] = (*req.Store)["req:issue:filter"].(string)
 	}
 
-	encode, _, err := req.Service.Endpoints["GetAllIssueFromRepositoryEndpoint"].CallEncode([]interface{}{
+	encode, _, err := req.Service.Endpoints["GetAllIssueFromRepositoryEndpoint"].CallEncodeContext(req.Context, []interface{}{
 		req.Authorization,
 		userLogin,
 		(*req.Store)["req:repository:name"],
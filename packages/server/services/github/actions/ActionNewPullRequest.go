@@ -30,7 +30,7 @@ func hasANewPullRequest(req static.AreaRequest) shared.AreaResponse {
 	query["direction"] = "desc"
 	query["per_page"] = "1"
 
-	encode, _, err := req.Service.Endpoints["GetAllPullRequestFromRepositoryEndpoint"].CallEncode([]interface{}{
+	encode, _, err := req.Service.Endpoints["GetAllPullRequestFromRepositoryEndpoint"].CallEncodeContext(req.Context, []interface{}{
 		req.Authorization,
 		userLogin,
 		(*req.Store)["req:repository:name"],
@@ -6,6 +6,7 @@ import (
 	"area-server/services/github/common"
 	"area-server/utils"
 	"encoding/json"
+	"strconv"
 )
 
 // `GetAllReleaseFromRepositoryResponse` is a struct with a field `Releases` of type
@@ -25,34 +26,33 @@ func hasANewRelease(req static.AreaRequest) shared.AreaResponse {
 	}
 
 	query := make(map[string]string)
-	query["per_page"] = "1"
+	query["per_page"] = "30"
 
-	encode, httpResp, err := req.Service.Endpoints["GetAllReleaseFromRepositoryEndpoint"].CallEncode([]interface{}{
+	it := req.Service.Endpoints["GetAllReleaseFromRepositoryEndpoint"].Paginate(req.Context, []interface{}{
 		req.Authorization,
 		userLogin,
 		(*req.Store)["req:repository:name"],
 		query,
 	})
 
+	// The releases are walked until the last release seen (by ID)
+	items, err := utils.IsLatestByCheckpoint(req.Store, it, func(item json.RawMessage) (string, error) {
+		var release common.Release
+		if err := json.Unmarshal(item, &release); err != nil {
+			return "", err
+		}
+		return strconv.Itoa(release.ID), nil
+	})
 	if err != nil {
 		return shared.AreaResponse{Error: err}
 	}
-
-	releases := GetAllReleaseFromRepositoryResponse{}
-	if err := json.Unmarshal(encode, &releases.Releases); err != nil {
-		return shared.AreaResponse{Error: err}
+	if len(items) == 0 {
+		return shared.AreaResponse{Success: false}
 	}
 
-	nbReleases, errP := common.GetLastPage(httpResp.Header.Get("Link"))
-	if errP != nil {
-		return shared.AreaResponse{Error: errP}
-	}
-	ok, errL := utils.IsLatestBasic(req.Store, nbReleases)
-	if errL != nil {
-		return shared.AreaResponse{Error: errL}
-	}
-	if !ok {
-		return shared.AreaResponse{Success: false}
+	releases := GetAllReleaseFromRepositoryResponse{Releases: make([]common.Release, 1)}
+	if err := json.Unmarshal(items[0], &releases.Releases[0]); err != nil {
+		return shared.AreaResponse{Error: err}
 	}
 
 	req.Logger.WriteInfo("[Action] New release found (Repo: "+(*req.Store)["req:repository:name"].(string)+") (Name: "+releases.Releases[0].Name+")", false)
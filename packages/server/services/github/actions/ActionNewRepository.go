@@ -44,13 +44,13 @@ func hasANewRepository(req static.AreaRequest) shared.AreaResponse {
 	var err error
 	var encode []byte
 	if (*req.Store)["req:repository:owner"] != nil {
-		encode, _, err = req.Service.Endpoints["GetAllRepositoriesFromUserEndpoint"].CallEncode([]interface{}{
+		encode, _, err = req.Service.Endpoints["GetAllRepositoriesFromUserEndpoint"].CallEncodeContext(req.Context, []interface{}{
 			req.Authorization,
 			(*req.Store)["req:repository:owner"],
 			query,
 		})
 	} else {
-		encode, _, err = req.Service.Endpoints["GetAllRepositoriesEndpoint"].CallEncode([]interface{}{
+		encode, _, err = req.Service.Endpoints["GetAllRepositoriesEndpoint"].CallEncodeContext(req.Context, []interface{}{
 			req.Authorization,
 			query,
 		})
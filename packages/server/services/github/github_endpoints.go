@@ -40,41 +40,50 @@ func GithubEndpoints() static.ServiceEndpoint {
 			BaseURL:        "https://api.github.com/user/repos",
 			Params:         GetAllRepositoriesEndpointParams,
 			ExpectedStatus: []int{200},
+			Conditional:    true,
 		},
 		"GetAllRepositoriesFromUserEndpoint": {
 			BaseURL:        "https://api.github.com/users/${login}/repos",
 			Params:         GetAllRepositoriesFromUserEndpointParams,
 			ExpectedStatus: []int{200},
+			Conditional:    true,
 		},
 		"GetAllCollaboratorsFromRepositoryEndpoint": {
 			BaseURL:        "https://api.github.com/repos/${owner}/${repo}/collaborators",
 			Params:         GetAllFromRepositoryEndpointParams,
 			ExpectedStatus: []int{200},
+			Conditional:    true,
 		},
 		"GetAllCommitFromRepositoryEndpoint": {
 			BaseURL:        "https://api.github.com/repos/${owner}/${repo}/commits",
 			Params:         GetAllFromRepositoryEndpointParams,
 			ExpectedStatus: []int{200},
+			Conditional:    true,
 		},
 		"GetAllReleaseFromRepositoryEndpoint": {
 			BaseURL:        "https://api.github.com/repos/${owner}/${repo}/releases",
 			Params:         GetAllFromRepositoryEndpointParams,
 			ExpectedStatus: []int{200},
+			Conditional:    true,
+			Pagination:     &utils.Pagination{Type: utils.PaginationLink},
 		},
 		"GetAllPullRequestFromRepositoryEndpoint": {
 			BaseURL:        "https://api.github.com/repos/${owner}/${repo}/pulls",
 			Params:         GetAllFromRepositoryEndpointParams,
 			ExpectedStatus: []int{200},
+			Conditional:    true,
 		},
 		"GetAllIssueFromRepositoryEndpoint": {
 			BaseURL:        "https://api.github.com/repos/${owner}/${repo}/issues",
 			Params:         GetAllFromRepositoryEndpointParams,
 			ExpectedStatus: []int{200},
+			Conditional:    true,
 		},
 		"GetAllBranchFromRepositoryEndpoint": {
 			BaseURL:        "https://api.github.com/repos/${owner}/${repo}/branches",
 			Params:         GetAllFromRepositoryEndpointParams,
 			ExpectedStatus: []int{200},
+			Conditional:    true,
 		},
 		// Reactions
 		"CreateNewGistEndpoint": {
@@ -0,0 +1,21 @@
+package github
+
+import (
+	"area-server/classes/static"
+	"area-server/services/github/actions"
+	"area-server/services/servicetest"
+	"testing"
+)
+
+func TestNewBranchFixtures(t *testing.T) {
+	service := static.Service{Name: "github", Endpoints: GithubEndpoints()}
+	store := map[string]interface{}{"req:repository:name": "area"}
+	req := servicetest.NewRequest(&service, store, map[string]interface{}{"login": "limeal"})
+
+	// The first poll only saves the number of branches
+	servicetest.ReplayPolls(t, "testdata/new_branch.json", actions.DescriptorForGithubActionAnyNewBranch(), req, []servicetest.Poll{
+		{Triggered: false},
+		{Triggered: false},
+		{Triggered: true, Data: map[string]interface{}{"github:branch:name": "feature/fixtures"}},
+	})
+}
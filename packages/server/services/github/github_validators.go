@@ -29,179 +29,163 @@ func GithubValidators() static.ServiceValidator {
 	}
 }
 
+// It returns the owner of the repository, the login of the authorized user is used when no owner is
+// provided
+func repositoryOwner(auth *models.Authorization, store map[string]interface{}) interface{} {
+	if store["req:repository:owner"] != nil {
+		return store["req:repository:owner"]
+	}
+
+	var other map[string]interface{}
+	if err := json.Unmarshal(auth.Other, &other); err != nil {
+		return nil
+	}
+	return other["login"]
+}
+
 // ----------------------- Validators -----------------------
 
-// It takes an authorization, a service, a value, and a store, and returns a boolean
-func UserValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) bool {
+// It checks if the value is the login of an existing Github user
+func UserValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) *static.ValidationError {
 	if value == nil {
-		return false
+		return static.MissingError()
 	}
 
 	if _, ok := value.(string); !ok {
-		return false
+		return static.InvalidTypeError("string")
 	}
 
 	_, _, err := service.Endpoints["GetUserByLoginEndpoint"].CallEncode([]interface{}{auth, value.(string)})
 	if err != nil {
-		return false
+		return static.NotFoundError("user")
 	}
 
-	return true
+	return nil
 }
 
 // It takes an authorization, a service, a value, and a store, and returns true if the value is a
 // string and the value is a valid repository name for the user
-func RepoNameValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) bool {
+func RepoNameValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) *static.ValidationError {
 	if value == nil {
-		return false
+		return static.MissingError()
 	}
 
 	if _, ok := value.(string); !ok {
-		return false
+		return static.InvalidTypeError("string")
 	}
 
-	owner := store["req:repository:owner"]
-
+	owner := repositoryOwner(auth, store)
 	if owner == nil {
-		var other map[string]interface{}
-		err := json.Unmarshal(auth.Other, &other)
-		if err != nil {
-			return false
-		}
-		owner = other["login"]
-	}
-
-	if owner == nil {
-		return false
+		return static.MissingDependencyError("req:repository:owner")
 	}
 
 	_, _, err := service.Endpoints["GetRepositoryFromNameEndpoint"].CallEncode([]interface{}{auth, owner, value.(string)})
 	if err != nil {
-		return false
+		return static.NotFoundError("repository")
 	}
 
-	return true
+	return nil
 }
 
 // If the value is a string, and it's one of the following values: "public", "private", "member",
 // "owner", or "all", then it's valid
-func RepoTypeValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) bool {
+func RepoTypeValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) *static.ValidationError {
 	if value == nil {
-		return false
+		return static.MissingError()
 	}
 
 	if _, ok := value.(string); !ok {
-		return false
+		return static.InvalidTypeError("string")
 	}
 
 	if value == "public" || value == "private" || value == "member" || value == "owner" || value == "all" {
-		return true
+		return nil
 	}
 
-	return false
+	return static.NotAllowedError("public", "private", "member", "owner", "all")
 }
 
 // If the value is a string, and the string is one of the valid permissions, then return true
-func PermissionValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) bool {
+func PermissionValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) *static.ValidationError {
 	if value == nil {
-		return false
+		return static.MissingError()
 	}
 
 	if _, ok := value.(string); !ok {
-		return false
+		return static.InvalidTypeError("string")
 	}
 
 	if value == "admin" || value == "push" || value == "pull" || value == "maintain" || value == "triage" {
-		return true
+		return nil
 	}
 
-	return false
+	return static.NotAllowedError("admin", "push", "pull", "maintain", "triage")
 }
 
 // If the value is "outside", "direct", or "all", then it's valid
-func AffiliationValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) bool {
+func AffiliationValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) *static.ValidationError {
 	if value == nil {
-		return false
+		return static.MissingError()
 	}
 
 	if _, ok := value.(string); !ok {
-		return false
+		return static.InvalidTypeError("string")
 	}
 
 	if value == "outside" || value == "direct" || value == "all" {
-		return true
+		return nil
 	}
 
-	return false
+	return static.NotAllowedError("outside", "direct", "all")
 }
 
 // It checks if the value is a string, and if it is, it checks if the value is a valid branch in the
 // repository
-func BranchValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) bool {
+func BranchValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) *static.ValidationError {
 	if value == nil {
-		return false
+		return static.MissingError()
 	}
 
 	if _, ok := value.(string); !ok {
-		return false
+		return static.InvalidTypeError("string")
 	}
 
 	if store["req:repository:name"] == nil {
-		return false
-	}
-
-	owner := store["req:repository:owner"]
-
-	if owner == nil {
-		var other map[string]interface{}
-		err := json.Unmarshal(auth.Other, &other)
-		if err != nil {
-			return false
-		}
-		owner = other["login"]
+		return static.MissingDependencyError("req:repository:name")
 	}
 
+	owner := repositoryOwner(auth, store)
 	if owner == nil {
-		return false
+		return static.MissingDependencyError("req:repository:owner")
 	}
 
 	_, _, err := service.Endpoints["GetBranchFromRepositoryEndpoint"].CallEncode([]interface{}{auth, owner, store["req:repository:name"], value.(string)})
 	if err != nil {
-		return false
+		return static.NotFoundError("branch")
 	}
 
-	return true
+	return nil
 }
 
 // If the value is a string, and the repository name is in the store, and the owner is in the store or
 // in the authorization's `other` field, and the GitHub API returns an error when we try to get the
 // release by tag name, then the value is valid
-func TagValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) bool {
+func TagValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) *static.ValidationError {
 	if value == nil {
-		return false
+		return static.MissingError()
 	}
 
 	if _, ok := value.(string); !ok {
-		return false
+		return static.InvalidTypeError("string")
 	}
 
 	if store["req:repository:name"] == nil {
-		return false
-	}
-
-	owner := store["req:repository:owner"]
-
-	if owner == nil {
-		var other map[string]interface{}
-		err := json.Unmarshal(auth.Other, &other)
-		if err != nil {
-			return false
-		}
-		owner = other["login"]
+		return static.MissingDependencyError("req:repository:name")
 	}
 
+	owner := repositoryOwner(auth, store)
 	if owner == nil {
-		return false
+		return static.MissingDependencyError("req:repository:owner")
 	}
 
 	_, _, err := service.Endpoints["GetReleaseByTagNameEndpoint"].CallEncode([]interface{}{
@@ -212,79 +196,68 @@ func TagValidator(auth *models.Authorization, service *static.Service, value int
 	})
 
 	if err != nil {
-		return true
+		return nil
 	}
 
-	return false
+	return static.AlreadyExistsError("release")
 }
 
 // `CommitishTypeValidator` validates that the value of the `commitish_type` parameter is either
 // `branch` or `commit`
-func CommitishTypeValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) bool {
+func CommitishTypeValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) *static.ValidationError {
 	if value == nil {
-		return false
+		return static.MissingError()
 	}
 
 	if _, ok := value.(string); !ok {
-		return false
+		return static.InvalidTypeError("string")
 	}
 
 	if store["req:release:commitish:value"] == nil {
-		return false
+		return static.MissingDependencyError("req:release:commitish:value")
 	}
 
 	if value == "branch" || value == "commit" {
-		return true
+		return nil
 	}
 
-	return false
+	return static.NotAllowedError("branch", "commit")
 }
 
 // It checks if the commitish value is valid for the given repository
-func CommitishValueValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) bool {
+func CommitishValueValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) *static.ValidationError {
 	if value == nil {
-		return false
+		return static.MissingError()
 	}
 
 	if _, ok := value.(string); !ok {
-		return false
+		return static.InvalidTypeError("string")
 	}
 
 	if store["req:release:commitish:type"] == nil {
-		return false
+		return static.MissingDependencyError("req:release:commitish:type")
 	}
 
 	if store["req:repository:name"] == nil {
-		return false
-	}
-
-	owner := store["req:repository:owner"]
-
-	if owner == nil {
-
-		var other map[string]interface{}
-		err := json.Unmarshal(auth.Other, &other)
-		if err != nil {
-			return false
-		}
-		owner = other["login"]
+		return static.MissingDependencyError("req:repository:name")
 	}
 
+	owner := repositoryOwner(auth, store)
 	if owner == nil {
-		return false
+		return static.MissingDependencyError("req:repository:owner")
 	}
 
 	if store["req:release:commitish:type"] == "branch" {
 		_, _, err := service.Endpoints["GetBranchFromRepositoryEndpoint"].CallEncode([]interface{}{auth, owner, store["req:repository:name"], value.(string)})
 		if err != nil {
-			return false
+			return static.NotFoundError("branch")
 		}
 	} else {
 		_, _, err := service.Endpoints["GetCommitFromRepositoryEndpoint"].CallEncode([]interface{}{auth, owner, store["req:repository:name"], value.(string)})
 		if err != nil {
-			return false
+			return static.NotFoundError("commit")
 		}
 	}
 
-	return true
+	return nil
 }
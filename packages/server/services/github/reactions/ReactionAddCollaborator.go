@@ -19,7 +19,7 @@ func addCollaborator(req static.AreaRequest) shared.AreaResponse {
 		})
 	}
 
-	_, httpResp, err := req.Service.Endpoints["AddCollaboratorToRepositoryEndpoint"].Call([]interface{}{
+	_, httpResp, err := req.Service.Endpoints["AddCollaboratorToRepositoryEndpoint"].CallContext(req.Context, []interface{}{
 		req.Authorization,
 		owner,
 		repository,
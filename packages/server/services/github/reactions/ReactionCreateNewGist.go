@@ -41,7 +41,7 @@ func createNewGist(req static.AreaRequest) shared.AreaResponse {
 		return shared.AreaResponse{Error: err}
 	}
 
-	_, _, errr := req.Service.Endpoints["CreateNewGistEndpoint"].Call([]interface{}{req.Authorization, string(str)})
+	_, _, errr := req.Service.Endpoints["CreateNewGistEndpoint"].CallContext(req.Context, []interface{}{req.Authorization, string(str)})
 	if errr != nil {
 		return shared.AreaResponse{Error: err}
 	}
@@ -52,7 +52,7 @@ func createNewIssue(req static.AreaRequest) shared.AreaResponse {
 		return shared.AreaResponse{Error: err}
 	}
 
-	_, _, errr := req.Service.Endpoints["CreateNewIssueEndpoint"].Call([]interface{}{
+	_, _, errr := req.Service.Endpoints["CreateNewIssueEndpoint"].CallContext(req.Context, []interface{}{
 		req.Authorization,
 		owner,
 		repository,
@@ -67,7 +67,7 @@ func createNewPullRequest(req static.AreaRequest) shared.AreaResponse {
 		return shared.AreaResponse{Error: err}
 	}
 
-	_, _, errr := req.Service.Endpoints["CreateNewPullRequestEndpoint"].CallEncode([]interface{}{
+	_, _, errr := req.Service.Endpoints["CreateNewPullRequestEndpoint"].CallEncodeContext(req.Context, []interface{}{
 		req.Authorization,
 		repoOwner,
 		repoName,
@@ -60,7 +60,7 @@ func createNewRelease(req static.AreaRequest) shared.AreaResponse {
 		return shared.AreaResponse{Error: errC}
 	}
 
-	_, _, err := req.Service.Endpoints["CreateNewReleaseEndpoint"].CallEncode([]interface{}{
+	_, _, err := req.Service.Endpoints["CreateNewReleaseEndpoint"].CallEncodeContext(req.Context, []interface{}{
 		req.Authorization,
 		owner,
 		repository,
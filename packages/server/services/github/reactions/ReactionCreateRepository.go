@@ -30,7 +30,7 @@ func createNewRepository(req static.AreaRequest) shared.AreaResponse {
 		return shared.AreaResponse{Error: err}
 	}
 
-	_, _, errr := req.Service.Endpoints["CreateNewRepositoryEndpoint"].Call([]interface{}{
+	_, _, errr := req.Service.Endpoints["CreateNewRepositoryEndpoint"].CallContext(req.Context, []interface{}{
 		req.Authorization,
 		string(ebody),
 	})
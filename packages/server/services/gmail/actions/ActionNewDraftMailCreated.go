@@ -89,7 +89,7 @@ func hasANewDraftMailBeenCreated(req static.AreaRequest) shared.AreaResponse {
 		(*req.Store)["ctx:user:id"] = "me"
 	}
 
-	encode, _, err := req.Service.Endpoints["GetAllDraftMailEndpoint"].CallEncode([]interface{}{
+	encode, _, err := req.Service.Endpoints["GetAllDraftMailEndpoint"].CallEncodeContext(req.Context, []interface{}{
 		req.Authorization,
 		(*req.Store)["ctx:user:id"],
 		query,
@@ -108,7 +108,7 @@ func hasANewDraftMailBeenCreated(req static.AreaRequest) shared.AreaResponse {
 		return shared.AreaResponse{Success: false}
 	}
 
-	encode, _, err = req.Service.Endpoints["GetMailInfoEndpoint"].CallEncode([]interface{}{
+	encode, _, err = req.Service.Endpoints["GetMailInfoEndpoint"].CallEncodeContext(req.Context, []interface{}{
 		req.Authorization,
 		(*req.Store)["ctx:user:id"],
 		draftList.Drafts[0].Message.ID,
@@ -55,7 +55,7 @@ func onNewFilterCreated(req static.AreaRequest) shared.AreaResponse {
 		(*req.Store)["ctx:user:id"] = "me"
 	}
 
-	encode, _, err := req.Service.Endpoints["ListFiltersEndpoint"].CallEncode([]interface{}{
+	encode, _, err := req.Service.Endpoints["ListFiltersEndpoint"].CallEncodeContext(req.Context, []interface{}{
 		req.Authorization,
 		(*req.Store)["ctx:user:id"],
 	})
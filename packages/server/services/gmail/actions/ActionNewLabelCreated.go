@@ -49,7 +49,7 @@ func onNewLabelCreated(req static.AreaRequest) shared.AreaResponse {
 		(*req.Store)["ctx:user:id"] = "me"
 	}
 
-	encode, _, err := req.Service.Endpoints["ListLabelsEndpoint"].CallEncode([]interface{}{
+	encode, _, err := req.Service.Endpoints["ListLabelsEndpoint"].CallEncodeContext(req.Context, []interface{}{
 		req.Authorization,
 		(*req.Store)["ctx:user:id"],
 	})
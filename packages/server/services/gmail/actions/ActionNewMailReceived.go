@@ -65,7 +65,7 @@ func hasReceivedANewMail(req static.AreaRequest) shared.AreaResponse {
 		(*req.Store)["ctx:user:id"] = "me"
 	}
 
-	encode, _, err := req.Service.Endpoints["GetAllMailEndpoint"].CallEncode([]interface{}{
+	encode, _, err := req.Service.Endpoints["GetAllMailEndpoint"].CallEncodeContext(req.Context, []interface{}{
 		req.Authorization,
 		(*req.Store)["ctx:user:id"],
 		query,
@@ -86,7 +86,7 @@ func hasReceivedANewMail(req static.AreaRequest) shared.AreaResponse {
 		return shared.AreaResponse{Success: false}
 	}
 
-	encode, _, err = req.Service.Endpoints["GetMailInfoEndpoint"].CallEncode([]interface{}{
+	encode, _, err = req.Service.Endpoints["GetMailInfoEndpoint"].CallEncodeContext(req.Context, []interface{}{
 		req.Authorization,
 		(*req.Store)["ctx:user:id"],
 		mailList.Messages[0].ID,
@@ -24,21 +24,25 @@ func GmailEndpoints() static.ServiceEndpoint {
 			BaseURL:        "https://www.googleapis.com/gmail/v1/users/${id}/messages",
 			Params:         GetAllMailEndpointParams,
 			ExpectedStatus: []int{200},
+			Conditional:    true,
 		},
 		"GetAllDraftMailEndpoint": {
 			BaseURL:        "https://www.googleapis.com/gmail/v1/users/${id}/drafts",
 			Params:         GetAllMailEndpointParams,
 			ExpectedStatus: []int{200},
+			Conditional:    true,
 		},
 		"ListLabelsEndpoint": {
 			BaseURL:        "https://www.googleapis.com/gmail/v1/users/${id}/labels",
 			Params:         BasicEndpointParams,
 			ExpectedStatus: []int{200, 204},
+			Conditional:    true,
 		},
 		"ListFiltersEndpoint": {
 			BaseURL:        "https://www.googleapis.com/gmail/v1/users/${id}/settings/filters",
 			Params:         BasicEndpointParams,
 			ExpectedStatus: []int{200, 204},
+			Conditional:    true,
 		},
 		// Reactions
 		"SendMailEndpoint": {
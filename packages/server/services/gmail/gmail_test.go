@@ -0,0 +1,21 @@
+package gmail
+
+import (
+	"area-server/classes/static"
+	"area-server/services/gmail/actions"
+	"area-server/services/servicetest"
+	"testing"
+)
+
+func TestNewMailReceivedFixtures(t *testing.T) {
+	service := static.Service{Name: "gmail", Endpoints: GmailEndpoints()}
+	// The applet was created before the second mail was received
+	store := map[string]interface{}{"ctx:time:start": int64(1700000000000)}
+	req := servicetest.NewRequest(&service, store, nil)
+
+	servicetest.ReplayPolls(t, "testdata/new_mail_received.json", actions.DescriptorForGmailActionNewMailReceived(), req, []servicetest.Poll{
+		{Triggered: false},
+		{Triggered: true, Data: map[string]interface{}{"gmail:mail:id": "18bd2", "gmail:mail:body": "Hello from the fixtures"}},
+		{Triggered: false},
+	})
+}
@@ -19,19 +19,19 @@ func GmailValidators() static.ServiceValidator {
 
 // ------------------------------ Validators ------------------------------
 
-// It takes an authorization, a service, a value, and a store, and returns true if the value is a valid
-// user ID
-func UserIDValidator(authorization *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) bool {
+// It takes an authorization, a service, a value, and a store, and returns an error if the value is not
+// a valid user ID
+func UserIDValidator(authorization *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) *static.ValidationError {
 	if value == nil {
-		return false
+		return static.MissingError()
 	}
 
 	if _, ok := value.(string); !ok {
-		return false
+		return static.InvalidTypeError("string")
 	}
 
 	if value == "{{gmail:user:id}}" {
-		return true
+		return nil
 	}
 
 	_, _, err := service.Endpoints["GmailGetProfileEndpoint"].CallEncode([]interface{}{
@@ -40,8 +40,8 @@ func UserIDValidator(authorization *models.Authorization, service *static.Servic
 	})
 
 	if err != nil {
-		return false
+		return static.NotFoundError("user")
 	}
 
-	return true
+	return nil
 }
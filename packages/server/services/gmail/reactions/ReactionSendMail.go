@@ -32,7 +32,7 @@ func createNewMail(req static.AreaRequest) shared.AreaResponse {
 	}
 
 	if (*req.Store)["ctx:mail:from"] == nil {
-		encode, _, err := req.Service.Endpoints["GetProfileEndpoint"].CallEncode([]interface{}{
+		encode, _, err := req.Service.Endpoints["GetProfileEndpoint"].CallEncodeContext(req.Context, []interface{}{
 			req.Authorization,
 			(*req.Store)["ctx:user:id"],
 		})
@@ -87,7 +87,7 @@ func createNewMail(req static.AreaRequest) shared.AreaResponse {
 	}
 
 	raw = base64.URLEncoding.EncodeToString([]byte(raw))
-	_, _, err := req.Service.Endpoints["SendMailEndpoint"].CallEncode([]interface{}{
+	_, _, err := req.Service.Endpoints["SendMailEndpoint"].CallEncodeContext(req.Context, []interface{}{
 		req.Authorization,
 		(*req.Store)["ctx:user:id"],
 		raw,
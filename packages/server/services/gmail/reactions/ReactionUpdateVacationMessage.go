@@ -47,7 +47,7 @@ func updateVacationMessage(req static.AreaRequest) shared.AreaResponse {
 		return shared.AreaResponse{Error: err}
 	}
 
-	_, httpResp, errr := req.Service.Endpoints["UpdateVacationMessageEndpoint"].CallEncode([]interface{}{
+	_, httpResp, errr := req.Service.Endpoints["UpdateVacationMessageEndpoint"].CallEncodeContext(req.Context, []interface{}{
 		req.Authorization,
 		(*req.Store)["ctx:user:id"],
 		string(body),
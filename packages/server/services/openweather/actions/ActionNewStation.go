@@ -11,7 +11,7 @@ import (
 // It gets the latest station from the OpenWeatherMap API, and returns it as a response
 func onNewStation(req static.AreaRequest) shared.AreaResponse {
 
-	encode, _, err := req.Service.Endpoints["GetAllStationsEndpoint"].CallEncode([]interface{}{})
+	encode, _, err := req.Service.Endpoints["GetAllStationsEndpoint"].CallEncodeContext(req.Context, []interface{}{})
 
 	if err != nil {
 		return shared.AreaResponse{Error: err}
@@ -57,7 +57,7 @@ func isRainingAtLocation(req static.AreaRequest) shared.AreaResponse {
 			query = (*req.Store)["req:city:name"].(string)
 		}
 
-		encode, _, err := req.Service.Endpoints["GetCoordsByLocationEndpoint"].CallEncode([]interface{}{
+		encode, _, err := req.Service.Endpoints["GetCoordsByLocationEndpoint"].CallEncodeContext(req.Context, []interface{}{
 			query,
 		})
 
@@ -77,7 +77,7 @@ func isRainingAtLocation(req static.AreaRequest) shared.AreaResponse {
 		}
 	}
 
-	encode, _, err := req.Service.Endpoints["GetWeatherAtLocationEndpoint"].CallEncode([]interface{}{
+	encode, _, err := req.Service.Endpoints["GetWeatherAtLocationEndpoint"].CallEncodeContext(req.Context, []interface{}{
 		(*req.Store)["ctx:location"],
 	})
 
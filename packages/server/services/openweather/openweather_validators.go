@@ -20,22 +20,25 @@ func StationIdValidator(
 	service *static.Service,
 	value interface{},
 	store map[string]interface{},
-) bool {
+) *static.ValidationError {
 	if value == nil {
-		return false
+		return static.MissingError()
 	}
 
 	if _, ok := value.(string); !ok {
-		return false
+		return static.InvalidTypeError("string")
 	}
 
 	if value == "{{openw:station:id}}" {
-		return true
+		return nil
 	}
 
 	_, _, err := service.Endpoints["GetStationEndpoint"].CallEncode([]interface{}{
 		value.(string),
 	})
+	if err != nil {
+		return static.NotFoundError("station")
+	}
 
-	return err == nil
+	return nil
 }
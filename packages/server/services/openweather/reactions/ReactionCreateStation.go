@@ -47,7 +47,7 @@ func createNewStation(req static.AreaRequest) shared.AreaResponse {
 		return shared.AreaResponse{Error: err}
 	}
 
-	_, _, err = req.Service.Endpoints["CreateStationEndpoint"].Call([]interface{}{
+	_, _, err = req.Service.Endpoints["CreateStationEndpoint"].CallContext(req.Context, []interface{}{
 		string(ebody),
 	})
 
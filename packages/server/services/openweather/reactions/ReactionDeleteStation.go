@@ -8,7 +8,7 @@ import (
 // It calls the DeleteStationEndpoint endpoint, passing in the station ID from the request store
 func deleteStation(req static.AreaRequest) shared.AreaResponse {
 
-	_, _, err := req.Service.Endpoints["DeleteStationEndpoint"].Call([]interface{}{
+	_, _, err := req.Service.Endpoints["DeleteStationEndpoint"].CallContext(req.Context, []interface{}{
 		(*req.Store)["req:station:id"],
 	})
 
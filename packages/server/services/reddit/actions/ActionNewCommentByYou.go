@@ -17,7 +17,7 @@ func newCommentByYou(req static.AreaRequest) shared.AreaResponse {
 	query["limit"] = "100"
 	query["sort"] = "new"
 
-	encode, _, err := req.Service.Endpoints["GetUserCommentsEndpoint"].CallEncode([]interface{}{
+	encode, _, err := req.Service.Endpoints["GetUserCommentsEndpoint"].CallEncodeContext(req.Context, []interface{}{
 		req.Authorization,
 		user,
 		query,
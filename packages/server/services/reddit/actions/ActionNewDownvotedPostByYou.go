@@ -17,7 +17,7 @@ func newDownvotedPostByYou(req static.AreaRequest) shared.AreaResponse {
 	query["limit"] = "100"
 	query["sort"] = "new"
 
-	encode, _, err := req.Service.Endpoints["GetUserDownvotedPostsEndpoint"].CallEncode([]interface{}{
+	encode, _, err := req.Service.Endpoints["GetUserDownvotedPostsEndpoint"].CallEncodeContext(req.Context, []interface{}{
 		req.Authorization,
 		userName,
 		query,
@@ -8,6 +8,14 @@ import (
 	"encoding/json"
 )
 
+// `PostListingItem` is a post of a Reddit listing.
+// @property {string} Kind - The type of the item (t3).
+// @property {common.RedditPost} Data - The post.
+type PostListingItem struct {
+	Kind string            `json:"kind"`
+	Data common.RedditPost `json:"data"`
+}
+
 // It checks if there's a new post by the user on Reddit
 func newPostByYou(req static.AreaRequest) shared.AreaResponse {
 
@@ -17,44 +25,45 @@ func newPostByYou(req static.AreaRequest) shared.AreaResponse {
 	query["limit"] = "100"
 	query["sort"] = "new"
 
-	encode, _, err := req.Service.Endpoints["GetUserPostsEndpoint"].CallEncode([]interface{}{
+	it := req.Service.Endpoints["GetUserPostsEndpoint"].Paginate(req.Context, []interface{}{
 		req.Authorization,
 		userName,
 		query,
 	})
+
+	// The posts are walked until the last post seen (by fullname)
+	posts, err := utils.IsLatestByCheckpoint(req.Store, it, func(item json.RawMessage) (string, error) {
+		post := PostListingItem{}
+		if err := json.Unmarshal(item, &post); err != nil {
+			return "", err
+		}
+		return post.Data.Name, nil
+	})
 	if err != nil {
 		return shared.AreaResponse{Error: err}
 	}
-
-	newPosts := common.NewPostsOnSubRedditResponse{}
-	errr := json.Unmarshal(encode, &newPosts)
-	if errr != nil {
-		return shared.AreaResponse{Error: errr}
-	}
-
-	nbPosts := len(newPosts.Data.Children)
-	ok, errL := utils.IsLatestBasic(req.Store, nbPosts)
-	if errL != nil {
-		return shared.AreaResponse{Error: errL}
+	if len(posts) == 0 {
+		return shared.AreaResponse{Success: false}
 	}
 
-	if !ok {
-		return shared.AreaResponse{Success: false}
+	post := PostListingItem{}
+	if err := json.Unmarshal(posts[0], &post); err != nil {
+		return shared.AreaResponse{Error: err}
 	}
 
 	return shared.AreaResponse{
 		Success: true,
 		Data: map[string]interface{}{
 			// Subreddit
-			"reddit:subreddit:id":   newPosts.Data.Children[0].Data.SubRedditID,
-			"reddit:subreddit:name": newPosts.Data.Children[0].Data.SubRedditName,
+			"reddit:subreddit:id":   post.Data.SubRedditID,
+			"reddit:subreddit:name": post.Data.SubRedditName,
 			// Post
-			"reddit:post:id":     newPosts.Data.Children[0].Data.ID,
-			"reddit:post:name":   newPosts.Data.Children[0].Data.Name,
-			"reddit:post:title":  newPosts.Data.Children[0].Data.Title,
-			"reddit:post:author": newPosts.Data.Children[0].Data.Author,
-			"reddit:post:url":    newPosts.Data.Children[0].Data.URL,
-			"reddit:post:text":   newPosts.Data.Children[0].Data.Text,
+			"reddit:post:id":     post.Data.ID,
+			"reddit:post:name":   post.Data.Name,
+			"reddit:post:title":  post.Data.Title,
+			"reddit:post:author": post.Data.Author,
+			"reddit:post:url":    post.Data.URL,
+			"reddit:post:text":   post.Data.Text,
 		},
 	}
 }
@@ -63,7 +72,7 @@ func newPostByYou(req static.AreaRequest) shared.AreaResponse {
 func DescriptorForRedditActionNewPostByYou() static.ServiceArea {
 	return static.ServiceArea{
 		Name:        "new_post_by_you",
-		Description: "Triggered when you submit a new post (<100)",
+		Description: "Triggered when you submit a new post",
 		Method:      newPostByYou,
 		Components: []string{
 			// Subreddit
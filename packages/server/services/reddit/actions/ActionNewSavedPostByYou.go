@@ -17,7 +17,7 @@ func newSavedPostByYou(req static.AreaRequest) shared.AreaResponse {
 	query["limit"] = "100"
 	query["sort"] = "new"
 
-	encode, _, err := req.Service.Endpoints["GetUserSavedPostsEndpoint"].CallEncode([]interface{}{
+	encode, _, err := req.Service.Endpoints["GetUserSavedPostsEndpoint"].CallEncodeContext(req.Context, []interface{}{
 		req.Authorization,
 		userName,
 		query,
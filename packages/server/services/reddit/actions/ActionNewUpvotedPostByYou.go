@@ -17,7 +17,7 @@ func newUpvotedPostByYou(req static.AreaRequest) shared.AreaResponse {
 	query["limit"] = "100"
 	query["sort"] = "new"
 
-	encode, _, err := req.Service.Endpoints["GetUserUpvotedPostsEndpoint"].CallEncode([]interface{}{
+	encode, _, err := req.Service.Endpoints["GetUserUpvotedPostsEndpoint"].CallEncodeContext(req.Context, []interface{}{
 		req.Authorization,
 		userName,
 		query,
@@ -10,7 +10,7 @@ import (
 // value
 func sendPrivateMessage(req static.AreaRequest) shared.AreaResponse {
 
-	_, _, err := req.Service.Endpoints["ComposePrivateMessageEndpoint"].CallEncode([]interface{}{
+	_, _, err := req.Service.Endpoints["ComposePrivateMessageEndpoint"].CallEncodeContext(req.Context, []interface{}{
 		req.Authorization,
 		(*req.Store)["req:user:to"],
 		(*req.Store)["req:message:subject"],
@@ -17,7 +17,7 @@ func submitNewLink(req static.AreaRequest) shared.AreaResponse {
 		".+url",
 	})
 
-	_, _, err := req.Service.Endpoints["SubmitNewLinkEndpoint"].CallEncode([]interface{}{
+	_, _, err := req.Service.Endpoints["SubmitNewLinkEndpoint"].CallEncodeContext(req.Context, []interface{}{
 		req.Authorization,
 		subReddit,
 		title,
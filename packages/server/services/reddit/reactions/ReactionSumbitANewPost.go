@@ -16,7 +16,7 @@ func submitNewPost(req static.AreaRequest) shared.AreaResponse {
 	title := utils.GenerateFinalComponent((*req.Store)["req:post:title"].(string), req.ExternalData, []string{})
 	content := utils.GenerateFinalComponent((*req.Store)["req:post:body"].(string), req.ExternalData, []string{})
 
-	_, _, err := req.Service.Endpoints["SubmitNewPostEndpoint"].CallEncode([]interface{}{
+	_, _, err := req.Service.Endpoints["SubmitNewPostEndpoint"].CallEncodeContext(req.Context, []interface{}{
 		req.Authorization,
 		subReddit,
 		title,
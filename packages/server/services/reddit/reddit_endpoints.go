@@ -7,6 +7,14 @@ import (
 	"net/url"
 )
 
+// Reddit listings are paginated with the `after` parameter (fullname of the last item)
+var listingPagination = &utils.Pagination{
+	Type:   utils.PaginationCursor,
+	Items:  "data.children",
+	Cursor: "data.after",
+	Param:  "after",
+}
+
 // It returns a map of strings to static.ServiceEndpoint structs
 func RedditEndpoints() static.ServiceEndpoint {
 	return static.ServiceEndpoint{
@@ -50,6 +58,7 @@ func RedditEndpoints() static.ServiceEndpoint {
 			BaseURL:        "https://oauth.reddit.com/user/${username}/submitted",
 			Params:         GetUserDataEndpointParams,
 			ExpectedStatus: []int{200},
+			Pagination:     listingPagination,
 		},
 		"GetUserCommentsEndpoint": {
 			BaseURL:        "https://oauth.reddit.com/user/${username}/comments",
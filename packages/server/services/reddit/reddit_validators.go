@@ -15,19 +15,19 @@ func RedditValidators() static.ServiceValidator {
 	}
 }
 
-// If the value is a string, and the string is a valid subreddit name, then return true
+// If the value is a string, and the string is a valid subreddit name, then return nil
 func SubRedditNameValidator(
 	authorization *models.Authorization,
 	service *static.Service,
 	value interface{},
 	store map[string]interface{},
-) bool {
+) *static.ValidationError {
 	if value == nil {
-		return false
+		return static.MissingError()
 	}
 
 	if _, ok := value.(string); !ok {
-		return false
+		return static.InvalidTypeError("string")
 	}
 
 	_, _, err := service.Endpoints["GetSubredditEndpoint"].CallEncode([]interface{}{
@@ -36,8 +36,8 @@ func SubRedditNameValidator(
 	})
 
 	if err != nil {
-		return false
+		return static.NotFoundError("subreddit")
 	}
 
-	return true
+	return nil
 }
@@ -0,0 +1,69 @@
+// Package servicetest runs the actions of the services against recorded HTTP fixtures (see
+// `utils.UseFixtures`), for the tests of the services.
+package servicetest
+
+import (
+	"area-server/classes/shared"
+	"area-server/classes/static"
+	"area-server/db/postgres/models"
+	"area-server/utils"
+	"bufio"
+	"context"
+	"io"
+	"reflect"
+	"testing"
+)
+
+// `Poll` is the expected result of a poll of an action.
+// @property {bool} Triggered - If the action is triggered by the poll.
+// @property {map[string]interface{}} Data - Values expected in the data of the triggered action.
+type Poll struct {
+	Triggered bool
+	Data      map[string]interface{}
+}
+
+// NewRequest returns a request of an applet of the service with a fake token, the logs are discarded
+func NewRequest(service *static.Service, store map[string]interface{}, authStore map[string]interface{}) static.AreaRequest {
+	return static.AreaRequest{
+		Authorization: &models.Authorization{AccessToken: "token"},
+		Service:       service,
+		Logger:        &shared.Logger{Writer: bufio.NewWriter(io.Discard)},
+		Store:         &store,
+		AuthStore:     authStore,
+		Context:       context.Background(),
+	}
+}
+
+// ReplayPolls calls the action once per poll with the fixtures of the file, the test fails if a poll
+// doesn't give the expected result or if a fixture is not used
+func ReplayPolls(t *testing.T, fixtures string, action static.ServiceArea, req static.AreaRequest, polls []Poll) {
+	t.Helper()
+
+	transport, restore, err := utils.UseFixtures(fixtures)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := restore(); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	for i, poll := range polls {
+		resp := action.Method(req)
+		if resp.Error != nil {
+			t.Fatalf("poll %d: %s", i, resp.Error)
+		}
+		if resp.Success != poll.Triggered {
+			t.Fatalf("poll %d: expected triggered=%v", i, poll.Triggered)
+		}
+		for key, expected := range poll.Data {
+			if !reflect.DeepEqual(resp.Data[key], expected) {
+				t.Errorf("poll %d: expected %s=%v, got %v", i, key, expected, resp.Data[key])
+			}
+		}
+	}
+	if transport.Remaining() != 0 {
+		t.Errorf("%d fixtures were not used", transport.Remaining())
+	}
+}
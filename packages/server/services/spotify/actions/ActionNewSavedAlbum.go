@@ -43,7 +43,7 @@ type Albums struct {
 
 // It checks if the user has a new saved album
 func hasANewSavedAlbum(req static.AreaRequest) shared.AreaResponse {
-	encode, _, err := req.Service.Endpoints["FindUserSavedAlbumsEndpoint"].CallEncode([]interface{}{req.Authorization})
+	encode, _, err := req.Service.Endpoints["FindUserSavedAlbumsEndpoint"].CallEncodeContext(req.Context, []interface{}{req.Authorization})
 
 	if err != nil {
 		return shared.AreaResponse{Error: err}
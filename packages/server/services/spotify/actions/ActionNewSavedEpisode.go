@@ -36,7 +36,7 @@ type Episodes struct {
 // It checks if the user has a new saved episode
 func hasANewSavedEpisode(req static.AreaRequest) shared.AreaResponse {
 
-	encode, _, err := req.Service.Endpoints["FindUserSavedEpisodesEndpoint"].CallEncode([]interface{}{req.Authorization})
+	encode, _, err := req.Service.Endpoints["FindUserSavedEpisodesEndpoint"].CallEncodeContext(req.Context, []interface{}{req.Authorization})
 
 	if err != nil {
 		return shared.AreaResponse{Error: err}
@@ -49,7 +49,7 @@ type Shows struct {
 // It checks if the user has a new saved show
 func hasANewSavedShow(req static.AreaRequest) shared.AreaResponse {
 
-	encode, _, err := req.Service.Endpoints["FindUserSavedShowsEndpoint"].CallEncode([]interface{}{req.Authorization})
+	encode, _, err := req.Service.Endpoints["FindUserSavedShowsEndpoint"].CallEncodeContext(req.Context, []interface{}{req.Authorization})
 
 	if err != nil {
 		return shared.AreaResponse{Error: err}
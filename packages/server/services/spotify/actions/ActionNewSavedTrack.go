@@ -10,7 +10,7 @@ import (
 // It checks if the user has a new saved track, and if so, it returns the track's information
 func hasANewSavedTrack(req static.AreaRequest) shared.AreaResponse {
 
-	encode, _, err := req.Service.Endpoints["FindUserSavedTracksEndpoint"].CallEncode([]interface{}{req.Authorization})
+	encode, _, err := req.Service.Endpoints["FindUserSavedTracksEndpoint"].CallEncodeContext(req.Context, []interface{}{req.Authorization})
 
 	if err != nil {
 		return shared.AreaResponse{Error: err}
@@ -54,7 +54,7 @@ type SpotifyPlaylist struct {
 
 // It checks if a new track has been added to a playlist
 func hasNewTrackAddedToPlaylist(req static.AreaRequest) shared.AreaResponse {
-	encode, _, err := req.Service.Endpoints["FindPlaylistByIDEndpoint"].CallEncode([]interface{}{req.Authorization, (*req.Store)["req:playlist:id"]})
+	encode, _, err := req.Service.Endpoints["FindPlaylistByIDEndpoint"].CallEncodeContext(req.Context, []interface{}{req.Authorization, (*req.Store)["req:playlist:id"]})
 
 	if err != nil {
 		return shared.AreaResponse{Error: err}
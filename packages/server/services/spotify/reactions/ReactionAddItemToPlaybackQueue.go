@@ -21,7 +21,7 @@ func addItemToQueue(req static.AreaRequest) shared.AreaResponse {
 		query["device_id"] = (*req.Store)["req:device:id"].(string)
 	}
 
-	_, httpResp, err := req.Service.Endpoints["AddItemToPlaybackQueue"].CallEncode([]interface{}{req.Authorization, query})
+	_, httpResp, err := req.Service.Endpoints["AddItemToPlaybackQueue"].CallEncodeContext(req.Context, []interface{}{req.Authorization, query})
 
 	if httpResp != nil && httpResp.StatusCode == 403 {
 		req.Logger.WriteInfo("(Spotify) No item added to queue, the user is not premium", true)
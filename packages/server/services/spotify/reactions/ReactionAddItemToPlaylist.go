@@ -32,7 +32,7 @@ func addItemToPlaylist(req static.AreaRequest) shared.AreaResponse {
 		return shared.AreaResponse{Error: err}
 	}
 
-	_, _, errr := req.Service.Endpoints["AddItemToPlaylistEndpoint"].CallEncode([]interface{}{req.Authorization, playlistID, string(str)})
+	_, _, errr := req.Service.Endpoints["AddItemToPlaylistEndpoint"].CallEncodeContext(req.Context, []interface{}{req.Authorization, playlistID, string(str)})
 
 	if errr != nil {
 		return shared.AreaResponse{Error: errr}
@@ -63,7 +63,7 @@ func addItemToPlaylistFromSearch(req static.AreaRequest) shared.AreaResponse {
 		typeItem = (*req.Store)["req:search:type"].(string)
 	}
 
-	encode, _, err := req.Service.Endpoints["SearchItemsEndpoint"].CallEncode([]interface{}{
+	encode, _, err := req.Service.Endpoints["SearchItemsEndpoint"].CallEncodeContext(req.Context, []interface{}{
 		req.Authorization,
 		query,
 		typeItem,
@@ -113,7 +113,7 @@ func addItemToPlaylistFromSearch(req static.AreaRequest) shared.AreaResponse {
 		return shared.AreaResponse{Error: err}
 	}
 
-	_, _, errr0 := req.Service.Endpoints["AddItemToPlaylistEndpoint"].CallEncode([]interface{}{
+	_, _, errr0 := req.Service.Endpoints["AddItemToPlaylistEndpoint"].CallEncodeContext(req.Context, []interface{}{
 		req.Authorization,
 		playlistID,
 		string(str),
@@ -14,7 +14,7 @@ func pausePlayback(req static.AreaRequest) shared.AreaResponse {
 		device = (*req.Store)["req:device:id"].(string)
 	} else {
 		// Take the first available device
-		encode, _, err := req.Service.Endpoints["GetUserAvailableDevicesEndpoint"].CallEncode([]interface{}{req.Authorization})
+		encode, _, err := req.Service.Endpoints["GetUserAvailableDevicesEndpoint"].CallEncodeContext(req.Context, []interface{}{req.Authorization})
 		if err != nil {
 			return shared.AreaResponse{Error: err}
 		}
@@ -32,7 +32,7 @@ func pausePlayback(req static.AreaRequest) shared.AreaResponse {
 		device = devices.Devices[0].ID
 	}
 
-	_, httpResp, err := req.Service.Endpoints["PausePlaybackEndpoint"].CallEncode([]interface{}{
+	_, httpResp, err := req.Service.Endpoints["PausePlaybackEndpoint"].CallEncodeContext(req.Context, []interface{}{
 		req.Authorization,
 		device,
 	})
@@ -40,7 +40,7 @@ func resumePlayback(req static.AreaRequest) shared.AreaResponse {
 		device = (*req.Store)["req:device:id"].(string)
 	} else {
 		// Take the first available device
-		encode, _, err := req.Service.Endpoints["GetUserAvailableDevicesEndpoint"].CallEncode([]interface{}{req.Authorization})
+		encode, _, err := req.Service.Endpoints["GetUserAvailableDevicesEndpoint"].CallEncodeContext(req.Context, []interface{}{req.Authorization})
 		if err != nil {
 			return shared.AreaResponse{Error: err}
 		}
@@ -76,7 +76,7 @@ func resumePlayback(req static.AreaRequest) shared.AreaResponse {
 		return shared.AreaResponse{Error: err}
 	}
 
-	_, httpResp, errr := req.Service.Endpoints["ResumePlaybackEndpoint"].CallEncode([]interface{}{
+	_, httpResp, errr := req.Service.Endpoints["ResumePlaybackEndpoint"].CallEncodeContext(req.Context, []interface{}{
 		req.Authorization,
 		device,
 		string(ebody),
@@ -0,0 +1,21 @@
+package spotify
+
+import (
+	"area-server/classes/static"
+	"area-server/services/servicetest"
+	"area-server/services/spotify/actions"
+	"testing"
+)
+
+func TestNewTrackAddedToPlaylistFixtures(t *testing.T) {
+	service := static.Service{Name: "spotify", Endpoints: SpotifyEndpoints()}
+	store := map[string]interface{}{"req:playlist:id": "37i9dQZF1DXcBWIGoYBM5M"}
+	req := servicetest.NewRequest(&service, store, nil)
+
+	// The first poll only saves the snapshot of the playlist
+	servicetest.ReplayPolls(t, "testdata/new_track_added_to_playlist.json", actions.DescriptorForSpotifyActionNewTrackAddedToPlaylist(), req, []servicetest.Poll{
+		{Triggered: false},
+		{Triggered: false},
+		{Triggered: true, Data: map[string]interface{}{"spotify:track:uri": "spotify:track:6rqhFgbbKwnb9MLmUQDhG6"}},
+	})
+}
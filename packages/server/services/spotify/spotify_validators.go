@@ -22,153 +22,153 @@ func SpotifyValidators() static.ServiceValidator {
 // ------------------------- Validators ------------------------------
 
 // It checks if the value is a valid device ID for the user
-func DeviceIDValidator(authorization *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) bool {
+func DeviceIDValidator(authorization *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) *static.ValidationError {
 	if value == nil {
-		return false
+		return static.MissingError()
 	}
 
 	if _, ok := value.(string); !ok {
-		return false
+		return static.InvalidTypeError("string")
 	}
 
 	body, _, err := service.Endpoints["GetUserAvailableDevicesEndpoint"].Call([]interface{}{authorization})
 	if err != nil || body["devices"] == nil {
-		return false
+		return static.NotFoundError("device")
 	}
 
 	for _, device := range body["devices"].([]interface{}) {
 		if device.(map[string]interface{})["id"] == value {
-			return true
+			return nil
 		}
 	}
-	return false
+	return static.NotFoundError("device")
 }
 
 // It checks if the value is a string, and if it is, it checks if it's a valid Spotify URI
-func UriValidator(authorization *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) bool {
+func UriValidator(authorization *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) *static.ValidationError {
 	if value == nil {
-		return false
+		return static.MissingError()
 	}
 
 	if _, ok := value.(string); !ok {
-		return false
+		return static.InvalidTypeError("string")
 	}
 
 	uri := strings.Split(value.(string), ":")
 
 	if len(uri) != 3 {
-		return false
+		return static.InvalidFormatError("spotify:<type>:<id>")
 	}
 
 	if uri[0] == "{{spotify" && uri[2] == "uri}}" {
-		return true
+		return nil
 	}
 
 	if uri[0] != "spotify" {
-		return false
+		return static.InvalidFormatError("spotify:<type>:<id>")
 	}
 
 	endpoint := "Find" + strings.Title(uri[1]) + "ByIDEndpoint"
 	if service.Endpoints[endpoint] == nil {
-		return false
+		return static.NotAllowedError("album", "artist", "playlist", "track", "show", "episode", "audiobook")
 	}
 
 	_, _, err := service.Endpoints[endpoint].CallEncode([]interface{}{authorization, uri[2]})
 	if err != nil {
-		return false
+		return static.NotFoundError(uri[1])
 	}
-	return true
+	return nil
 }
 
-// `PlaylistIDValidator` takes an authorization, a service, a value, and a store, and returns a boolean
-func PlaylistIDValidator(authorization *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) bool {
+// `PlaylistIDValidator` checks if the value is the ID of an existing playlist
+func PlaylistIDValidator(authorization *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) *static.ValidationError {
 	if value == nil {
-		return false
+		return static.MissingError()
 	}
 
 	if _, ok := value.(string); !ok {
-		return false
+		return static.InvalidTypeError("string")
 	}
 
 	if value == "{{spotify:playlist:id}}" {
-		return true
+		return nil
 	}
 
 	_, _, err := service.Endpoints["FindPlaylistByIDEndpoint"].CallEncode([]interface{}{authorization, value})
 	if err != nil {
-		return false
+		return static.NotFoundError("playlist")
 	}
-	return true
+	return nil
 }
 
 // It checks that the value is a string and that it is a valid URI
-func UrisValidator(authorization *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) bool {
+func UrisValidator(authorization *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) *static.ValidationError {
 	if value == nil {
-		return false
+		return static.MissingError()
 	}
 
 	if _, ok := value.(string); !ok {
-		return false
+		return static.InvalidTypeError("string")
 	}
 
 	for _, uri := range value.([]interface{}) {
-		if !UriValidator(authorization, service, uri, store) {
-			return false
+		if err := UriValidator(authorization, service, uri, store); err != nil {
+			return err
 		}
 	}
-	return true
+	return nil
 }
 
 // If the value is nil, or not a string, or the string is not the special value, then we call the
 // FindPlaylistByIDEndpoint to get the playlist. If the playlist is collaborative, or the owner of the
 // playlist is the user, then the value is valid
-func WriteablePlaylistIDValidator(authorization *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) bool {
+func WriteablePlaylistIDValidator(authorization *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) *static.ValidationError {
 	if value == nil {
-		return false
+		return static.MissingError()
 	}
 
 	if _, ok := value.(string); !ok {
-		return false
+		return static.InvalidTypeError("string")
 	}
 
 	if value == "{{spotify:playlist:id}}" {
-		return true
+		return nil
 	}
 
 	body, _, err := service.Endpoints["FindPlaylistByIDEndpoint"].Call([]interface{}{authorization, value})
 	if err != nil {
-		return false
+		return static.NotFoundError("playlist")
 	}
 
 	if body["collaborative"].(bool) {
-		return true
+		return nil
 	}
 
 	userBody, _, errP := service.Endpoints["GetUserProfileEndpoint"].Call([]interface{}{authorization})
 	if errP != nil {
-		return false
+		return static.NotFoundError("user")
 	}
 
 	if body["owner"].(map[string]interface{})["id"] != userBody["id"] {
-		return false
+		return static.NewValidationError(static.ValidationNotAllowed, "The playlist is not writable by the user", "collaborative or owned playlist")
 	}
-	return true
+	return nil
 }
 
 // If the value is a string, and the string is one of the following: album, artist, playlist, track,
 // show, episode, audiobook, then return true
-func SearchTypeValidator(authorization *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) bool {
+func SearchTypeValidator(authorization *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) *static.ValidationError {
 	if value == nil {
-		return false
+		return static.MissingError()
 	}
 
 	if _, ok := value.(string); !ok {
-		return false
+		return static.InvalidTypeError("string")
 	}
 
 	switch value.(string) {
 	case "album", "artist", "playlist", "track", "show", "episode", "audiobook":
-		return true
+		return nil
 	}
-	return false
+	return static.NotAllowedError("album", "artist", "playlist", "track", "show", "episode", "audiobook")
 }
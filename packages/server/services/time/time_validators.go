@@ -28,48 +28,46 @@ func TimeValidators() static.ServiceValidator {
 }
 
 // If the value is a string, then it must match the regex pattern for a UTC timezone.
-func ZoneValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) bool {
+func ZoneValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) *static.ValidationError {
 	if value == nil {
-		return false
+		return static.MissingError()
 	}
 
 	// Must mast +00:00 format (regex)
 	if _, ok := value.(string); ok {
 		// Regex
 		match, err := regexp.MatchString("UTC(\\+([1-9]){1}([0-4]){0,1}|\\-([1-9]){1}([0-2]){0,1})", value.(string))
-		if err != nil {
-			return false
+		if err != nil || !match {
+			return static.InvalidFormatError("UTC+1 ... UTC+14, UTC-1 ... UTC-12")
 		}
-		if !match {
-			return false
-		}
-		return true
+		return nil
 	}
 
-	return false
+	return static.InvalidTypeError("string")
 }
 
 // If the value is a string, it must be one of the following values: second, minute, hour, day, week,
 // month, year.
-func TimeUnitValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) bool {
+func TimeUnitValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) *static.ValidationError {
 	if value == nil {
-		return false
+		return static.MissingError()
 	}
 
 	// Must be one of these values
 	if _, ok := value.(string); ok {
 		if value == "second" || value == "minute" || value == "hour" || value == "day" || value == "week" || value == "month" || value == "year" {
-			return true
+			return nil
 		}
+		return static.NotAllowedError("second", "minute", "hour", "day", "week", "month", "year")
 	}
 
-	return false
+	return static.InvalidTypeError("string")
 }
 
 // It checks if the value is a string, and if it is, it checks if it's a valid date
-func DateValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) bool {
+func DateValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) *static.ValidationError {
 	if value == nil {
-		return false
+		return static.MissingError()
 	}
 
 	// Must be one of these values
@@ -78,34 +76,34 @@ func DateValidator(auth *models.Authorization, service *static.Service, value in
 		if store["req:time:zone"] != nil {
 			llocation, err := commonr.TransformTimeZoneIntoFixedZone(store["req:time:zone"].(string))
 			if err != nil {
-				return false
+				return static.InvalidFormatError("UTC+1 ... UTC+14, UTC-1 ... UTC-12")
 			}
 			ctime, err = time.ParseInLocation("2006-01-02T15:04:05", value.(string), llocation)
 
 			if err != nil {
-				return false
+				return static.InvalidFormatError("YYYY-MM-DDTHH:MM:SS")
 			}
 		} else {
 			var err error
 			ctime, err = time.Parse("2006-01-02T15:04:05", value.(string))
 			if err != nil {
-				return false
+				return static.InvalidFormatError("YYYY-MM-DDTHH:MM:SS")
 			}
 		}
 
 		if ctime.IsZero() {
-			return false
+			return static.InvalidFormatError("YYYY-MM-DDTHH:MM:SS")
 		}
-		return true
+		return nil
 	}
 
-	return false
+	return static.InvalidTypeError("string")
 }
 
 // `AfterDateValidator` checks if the value is a string and if it is a valid date in the future
-func AfterDateValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) bool {
+func AfterDateValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) *static.ValidationError {
 	if value == nil {
-		return false
+		return static.MissingError()
 	}
 
 	// Must be one of these values
@@ -114,28 +112,31 @@ func AfterDateValidator(auth *models.Authorization, service *static.Service, val
 		if store["req:time:zone"] != nil {
 			llocation, err := commonr.TransformTimeZoneIntoFixedZone(store["req:time:zone"].(string))
 			if err != nil {
-				return false
+				return static.InvalidFormatError("UTC+1 ... UTC+14, UTC-1 ... UTC-12")
 			}
 			ctime, err = time.ParseInLocation("2006-01-02T15:04:05", value.(string), llocation)
 
 			if err != nil {
-				return false
+				return static.InvalidFormatError("YYYY-MM-DDTHH:MM:SS")
 			}
 		} else {
 			var err error
 			ctime, err = time.Parse("2006-01-02T15:04:05", value.(string))
 			if err != nil {
-				return false
+				return static.InvalidFormatError("YYYY-MM-DDTHH:MM:SS")
 			}
 		}
 
-		if ctime.IsZero() || ctime.UTC().Before(time.Now().UTC()) {
-			return false
+		if ctime.IsZero() {
+			return static.InvalidFormatError("YYYY-MM-DDTHH:MM:SS")
+		}
+		if ctime.UTC().Before(time.Now().UTC()) {
+			return static.InPastError()
 		}
-		return true
+		return nil
 	}
 
-	return false
+	return static.InvalidTypeError("string")
 }
 
 // "If the value is not nil, parse it as an integer and return true if it's greater than or equal to
@@ -144,22 +145,22 @@ func AfterDateValidator(auth *models.Authorization, service *static.Service, val
 // The first thing we do is check if the value is nil. If it is, we return false. If it's not, we parse
 // it as an integer. If we can't parse it, we return false. If we can, we check if it's greater than or
 // equal to the current year. If it is, we return true. If it's not, we return false
-func YearValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) bool {
+func YearValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) *static.ValidationError {
 	if value == nil {
-		return false
+		return static.MissingError()
 	}
 
 	// Parse year
 	year, err := strconv.Atoi(value.(string))
 	if err != nil {
-		return false
+		return static.InvalidFormatError("integer")
 	}
 
 	if year < time.Now().Year() {
-		return false
+		return static.InPastError()
 	}
 
-	return true
+	return nil
 }
 
 // "If the value is not nil, and it's a valid month, return true."
@@ -168,35 +169,35 @@ func YearValidator(auth *models.Authorization, service *static.Service, value in
 // the service that is being accessed. The `value` parameter is the value of the parameter being
 // validated. The `store` parameter is a map of values that can be used to store state between
 // validators
-func MonthValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) bool {
+func MonthValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) *static.ValidationError {
 	if value == nil {
-		return false
+		return static.MissingError()
 	}
 
 	// Parse month
 	switch value.(string) {
 	case "January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December":
-		return true
+		return nil
 	}
 
-	return false
+	return static.NotAllowedError("January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December")
 }
 
 // "If the value is not nil, and it's a valid day of the week, return true."
 //
 // The first thing we do is check if the value is nil. If it is, we return false
-func DayValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) bool {
+func DayValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) *static.ValidationError {
 	if value == nil {
-		return false
+		return static.MissingError()
 	}
 
 	// Parse day
 	switch value.(string) {
 	case "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday", "Sunday":
-		return true
+		return nil
 	}
 
-	return false
+	return static.NotAllowedError("Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday", "Sunday")
 }
 
 // "If the value is not nil, parse it as an integer and return true if it's between 0 and 23, otherwise
@@ -208,22 +209,22 @@ func DayValidator(auth *models.Authorization, service *static.Service, value int
 // * `service`: The service object.
 // * `value`: The value to validate.
 // * `store`: A map of values that can be used to store data for later use
-func HourValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) bool {
+func HourValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) *static.ValidationError {
 	if value == nil {
-		return false
+		return static.MissingError()
 	}
 
 	// Parse hour
 	hour, err := strconv.Atoi(value.(string))
 	if err != nil {
-		return false
+		return static.InvalidFormatError("integer")
 	}
 
 	if hour < 0 || hour > 23 {
-		return false
+		return static.OutOfRangeError(0, 23)
 	}
 
-	return true
+	return nil
 }
 
 // "If the value is not nil, parse it as an integer and return true if it's between 0 and 59, otherwise
@@ -232,20 +233,20 @@ func HourValidator(auth *models.Authorization, service *static.Service, value in
 // The `auth` parameter is the authorization being validated. The `service` parameter is the service
 // being accessed. The `value` parameter is the value of the parameter being validated. The `store`
 // parameter is a map that can be used to store data that can be used by other validators
-func MinuteValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) bool {
+func MinuteValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) *static.ValidationError {
 	if value == nil {
-		return false
+		return static.MissingError()
 	}
 
 	// Parse minute
 	minute, err := strconv.Atoi(value.(string))
 	if err != nil {
-		return false
+		return static.InvalidFormatError("integer")
 	}
 
 	if minute < 0 || minute > 59 {
-		return false
+		return static.OutOfRangeError(0, 59)
 	}
 
-	return true
+	return nil
 }
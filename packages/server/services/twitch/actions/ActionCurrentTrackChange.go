@@ -12,7 +12,7 @@ import (
 func hasCurrentTrackChange(req static.AreaRequest) shared.AreaResponse {
 
 	if (*req.Store)["ctx:streamer:id"] == nil && ((*req.Store)["req:streamer:login"] != nil && (*req.Store)["req:streamer:login"] != "") {
-		encode, _, err := req.Service.Endpoints["GetUserByLoginEndpoint"].CallEncode([]interface{}{req.Authorization, (*req.Store)["req:streamer:login"]})
+		encode, _, err := req.Service.Endpoints["GetUserByLoginEndpoint"].CallEncodeContext(req.Context, []interface{}{req.Authorization, (*req.Store)["req:streamer:login"]})
 		if err != nil {
 			return shared.AreaResponse{Error: err}
 		}
@@ -28,7 +28,7 @@ func hasCurrentTrackChange(req static.AreaRequest) shared.AreaResponse {
 		(*req.Store)["ctx:streamer:id"] = user.Data[0].ID
 	}
 
-	encode, httpResp, err := req.Service.Endpoints["GetSoundTrackCurrentTrackEndpoint"].CallEncode([]interface{}{
+	encode, httpResp, err := req.Service.Endpoints["GetSoundTrackCurrentTrackEndpoint"].CallEncodeContext(req.Context, []interface{}{
 		req.Authorization,
 		(*req.Store)["ctx:streamer:id"],
 	})
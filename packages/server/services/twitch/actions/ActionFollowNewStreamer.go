@@ -17,7 +17,7 @@ func isFollowingNewStreamer(req static.AreaRequest) shared.AreaResponse {
 	query["from_id"] = userID.(string)
 
 	if (*req.Store)["ctx:streamer:id"] == nil && ((*req.Store)["req:streamer:login"] != nil && (*req.Store)["req:streamer:login"] != "") {
-		encode, _, err := req.Service.Endpoints["GetUserByLoginEndpoint"].CallEncode([]interface{}{req.Authorization, (*req.Store)["req:streamer:login"]})
+		encode, _, err := req.Service.Endpoints["GetUserByLoginEndpoint"].CallEncodeContext(req.Context, []interface{}{req.Authorization, (*req.Store)["req:streamer:login"]})
 		if err != nil {
 			return shared.AreaResponse{Error: err}
 		}
@@ -37,7 +37,7 @@ func isFollowingNewStreamer(req static.AreaRequest) shared.AreaResponse {
 		query["to_id"] = (*req.Store)["ctx:streamer:id"].(string)
 	}
 
-	body, _, err := req.Service.Endpoints["GetUserFollowsEndpoint"].CallEncode([]interface{}{req.Authorization, query})
+	body, _, err := req.Service.Endpoints["GetUserFollowsEndpoint"].CallEncodeContext(req.Context, []interface{}{req.Authorization, query})
 
 	if err != nil {
 		return shared.AreaResponse{Error: err}
@@ -24,7 +24,7 @@ func hasANewClipBeenCaptured(req static.AreaRequest) shared.AreaResponse {
 	if (*req.Store)["ctx:entity:id"] == nil {
 		switch (*req.Store)["req:clip:entity:type"] {
 		case "user":
-			encode, _, err := req.Service.Endpoints["GetUserByLoginEndpoint"].CallEncode([]interface{}{req.Authorization, (*req.Store)["req:clip:entity:value"]})
+			encode, _, err := req.Service.Endpoints["GetUserByLoginEndpoint"].CallEncodeContext(req.Context, []interface{}{req.Authorization, (*req.Store)["req:clip:entity:value"]})
 			if err != nil {
 				return shared.AreaResponse{Error: err}
 			}
@@ -34,7 +34,7 @@ func hasANewClipBeenCaptured(req static.AreaRequest) shared.AreaResponse {
 			}
 			(*req.Store)["ctx:entity:id"] = streamer.Data[0].ID
 		case "game":
-			encode, _, err := req.Service.Endpoints["GetGameEndpoint"].CallEncode([]interface{}{req.Authorization, (*req.Store)["req:clip:entity:value"]})
+			encode, _, err := req.Service.Endpoints["GetGameEndpoint"].CallEncodeContext(req.Context, []interface{}{req.Authorization, (*req.Store)["req:clip:entity:value"]})
 			if err != nil {
 				return shared.AreaResponse{Error: err}
 			}
@@ -53,7 +53,7 @@ func hasANewClipBeenCaptured(req static.AreaRequest) shared.AreaResponse {
 		query["game_id"] = (*req.Store)["ctx:entity:id"].(string)
 	}
 
-	encode, _, err := req.Service.Endpoints["GetClipsEndpoint"].CallEncode([]interface{}{req.Authorization, query})
+	encode, _, err := req.Service.Endpoints["GetClipsEndpoint"].CallEncodeContext(req.Context, []interface{}{req.Authorization, query})
 
 	if err != nil {
 		return shared.AreaResponse{Error: err}
@@ -30,7 +30,7 @@ func hasANewStreamStarted(req static.AreaRequest) shared.AreaResponse {
 	query["first"] = "1"
 
 	if (*req.Store)["ctx:game:id"] == nil && (*req.Store)["req:game:name"] != nil {
-		encode, _, err := req.Service.Endpoints["GetGameEndpoint"].CallEncode([]interface{}{req.Authorization, (*req.Store)["req:game:name"]})
+		encode, _, err := req.Service.Endpoints["GetGameEndpoint"].CallEncodeContext(req.Context, []interface{}{req.Authorization, (*req.Store)["req:game:name"]})
 		if err != nil {
 			return shared.AreaResponse{Error: err}
 		}
@@ -45,7 +45,7 @@ func hasANewStreamStarted(req static.AreaRequest) shared.AreaResponse {
 		query["game_id"] = (*req.Store)["ctx:game:id"].(string)
 	}
 
-	encode, _, err := req.Service.Endpoints["NewStreamStartedByUserEndpoint"].CallEncode([]interface{}{req.Authorization, query})
+	encode, _, err := req.Service.Endpoints["NewStreamStartedByUserEndpoint"].CallEncodeContext(req.Context, []interface{}{req.Authorization, query})
 
 	if err != nil {
 		return shared.AreaResponse{Error: err}
@@ -13,7 +13,7 @@ func createClip(req static.AreaRequest) shared.AreaResponse {
 	if (*req.Store)["ctx:broadcaster:id"] == nil {
 		switch (*req.Store)["req:streamer:entity:type"] {
 		case "login":
-			encode, _, err := req.Service.Endpoints["GetUserByLoginEndpoint"].CallEncode([]interface{}{req.Authorization, (*req.Store)["req:streamer:entity:value"]})
+			encode, _, err := req.Service.Endpoints["GetUserByLoginEndpoint"].CallEncodeContext(req.Context, []interface{}{req.Authorization, (*req.Store)["req:streamer:entity:value"]})
 			if err != nil {
 				return shared.AreaResponse{Error: err}
 			}
@@ -34,7 +34,7 @@ func createClip(req static.AreaRequest) shared.AreaResponse {
 		}
 	}
 
-	_, httpResp, err := req.Service.Endpoints["CreateClipEndpoint"].CallEncode([]interface{}{req.Authorization, (*req.Store)["ctx:broadcaster:id"].(string)})
+	_, httpResp, err := req.Service.Endpoints["CreateClipEndpoint"].CallEncodeContext(req.Context, []interface{}{req.Authorization, (*req.Store)["ctx:broadcaster:id"].(string)})
 
 	if httpResp != nil && httpResp.StatusCode == 404 {
 		req.Logger.WriteInfo("(Twitch) No clip created, the streamer is not living", true)
@@ -46,7 +46,7 @@ type PollError struct {
 func createPoll(req static.AreaRequest) shared.AreaResponse {
 	userID := req.AuthStore["user_id"].(string)
 	if (*req.Store)["req:streamer:login"] != nil {
-		streamer, _, err := req.Service.Endpoints["GetUserByLoginEndpoint"].Call([]interface{}{req.Authorization, (*req.Store)["req:streamer:login"]})
+		streamer, _, err := req.Service.Endpoints["GetUserByLoginEndpoint"].CallContext(req.Context, []interface{}{req.Authorization, (*req.Store)["req:streamer:login"]})
 		if err != nil {
 			return shared.AreaResponse{Error: err}
 		}
@@ -85,7 +85,7 @@ func createPoll(req static.AreaRequest) shared.AreaResponse {
 		return shared.AreaResponse{Error: err}
 	}
 
-	_, httpResp, errr := req.Service.Endpoints["CreatePollEndpoint"].CallEncode([]interface{}{
+	_, httpResp, errr := req.Service.Endpoints["CreatePollEndpoint"].CallEncodeContext(req.Context, []interface{}{
 		req.Authorization,
 		string(ebody),
 	})
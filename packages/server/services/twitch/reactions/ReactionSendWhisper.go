@@ -16,7 +16,7 @@ func sendWhisper(req static.AreaRequest) shared.AreaResponse {
 	message := utils.GenerateFinalComponent((*req.Store)["req:message:content"].(string), req.ExternalData, []string{})
 
 	if (*req.Store)["ctx:receiver:id"] == nil {
-		encode, _, err := req.Service.Endpoints["GetUserByLoginEndpoint"].CallEncode([]interface{}{req.Authorization, (*req.Store)["req:user:login"]})
+		encode, _, err := req.Service.Endpoints["GetUserByLoginEndpoint"].CallEncodeContext(req.Context, []interface{}{req.Authorization, (*req.Store)["req:user:login"]})
 		if err != nil {
 			return shared.AreaResponse{Error: err}
 		}
@@ -32,7 +32,7 @@ func sendWhisper(req static.AreaRequest) shared.AreaResponse {
 		(*req.Store)["ctx:receiver:id"] = streamer.Data[0].ID
 	}
 
-	_, httpResp, err := req.Service.Endpoints["SendWhisperEndpoint"].CallEncode([]interface{}{
+	_, httpResp, err := req.Service.Endpoints["SendWhisperEndpoint"].CallEncodeContext(req.Context, []interface{}{
 		req.Authorization,
 		userID,
 		(*req.Store)["ctx:receiver:id"].(string),
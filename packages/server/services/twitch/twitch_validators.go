@@ -29,56 +29,52 @@ func TwitchValidators() static.ServiceValidator {
 // ----------------------- Validators -----------------------
 
 // `UserLoginValidator` is a function that takes in an `Authorization` object, a `Service` object, a
-// value, and a map of values. It returns a boolean
-func UserLoginValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) bool {
+// value, and a map of values. It returns an error if the user does not exist
+func UserLoginValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) *static.ValidationError {
 	if value == nil {
-		return false
+		return static.MissingError()
 	}
 
 	if _, ok := value.(string); !ok {
-		return false
+		return static.InvalidTypeError("string")
 	}
 
 	if value == "{{twitch:user:login}}" {
-		return true
+		return nil
 	}
 
 	encode, _, err := service.Endpoints["GetUserByLoginEndpoint"].CallEncode([]interface{}{auth, value.(string)})
 	if err != nil {
-		return false
+		return static.NotFoundError("user")
 	}
 
 	streamer := common.TwitchUsers{}
-	if err := json.Unmarshal(encode, &streamer); err != nil {
-		return false
+	if err := json.Unmarshal(encode, &streamer); err != nil || len(streamer.Data) == 0 {
+		return static.NotFoundError("user")
 	}
 
-	if len(streamer.Data) == 0 {
-		return false
-	}
-
-	return true
+	return nil
 }
 
 // "If the value is a string, and it's either 'game' or 'user', then it's valid."
 //
 // The first thing we do is check if the value is nil. If it is, then we return false. This is because
 // we don't want to allow nil values
-func ClipEntityTypeValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) bool {
+func ClipEntityTypeValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) *static.ValidationError {
 	if value == nil {
-		return false
+		return static.MissingError()
 	}
 
 	if _, ok := value.(string); !ok {
-		return false
+		return static.InvalidTypeError("string")
 	}
 
 	switch value.(string) {
 	case "game", "user":
-		return true
+		return nil
 	}
 
-	return false
+	return static.NotAllowedError("game", "user")
 }
 
 // "If the value is a string, and the entity type is either 'game' or 'user', then validate the value
@@ -86,184 +82,176 @@ func ClipEntityTypeValidator(auth *models.Authorization, service *static.Service
 //
 // The first thing we do is check if the value is nil. If it is, we return false. This is because we
 // don't want to validate a nil value
-func ClipEntityValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) bool {
+func ClipEntityValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) *static.ValidationError {
 	if value == nil {
-		return false
+		return static.MissingError()
 	}
 
 	if _, ok := value.(string); !ok {
-		return false
+		return static.InvalidTypeError("string")
 	}
 
 	if store["req:entity:type"] == nil {
-		return false
+		return static.MissingDependencyError("req:entity:type")
 	}
 
 	switch store["req:entity:type"].(string) {
 	case "game":
-		if GameNameValidator(auth, service, value, store) {
-			return true
-		}
+		return GameNameValidator(auth, service, value, store)
 	case "user":
-		if UserLoginValidator(auth, service, value, store) {
-			return true
-		}
+		return UserLoginValidator(auth, service, value, store)
 	}
 
-	return false
+	return static.NotAllowedError("game", "user")
 }
 
 // It checks if the value is a string, and if it is, it checks if the value is a valid game name
-func GameNameValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) bool {
+func GameNameValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) *static.ValidationError {
 	if value == nil {
-		return false
+		return static.MissingError()
 	}
 
 	if _, ok := value.(string); !ok {
-		return false
+		return static.InvalidTypeError("string")
 	}
 
 	if value.(string) == "{{twitch:game:name}}" {
-		return true
+		return nil
 	}
 
 	encode, _, err := service.Endpoints["GetGameEndpoint"].CallEncode([]interface{}{auth, value.(string)})
 	if err != nil {
-		return false
+		return static.NotFoundError("game")
 	}
 
 	game := common.TwitchGames{}
-	if err := json.Unmarshal(encode, &game); err != nil {
-		return false
+	if err := json.Unmarshal(encode, &game); err != nil || len(game.Data) == 0 {
+		return static.NotFoundError("game")
 	}
 
-	if len(game.Data) == 0 {
-		return false
-	}
-
-	return true
+	return nil
 }
 
 // If the value is a string and it's less than 60 characters, then it's valid
-func PollTitleValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) bool {
+func PollTitleValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) *static.ValidationError {
 	if value == nil {
-		return false
+		return static.MissingError()
 	}
 
 	if _, ok := value.(string); !ok {
-		return false
+		return static.InvalidTypeError("string")
 	}
 
 	if len(value.(string)) > 60 {
-		return false
+		return static.NewValidationError(static.ValidationOutOfRange, "The title is too long", "at most 60 characters")
 	}
 
-	return true
+	return nil
 }
 
 // It checks that the value is a string, that it contains at least two and at most ten comma-separated
 // values, and that each value is at most 25 characters long
-func PollChoicesValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) bool {
+func PollChoicesValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) *static.ValidationError {
 	if value == nil {
-		return false
+		return static.MissingError()
 	}
 
 	if _, ok := value.(string); !ok {
-		return false
+		return static.InvalidTypeError("string")
 	}
 
 	tab := strings.Split(value.(string), ",")
 
 	if len(tab) < 2 || len(tab) > 10 {
-		return false
+		return static.NewValidationError(static.ValidationOutOfRange, "The number of choices is invalid", "between 2 and 10 comma-separated choices")
 	}
 
 	for _, choice := range tab {
 		if len(choice) > 25 {
-			return false
+			return static.NewValidationError(static.ValidationOutOfRange, "A choice is too long", "at most 25 characters per choice")
 		}
 	}
 
-	return true
+	return nil
 }
 
 // If the value is a string, convert it to an integer and make sure it's between 15 and 1800
-func PollDurationValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) bool {
+func PollDurationValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) *static.ValidationError {
 	if value == nil {
-		return false
+		return static.MissingError()
 	}
 
 	if _, ok := value.(string); !ok {
-		return false
+		return static.InvalidTypeError("string")
 	}
 
 	duration, err := strconv.Atoi(value.(string))
 	if err != nil {
-		return false
+		return static.InvalidFormatError("integer")
 	}
 
 	if duration < 15 || duration > 1800 {
-		return false
+		return static.OutOfRangeError(15, 1800)
 	}
 
-	return true
+	return nil
 }
 
 // "If the value is a string, convert it to an integer and make sure it's between 1 and 1,000,000."
 //
 // The first thing we do is check if the value is nil. If it is, we return false
-func PollPointsPerVoteValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) bool {
+func PollPointsPerVoteValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) *static.ValidationError {
 	if value == nil {
-		return false
+		return static.MissingError()
 	}
 
 	if _, ok := value.(string); !ok {
-		return false
+		return static.InvalidTypeError("string")
 	}
 
 	points, err := strconv.Atoi(value.(string))
 	if err != nil {
-		return false
+		return static.InvalidFormatError("integer")
 	}
 
 	if points < 1 || points > 1000000 {
-		return false
+		return static.OutOfRangeError(1, 1000000)
 	}
 
-	return true
+	return nil
 }
 
 // `StreamerEntityTypeValidator` is a function that takes an authorization, a service, a value, and a
-// store, and returns a boolean
-func StreamerEntityTypeValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) bool {
+// store, and returns an error if the value is not "id" or "login"
+func StreamerEntityTypeValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) *static.ValidationError {
 	if value == nil {
-		return false
+		return static.MissingError()
 	}
 
 	if _, ok := value.(string); !ok {
-		return false
+		return static.InvalidTypeError("string")
 	}
 
 	switch value.(string) {
 	case "id", "login":
-		return true
+		return nil
 	}
 
-	return false
+	return static.NotAllowedError("id", "login")
 }
 
 // It checks if the value is a string, and if it is, it checks if the value is a valid streamer entity
-func StreamerEntityValueValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) bool {
+func StreamerEntityValueValidator(auth *models.Authorization, service *static.Service, value interface{}, store map[string]interface{}) *static.ValidationError {
 	if value == nil {
-		return false
+		return static.MissingError()
 	}
 
 	if _, ok := value.(string); !ok {
-		return false
+		return static.InvalidTypeError("string")
 	}
 
 	if store["req:streamer:entity:type"] == nil {
-		return false
+		return static.MissingDependencyError("req:streamer:entity:type")
 	}
 
 	var encode []byte
@@ -272,31 +260,27 @@ func StreamerEntityValueValidator(auth *models.Authorization, service *static.Se
 	case "id":
 
 		if value.(string) == "{{twitch:user:id}}" || value.(string) == "{{twitch:creator:id}}" || value.(string) == "{{twitch:broadcaster:id}}" {
-			return true
+			return nil
 		}
 
 		encode, _, err = service.Endpoints["GetUserByIdEndpoint"].CallEncode([]interface{}{auth, value.(string)})
 	case "login":
 
 		if value.(string) == "{{twitch:user:login}}" {
-			return true
+			return nil
 		}
 
 		encode, _, err = service.Endpoints["GetUserByLoginEndpoint"].CallEncode([]interface{}{auth, value.(string)})
 	}
 
 	if err != nil {
-		return false
+		return static.NotFoundError("streamer")
 	}
 
 	streamer := common.TwitchUsers{}
-	if err := json.Unmarshal(encode, &streamer); err != nil {
-		return false
-	}
-
-	if len(streamer.Data) == 0 {
-		return false
+	if err := json.Unmarshal(encode, &streamer); err != nil || len(streamer.Data) == 0 {
+		return static.NotFoundError("streamer")
 	}
 
-	return true
+	return nil
 }
@@ -35,11 +35,13 @@ func triggerWebhook(req static.AreaRequest) shared.AreaResponse {
 		Method:  method,
 		Headers: headers,
 		Body:    string(body),
+		Context: req.Context,
 	})
 
 	if errr != nil {
 		return shared.AreaResponse{Error: errr}
 	}
+	resp.Body.Close()
 
 	if (*req.Store)["req:webhook:response:status"] != nil {
 		status, err := strconv.Atoi((*req.Store)["req:webhook:response:status"].(string))
@@ -4,6 +4,7 @@ import (
 	"area-server/classes/static"
 	"area-server/services/webhook/actions"
 	"area-server/services/webhook/reactions"
+	"time"
 )
 
 // It returns a static.Service object that describes the webhook service
@@ -12,6 +13,7 @@ func Descriptor() static.Service {
 		Name:        "webhook",
 		Description: "Webhook service",
 		RateLimit:   0,
+		Timeout:     15 * time.Second, // The URL is provided by the user
 		More: &static.More{
 			Avatar: true,
 			Color:  "#7289DA",
@@ -5,6 +5,7 @@ import (
 	"area-server/db/postgres"
 	"area-server/db/postgres/models"
 	"area-server/store/webhooks"
+	"area-server/utils"
 	"fmt"
 
 	"github.com/gofiber/fiber/v2"
@@ -19,6 +20,7 @@ func WebhookRoute() []static.ServiceRoute {
 			Handler:  webhookHandler,
 			Method:   "POST",
 			NeedAuth: true,
+			Scope:    utils.ScopeWebhooksTrigger,
 		},
 		{
 			Endpoint: "/:email/:name",
@@ -32,12 +32,12 @@ func newCommentByYou(req static.AreaRequest) shared.AreaResponse {
 
 	switch (*req.Store)["req:entity:commentable:type"] {
 	case "video", "channel":
-		encode, _, err = req.Service.Endpoints["GetAllCommentsEndpoint"].CallEncode([]interface{}{
+		encode, _, err = req.Service.Endpoints["GetAllCommentsEndpoint"].CallEncodeContext(req.Context, []interface{}{
 			req.Authorization,
 			query,
 		})
 	case "comment":
-		encode, _, err = req.Service.Endpoints["GetAllRepliesEndpoint"].CallEncode([]interface{}{
+		encode, _, err = req.Service.Endpoints["GetAllRepliesEndpoint"].CallEncodeContext(req.Context, []interface{}{
 			req.Authorization,
 			query,
 		})
@@ -16,7 +16,7 @@ func hasLikedANewVideo(req static.AreaRequest) shared.AreaResponse {
 	query["maxResults"] = "1"
 	query["myRating"] = "like"
 
-	encode, _, err := req.Service.Endpoints["GetAllVideosEndpoint"].CallEncode([]interface{}{req.Authorization, query})
+	encode, _, err := req.Service.Endpoints["GetAllVideosEndpoint"].CallEncodeContext(req.Context, []interface{}{req.Authorization, query})
 	if err != nil {
 		return shared.AreaResponse{Error: err}
 	}
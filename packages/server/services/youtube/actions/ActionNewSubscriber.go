@@ -16,7 +16,7 @@ func hasNewSubscriber(req static.AreaRequest) shared.AreaResponse {
 	query["maxResults"] = "1"
 	query["myRecentSubscribers"] = "true"
 
-	encode, _, err := req.Service.Endpoints["GetAllSubscriptionsEndpoint"].CallEncode([]interface{}{req.Authorization, query})
+	encode, _, err := req.Service.Endpoints["GetAllSubscriptionsEndpoint"].CallEncodeContext(req.Context, []interface{}{req.Authorization, query})
 	if err != nil {
 		return shared.AreaResponse{Error: err}
 	}
@@ -20,7 +20,7 @@ func isSubscribingToNewChannel(req static.AreaRequest) shared.AreaResponse {
 		query["forChannelId"] = (*req.Store)["req:creator:id"].(string)
 	}
 
-	encode, _, err := req.Service.Endpoints["GetAllSubscriptionsEndpoint"].CallEncode([]interface{}{req.Authorization, query})
+	encode, _, err := req.Service.Endpoints["GetAllSubscriptionsEndpoint"].CallEncodeContext(req.Context, []interface{}{req.Authorization, query})
 	if err != nil {
 		return shared.AreaResponse{Error: err}
 	}
@@ -11,7 +11,7 @@ import (
 // It checks if the latest video in the playlist is the same as the one stored in the store, and if
 // not, it returns the new video's information
 func newVideoAddedToPlaylist(req static.AreaRequest) shared.AreaResponse {
-	encode, _, err := req.Service.Endpoints["GetPlaylistItemsEndpoint"].CallEncode([]interface{}{
+	encode, _, err := req.Service.Endpoints["GetPlaylistItemsEndpoint"].CallEncodeContext(req.Context, []interface{}{
 		req.Authorization,
 		(*req.Store)["req:playlist:id"].(string),
 	})
@@ -96,12 +96,12 @@ func addComment(req static.AreaRequest) shared.AreaResponse {
 	var errr error
 	switch (*req.Store)["req:entity:commentable:type"] {
 	case "video", "channel":
-		_, _, errr = req.Service.Endpoints["AddCommentThreadEndpoint"].CallEncode([]interface{}{
+		_, _, errr = req.Service.Endpoints["AddCommentThreadEndpoint"].CallEncodeContext(req.Context, []interface{}{
 			req.Authorization,
 			string(ebody),
 		})
 	case "comment":
-		_, _, errr = req.Service.Endpoints["AddCommentEndpoint"].CallEncode([]interface{}{
+		_, _, errr = req.Service.Endpoints["AddCommentEndpoint"].CallEncodeContext(req.Context, []interface{}{
 			req.Authorization,
 			string(ebody),
 		})
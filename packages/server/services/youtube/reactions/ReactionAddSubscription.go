@@ -34,7 +34,7 @@ func addSubscriptionToChannel(req static.AreaRequest) shared.AreaResponse {
 		return shared.AreaResponse{Error: err}
 	}
 
-	_, _, err = req.Service.Endpoints["AddSubscriptionEndpoint"].CallEncode([]interface{}{
+	_, _, err = req.Service.Endpoints["AddSubscriptionEndpoint"].CallEncodeContext(req.Context, []interface{}{
 		req.Authorization,
 		string(ebody),
 	})
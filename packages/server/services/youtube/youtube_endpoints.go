@@ -26,26 +26,31 @@ func YoutubeEndpoints() static.ServiceEndpoint {
 			BaseURL:        "https://www.googleapis.com/youtube/v3/subscriptions",
 			Params:         BasicEndpointParams,
 			ExpectedStatus: []int{200},
+			Conditional:    true,
 		},
 		"GetAllVideosEndpoint": {
 			BaseURL:        "https://www.googleapis.com/youtube/v3/videos",
 			Params:         BasicEndpointParams,
 			ExpectedStatus: []int{200},
+			Conditional:    true,
 		},
 		"GetAllRepliesEndpoint": {
 			BaseURL:        "https://www.googleapis.com/youtube/v3/comments",
 			Params:         BasicEndpointParams,
 			ExpectedStatus: []int{200},
+			Conditional:    true,
 		},
 		"GetAllCommentsEndpoint": {
 			BaseURL:        "https://www.googleapis.com/youtube/v3/commentThreads",
 			Params:         BasicEndpointParams,
 			ExpectedStatus: []int{200},
+			Conditional:    true,
 		},
 		"GetPlaylistItemsEndpoint": {
 			BaseURL:        "https://www.googleapis.com/youtube/v3/playlistItems",
 			Params:         GetPlaylistEndpointParams,
 			ExpectedStatus: []int{200},
+			Conditional:    true,
 		},
 		// Reactions
 		"AddSubscriptionEndpoint": {
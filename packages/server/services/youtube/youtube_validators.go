@@ -27,17 +27,17 @@ func VideoIdValidator(
 	service *static.Service,
 	value interface{},
 	store map[string]interface{},
-) bool {
+) *static.ValidationError {
 	if value == nil {
-		return false
+		return static.MissingError()
 	}
 
 	if _, ok := value.(string); !ok {
-		return false
+		return static.InvalidTypeError("string")
 	}
 
 	if value == "{{youtube:video:id}}" {
-		return true
+		return nil
 	}
 
 	encode, _, err := service.Endpoints["GetAllVideosEndpoint"].CallEncode([]interface{}{
@@ -49,19 +49,15 @@ func VideoIdValidator(
 	})
 
 	if err != nil {
-		return false
+		return static.NotFoundError("video")
 	}
 
 	var videos common.YoutubeVideoResponse
-	if err := json.Unmarshal(encode, &videos); err != nil {
-		return false
+	if err := json.Unmarshal(encode, &videos); err != nil || len(videos.Items) == 0 {
+		return static.NotFoundError("video")
 	}
 
-	if len(videos.Items) == 0 {
-		return false
-	}
-
-	return true
+	return nil
 }
 
 // It checks if the value is a valid YouTube channel ID
@@ -70,17 +66,17 @@ func ChannelIdValidator(
 	service *static.Service,
 	value interface{},
 	store map[string]interface{},
-) bool {
+) *static.ValidationError {
 	if value == nil {
-		return false
+		return static.MissingError()
 	}
 
 	if _, ok := value.(string); !ok {
-		return false
+		return static.InvalidTypeError("string")
 	}
 
 	if value == "{{youtube:channel:id}}" {
-		return true
+		return nil
 	}
 
 	encode, _, err := service.Endpoints["GetAllChannelsEndpoint"].CallEncode([]interface{}{
@@ -92,19 +88,15 @@ func ChannelIdValidator(
 	})
 
 	if err != nil {
-		return false
+		return static.NotFoundError("channel")
 	}
 
 	var channels common.YoutubeChannelsResponse
-	if err := json.Unmarshal(encode, &channels); err != nil {
-		return false
-	}
-
-	if len(channels.Items) == 0 {
-		return false
+	if err := json.Unmarshal(encode, &channels); err != nil || len(channels.Items) == 0 {
+		return static.NotFoundError("channel")
 	}
 
-	return true
+	return nil
 }
 
 // It checks if the playlist id is valid
@@ -113,13 +105,13 @@ func PlaylistIdValidator(
 	service *static.Service,
 	value interface{},
 	store map[string]interface{},
-) bool {
+) *static.ValidationError {
 	if value == nil {
-		return false
+		return static.MissingError()
 	}
 
 	if _, ok := value.(string); !ok {
-		return false
+		return static.InvalidTypeError("string")
 	}
 
 	encode, _, err := service.Endpoints["GetAllPlaylistsEndpoint"].CallEncode([]interface{}{
@@ -131,19 +123,15 @@ func PlaylistIdValidator(
 	})
 
 	if err != nil {
-		return false
+		return static.NotFoundError("playlist")
 	}
 
 	var playlists common.YoutubePlaylistsResponse
-	if err := json.Unmarshal(encode, &playlists); err != nil {
-		return false
-	}
-
-	if len(playlists.Items) == 0 {
-		return false
+	if err := json.Unmarshal(encode, &playlists); err != nil || len(playlists.Items) == 0 {
+		return static.NotFoundError("playlist")
 	}
 
-	return true
+	return nil
 }
 
 // It takes a string, and checks if it's a valid YouTube channel name
@@ -152,13 +140,13 @@ func ChannelNameValidator(
 	service *static.Service,
 	value interface{},
 	store map[string]interface{},
-) bool {
+) *static.ValidationError {
 	if value == nil {
-		return false
+		return static.MissingError()
 	}
 
 	if _, ok := value.(string); !ok {
-		return false
+		return static.InvalidTypeError("string")
 	}
 
 	encode, _, err := service.Endpoints["GetAllChannelsEndpoint"].CallEncode([]interface{}{
@@ -170,19 +158,15 @@ func ChannelNameValidator(
 	})
 
 	if err != nil {
-		return false
+		return static.NotFoundError("channel")
 	}
 
 	var channels common.YoutubeChannelsResponse
-	if err := json.Unmarshal(encode, &channels); err != nil {
-		return false
+	if err := json.Unmarshal(encode, &channels); err != nil || len(channels.Items) == 0 {
+		return static.NotFoundError("channel")
 	}
 
-	if len(channels.Items) == 0 {
-		return false
-	}
-
-	return true
+	return nil
 }
 
 // It checks if the commentable id is valid
@@ -191,31 +175,27 @@ func EntityCommentableIdValidator(
 	service *static.Service,
 	value interface{},
 	store map[string]interface{},
-) bool {
+) *static.ValidationError {
 	if value == nil {
-		return false
+		return static.MissingError()
 	}
 
 	if _, ok := value.(string); !ok {
-		return false
+		return static.InvalidTypeError("string")
 	}
 
 	if store["req:entity:commentable:type"] == nil {
-		return false
+		return static.MissingDependencyError("req:entity:commentable:type")
 	}
 
 	switch store["req:entity:commentable:type"].(string) {
 	case "video":
-		if VideoIdValidator(authorization, service, value, store) {
-			return true
-		}
+		return VideoIdValidator(authorization, service, value, store)
 	case "channel":
-		if ChannelIdValidator(authorization, service, value, store) {
-			return true
-		}
+		return ChannelIdValidator(authorization, service, value, store)
 	case "comment":
 		if value == "{{youtube:comment:id}}" {
-			return true
+			return nil
 		}
 
 		encode, _, err := service.Endpoints["GetAllRepliesEndpoint"].CallEncode([]interface{}{
@@ -227,22 +207,18 @@ func EntityCommentableIdValidator(
 		})
 
 		if err != nil {
-			return false
+			return static.NotFoundError("comment")
 		}
 
 		var comments common.YoutubeCommentsResponse
-		if err := json.Unmarshal(encode, &comments); err != nil {
-			return false
-		}
-
-		if len(comments.Items) == 0 {
-			return false
+		if err := json.Unmarshal(encode, &comments); err != nil || len(comments.Items) == 0 {
+			return static.NotFoundError("comment")
 		}
 
-		return true
+		return nil
 	}
 
-	return false
+	return static.NotAllowedError("video", "channel", "comment")
 }
 
 // It checks that the value is a string and that it's either "video", "channel" or "comment"
@@ -251,19 +227,19 @@ func EntityCommentableTypeValidator(
 	service *static.Service,
 	value interface{},
 	store map[string]interface{},
-) bool {
+) *static.ValidationError {
 	if value == nil {
-		return false
+		return static.MissingError()
 	}
 
 	if _, ok := value.(string); !ok {
-		return false
+		return static.InvalidTypeError("string")
 	}
 
 	switch value.(string) {
 	case "video", "channel", "comment":
-		return true
+		return nil
 	}
 
-	return false
+	return static.NotAllowedError("video", "channel", "comment")
 }
@@ -0,0 +1,16 @@
+package store
+
+import (
+	"area-server/config"
+	"context"
+	"strings"
+	"time"
+)
+
+// AllowMail returns true if no mail of this kind was sent to the address since `MailCooldown` seconds
+// (and starts the cooldown)
+func AllowMail(kind string, address string) (bool, error) {
+	cooldown := time.Duration(config.CFG.Account.MailCooldown) * time.Second
+	key := "mail:cooldown:" + kind + ":" + strings.ToLower(address)
+	return Redis.Conn().SetNX(context.Background(), key, "1", cooldown).Result()
+}
@@ -0,0 +1,102 @@
+package store
+
+import (
+	"area-server/classes/static"
+	"area-server/utils"
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Lifetime of a state, the user must come back from the provider before it expires
+const OAuthStateDuration = 10 * time.Minute
+
+const (
+	OAuthLogin         = "login"         // The flow logs the user in (`/auth/external`)
+	OAuthAuthorization = "authorization" // The flow adds an authorization to an account (`/authorization`)
+	OAuthLink          = "link"          // The flow links an external login to an account (`/me/identities`)
+)
+
+// `OAuthFlow` is an OAuth2 flow started by a client, stored until the provider redirects the user.
+// @property {string} Authenticator - The name of the authenticator.
+// @property {string} RedirectURI - The redirect URI sent to the provider.
+// @property {string} Verifier - The PKCE code verifier (empty if the provider does not support PKCE).
+// @property {string} Purpose - `OAuthLogin`, `OAuthAuthorization` or `OAuthLink`.
+// @property {string} Account - The UUID of the account that started the flow (authorization and link only).
+type OAuthFlow struct {
+	Authenticator string `json:"authenticator"`
+	RedirectURI   string `json:"redirect_uri"`
+	Verifier      string `json:"verifier,omitempty"`
+	Purpose       string `json:"purpose"`
+	Account       string `json:"account,omitempty"`
+}
+
+// It returns the redis key of a state
+func oauthStateKey(state string) string {
+	return "oauth:state:" + state
+}
+
+// StartOAuthFlow generates a one-time state and saves the flow until `OAuthStateDuration`
+func StartOAuthFlow(flow OAuthFlow) (string, error) {
+	state, err := utils.GenerateOAuthState()
+	if err != nil {
+		return "", err
+	}
+	encoded, err := json.Marshal(flow)
+	if err != nil {
+		return "", err
+	}
+	if err := Redis.Set(oauthStateKey(state), encoded, OAuthStateDuration); err != nil {
+		return "", err
+	}
+	return state, nil
+}
+
+// BeginOAuthFlow starts a flow for the authenticator (with a PKCE verifier if the provider supports it)
+// and returns the authorization URI the user must be redirected to, with its state
+func BeginOAuthFlow(authenticator *static.OAuth2Authenticator, flow OAuthFlow) (string, string, error) {
+	flow.Authenticator = authenticator.Name
+	if authenticator.PKCE {
+		verifier, err := utils.GeneratePKCEVerifier()
+		if err != nil {
+			return "", "", err
+		}
+		flow.Verifier = verifier
+	}
+	state, err := StartOAuthFlow(flow)
+	if err != nil {
+		return "", "", err
+	}
+	uri, err := authenticator.AuthorizationURL(state, flow.RedirectURI, flow.Verifier)
+	if err != nil {
+		return "", "", err
+	}
+	return uri, state, nil
+}
+
+// ConsumeOAuthFlow returns the flow of the state and deletes it, so a state can only be used once
+// (nil if the state is unknown or expired)
+func ConsumeOAuthFlow(state string) (*OAuthFlow, error) {
+	if state == "" {
+		return nil, nil
+	}
+	encoded, err := Redis.Conn().GetDel(context.Background(), oauthStateKey(state)).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	flow := new(OAuthFlow)
+	if err := json.Unmarshal(encoded, flow); err != nil {
+		return nil, err
+	}
+	return flow, nil
+}
+
+// It returns true if the flow was started for this authenticator, redirect URI, purpose and account
+func (f *OAuthFlow) Matches(authenticator string, redirectURI string, purpose string, account string) bool {
+	return f.Authenticator == authenticator && f.RedirectURI == redirectURI && f.Purpose == purpose && f.Account == account
+}
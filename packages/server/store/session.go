@@ -1,31 +1,54 @@
 package store
 
 import (
+	"area-server/config"
 	redis "area-server/db/redis"
+	"area-server/utils"
 	"errors"
+	"time"
 
 	"github.com/gofiber/fiber/v2/middleware/session"
 	"github.com/google/uuid"
 )
 
+// The redis storage shared by the sessions and the other temporary data of the server
+var Redis = redis.CreateRedisStorage().(*redis.Storage)
+
+// Header containing the CSRF token of the session, required by the mutating requests (session mode)
+const CSRFHeader = "X-CSRF-Token"
+
 // It's creating a new session store with the given configuration.
+// The cookie can't be read by the scripts, the client reads the CSRF token from `/auth/csrf`.
 var SessionStore = session.New(session.Config{
 	Expiration:     3600 * 24 * 7, // 7 days
 	KeyLookup:      "cookie:X-Session",
-	CookieHTTPOnly: false,
+	CookieHTTPOnly: true,
+	CookieSameSite: config.CFG.Cookie.SameSite,
+	CookieSecure:   config.CFG.HTTPS || config.CFG.Cookie.Secure || config.CFG.Cookie.SameSite == "None",
 	Storage:        redis.CreateRedisStorage(),
 })
 
-// TODO: implement csrf
 // Return session ID
 func GenerateSession(session *session.Session, accountUUID uuid.UUID) error {
-	//crsfToken := utils.GenerateRandomString(32)
-
 	if session.Get("account") != nil {
 		return errors.New("Session is invalide")
 	}
 
+	// A new CSRF token is issued with the session (synchronizer token)
+	csrfToken, err := utils.GenerateCSRFToken()
+	if err != nil {
+		return errors.New("Session can't be generated")
+	}
+
+	// The session opened before the login (e.g. by `/auth/csrf`) gets a new ID, so an ID known before
+	// the login can't be used after it (session fixation)
+	if err := session.Regenerate(); err != nil {
+		return errors.New("Session can't be generated")
+	}
+
 	session.Set("account", accountUUID.String())
+	session.Set("issued_at", time.Now().Unix())
+	session.Set("csrf", csrfToken)
 
 	// Save session
 	if err := session.Save(); err != nil {
@@ -42,3 +65,27 @@ func DestroySession(session *session.Session) bool {
 	}
 	return true
 }
+
+// CSRFToken returns the CSRF token of the session, a token is generated (and the session saved) if the
+// session has none yet, e.g. before the login
+func CSRFToken(session *session.Session) (string, error) {
+	if token, ok := session.Get("csrf").(string); ok && token != "" {
+		return token, nil
+	}
+
+	token, err := utils.GenerateCSRFToken()
+	if err != nil {
+		return "", err
+	}
+	session.Set("csrf", token)
+	if err := session.Save(); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// CheckCSRF returns true if the token is the CSRF token of the session
+func CheckCSRF(session *session.Session, token string) bool {
+	expected, _ := session.Get("csrf").(string)
+	return utils.CheckCSRFToken(expected, token)
+}
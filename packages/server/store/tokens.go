@@ -0,0 +1,224 @@
+package store
+
+import (
+	"area-server/config"
+	"area-server/utils"
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+// Types of the JWT tokens (`typ` claim)
+const (
+	AccessToken  = "access"
+	RefreshToken = "refresh"
+)
+
+var (
+	ErrInvalidToken = errors.New("Invalid token")
+	ErrTokenRevoked = errors.New("Token revoked")
+	ErrTokenReused  = errors.New("Refresh token already used, the session is revoked")
+)
+
+// `TokenPair` is returned to the client when it logs in (token mode) or refreshes its tokens.
+// @property {string} AccessToken - The short-lived token sent in the `Authorization` header.
+// @property {string} RefreshToken - The token exchanged (once) for a new pair on `/auth/refresh`.
+// @property {int} ExpiresIn - The lifetime (in seconds) of the access token.
+type TokenPair struct {
+	AccessToken  string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+// It returns the redis key of a refresh token that can still be used
+func refreshTokenKey(id string) string {
+	return "token:refresh:" + id
+}
+
+// It returns the redis key of a revoked access token
+func revokedTokenKey(id string) string {
+	return "token:revoked:" + id
+}
+
+// It returns the redis key of a revoked family (all the tokens issued from the same login)
+func revokedFamilyKey(family string) string {
+	return "token:family:" + family
+}
+
+// It returns the redis key of the time before which the tokens of an account are revoked
+func revokedAccountKey(account string) string {
+	return "token:account:" + account
+}
+
+// It returns the lifetime of the refresh tokens
+func refreshTokenDuration() time.Duration {
+	return time.Duration(config.CFG.TokenDuration) * time.Second
+}
+
+// IssueTokens starts a new family of tokens for the account (on login)
+func IssueTokens(accountUUID uuid.UUID) (*TokenPair, error) {
+	family, err := utils.GenerateTokenID()
+	if err != nil {
+		return nil, err
+	}
+	return issueTokens(accountUUID.String(), family)
+}
+
+// It generates an access token and a refresh token of the family, the refresh token is saved until it
+// is used or expires
+func issueTokens(account string, family string) (*TokenPair, error) {
+	accessID, err := utils.GenerateTokenID()
+	if err != nil {
+		return nil, err
+	}
+	refreshID, err := utils.GenerateTokenID()
+	if err != nil {
+		return nil, err
+	}
+
+	access, err := utils.GenerateJWT(map[string]interface{}{
+		"accountID": account,
+		"typ":       AccessToken,
+		"jti":       accessID,
+		"fam":       family,
+	}, config.CFG.AccessTokenDuration)
+	if err != nil {
+		return nil, err
+	}
+	refresh, err := utils.GenerateJWT(map[string]interface{}{
+		"accountID": account,
+		"typ":       RefreshToken,
+		"jti":       refreshID,
+		"fam":       family,
+	}, config.CFG.TokenDuration)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := Redis.Set(refreshTokenKey(refreshID), []byte(family), refreshTokenDuration()); err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		ExpiresIn:    config.CFG.AccessTokenDuration,
+	}, nil
+}
+
+// It returns a string claim ("" if it is missing)
+func stringClaim(claims map[string]interface{}, name string) string {
+	value, _ := claims[name].(string)
+	return value
+}
+
+// It returns a numeric claim (0 if it is missing)
+func timeClaim(claims map[string]interface{}, name string) int64 {
+	switch value := claims[name].(type) {
+	case float64:
+		return int64(value)
+	case int64:
+		return value
+	}
+	return 0
+}
+
+// ParseToken validates a token of the given type and checks it has not been revoked
+func ParseToken(token string, typ string) (map[string]interface{}, error) {
+	claims, err := utils.ValidateJWT(token)
+	if err != nil || stringClaim(claims, "typ") != typ || stringClaim(claims, "accountID") == "" || stringClaim(claims, "jti") == "" {
+		return nil, ErrInvalidToken
+	}
+	if IsTokenRevoked(claims) {
+		return nil, ErrTokenRevoked
+	}
+	return claims, nil
+}
+
+// RefreshTokens exchanges a refresh token for a new pair. A refresh token can only be used once: if
+// an already used one is presented, it has been stolen and the whole family is revoked.
+func RefreshTokens(refreshToken string) (*TokenPair, error) {
+	claims, err := ParseToken(refreshToken, RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	family := stringClaim(claims, "fam")
+	stored, err := Redis.Conn().GetDel(context.Background(), refreshTokenKey(stringClaim(claims, "jti"))).Result()
+	if err == redis.Nil || (err == nil && stored != family) {
+		if err := RevokeFamily(family); err != nil {
+			return nil, err
+		}
+		return nil, ErrTokenReused
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return issueTokens(stringClaim(claims, "accountID"), family)
+}
+
+// IsTokenRevoked checks the token, its family and its account in the revocation list (a token is
+// considered revoked if the list can't be read)
+func IsTokenRevoked(claims map[string]interface{}) bool {
+	ctx := context.Background()
+
+	keys := []string{revokedTokenKey(stringClaim(claims, "jti"))}
+	if family := stringClaim(claims, "fam"); family != "" {
+		keys = append(keys, revokedFamilyKey(family))
+	}
+	count, err := Redis.Conn().Exists(ctx, keys...).Result()
+	if err != nil || count > 0 {
+		return true
+	}
+
+	revoked, err := RevokedSince(stringClaim(claims, "accountID"), timeClaim(claims, "iat"))
+	return err != nil || revoked
+}
+
+// RevokedSince returns true if the account logged out everywhere after `issuedAt` (unix time)
+func RevokedSince(account string, issuedAt int64) (bool, error) {
+	value, err := Redis.Conn().Get(context.Background(), revokedAccountKey(account)).Result()
+	if err == redis.Nil {
+		return false, nil
+	}
+	if err != nil {
+		return true, err
+	}
+	before, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return true, err
+	}
+	return issuedAt < before, nil
+}
+
+// RevokeToken revokes an access token (until it expires) and its family, so its refresh token can't
+// be used anymore
+func RevokeToken(claims map[string]interface{}) error {
+	ttl := time.Until(time.Unix(timeClaim(claims, "exp"), 0))
+	if ttl > 0 {
+		if err := Redis.Set(revokedTokenKey(stringClaim(claims, "jti")), []byte("1"), ttl); err != nil {
+			return err
+		}
+	}
+	if family := stringClaim(claims, "fam"); family != "" {
+		return RevokeFamily(family)
+	}
+	return nil
+}
+
+// RevokeFamily revokes all the tokens issued from the same login
+func RevokeFamily(family string) error {
+	return Redis.Set(revokedFamilyKey(family), []byte("1"), refreshTokenDuration())
+}
+
+// RevokeAccount revokes all the tokens and sessions of the account issued until now ("log out
+// everywhere")
+func RevokeAccount(accountUUID uuid.UUID) error {
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	return Redis.Set(revokedAccountKey(accountUUID.String()), []byte(now), refreshTokenDuration())
+}
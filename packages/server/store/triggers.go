@@ -81,13 +81,15 @@ func StartTrigger(id uuid.UUID) error {
 	return nil
 }
 
-// It closes all the channels for the trigger, and sets the trigger's Stopped flag to true
+// It cancels the requests in progress, closes all the channels for the trigger, and sets the trigger's
+// Stopped flag to true
 func StopTrigger(id uuid.UUID) error {
 	fmt.Println("Stopping trigger for applet: ", id)
 	_, ok := Triggers[id]
 	if !ok {
 		return fmt.Errorf("Trigger not found")
 	}
+	Triggers[id].Cancel()
 	select {
 	case <-Triggers[id].Interrupt:
 		break
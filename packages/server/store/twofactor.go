@@ -0,0 +1,129 @@
+package store
+
+import (
+	"area-server/utils"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+// Lifetime of a login waiting for its second factor
+const LoginChallengeDuration = 5 * time.Minute
+
+// Number of codes that can be tried for a login
+const LoginChallengeAttempts = 5
+
+// Number of invalid codes allowed for an account (all its logins together) before its logins are
+// refused, until `SecondFactorLockDuration` after the last invalid code
+const SecondFactorMaxFailures = 10
+
+// Time the logins of an account are refused after too many invalid codes
+const SecondFactorLockDuration = 15 * time.Minute
+
+var ErrLoginChallenge = errors.New("Login expired, log in again")
+
+var ErrSecondFactorLocked = errors.New("Too many invalid codes, try again later")
+
+// It returns the redis key of a login waiting for its second factor
+func loginChallengeKey(challenge string) string {
+	return "2fa:challenge:" + challenge
+}
+
+// It returns the redis key of the number of codes tried for a login
+func loginAttemptsKey(challenge string) string {
+	return "2fa:attempts:" + challenge
+}
+
+// It returns the redis key of the number of invalid codes of an account
+func secondFactorFailuresKey(accountUUID uuid.UUID) string {
+	return "2fa:failures:" + accountUUID.String()
+}
+
+// It returns `ErrSecondFactorLocked` if too many invalid codes were sent for the account
+func checkSecondFactorLock(accountUUID uuid.UUID) error {
+	failures, err := Redis.Conn().Get(context.Background(), secondFactorFailuresKey(accountUUID)).Int()
+	if err == redis.Nil {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if failures >= SecondFactorMaxFailures {
+		return ErrSecondFactorLocked
+	}
+	return nil
+}
+
+// StartLoginChallenge saves a login (the password is verified) until the second factor is verified,
+// `ErrSecondFactorLocked` is returned if too many invalid codes were sent for the account
+func StartLoginChallenge(accountUUID uuid.UUID) (string, error) {
+	if err := checkSecondFactorLock(accountUUID); err != nil {
+		return "", err
+	}
+
+	challenge, err := utils.GenerateTokenID()
+	if err != nil {
+		return "", err
+	}
+	if err := Redis.Set(loginChallengeKey(challenge), []byte(accountUUID.String()), LoginChallengeDuration); err != nil {
+		return "", err
+	}
+	return challenge, nil
+}
+
+// LoginChallengeAccount counts an attempt and returns the account of the login, the login is dropped
+// after `LoginChallengeAttempts` attempts
+func LoginChallengeAccount(challenge string) (uuid.UUID, error) {
+	ctx := context.Background()
+
+	attempts, err := Redis.Conn().Incr(ctx, loginAttemptsKey(challenge)).Result()
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if attempts == 1 {
+		Redis.Conn().Expire(ctx, loginAttemptsKey(challenge), LoginChallengeDuration)
+	}
+	if attempts > LoginChallengeAttempts {
+		EndLoginChallenge(challenge)
+		return uuid.Nil, ErrLoginChallenge
+	}
+
+	account, err := Redis.Conn().Get(ctx, loginChallengeKey(challenge)).Result()
+	if err == redis.Nil {
+		return uuid.Nil, ErrLoginChallenge
+	}
+	if err != nil {
+		return uuid.Nil, err
+	}
+	accountUUID, err := uuid.Parse(account)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if err := checkSecondFactorLock(accountUUID); err != nil {
+		return uuid.Nil, err
+	}
+	return accountUUID, nil
+}
+
+// RecordSecondFactorFailure counts an invalid code for the account, the logins are refused after
+// `SecondFactorMaxFailures` invalid codes (however many logins were started)
+func RecordSecondFactorFailure(accountUUID uuid.UUID) error {
+	ctx := context.Background()
+	if err := Redis.Conn().Incr(ctx, secondFactorFailuresKey(accountUUID)).Err(); err != nil {
+		return err
+	}
+	return Redis.Conn().Expire(ctx, secondFactorFailuresKey(accountUUID), SecondFactorLockDuration).Err()
+}
+
+// ResetSecondFactorFailures forgets the invalid codes of the account (a valid code was sent)
+func ResetSecondFactorFailures(accountUUID uuid.UUID) {
+	Redis.Conn().Del(context.Background(), secondFactorFailuresKey(accountUUID))
+}
+
+// EndLoginChallenge drops a login (the second factor is verified or too many codes were tried)
+func EndLoginChallenge(challenge string) {
+	Redis.Conn().Del(context.Background(), loginChallengeKey(challenge), loginAttemptsKey(challenge))
+}
@@ -0,0 +1,98 @@
+package utils
+
+import (
+	"area-server/config"
+	"area-server/db/postgres"
+	"area-server/db/postgres/models"
+	"area-server/mail"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+)
+
+// Types of the tokens sent by mail (`typ` claim)
+const (
+	EmailVerificationToken = "email_verification"
+	PasswordResetToken     = "password_reset"
+)
+
+var ErrInvalidAccountToken = errors.New("Invalid or expired token")
+
+// It binds a token to the current state of the account: the token becomes invalid when the email
+// changes, or the password for a reset (so a reset token can only be used once)
+func accountTokenBinding(purpose string, account *models.Account) string {
+	data := purpose + "\x00" + account.Email
+	if purpose == PasswordResetToken {
+		data += "\x00" + account.Password
+	}
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:16])
+}
+
+// GenerateAccountToken returns a signed token for the account, valid `exp` seconds
+func GenerateAccountToken(purpose string, account *models.Account, exp int) (string, error) {
+	return GenerateJWT(map[string]interface{}{
+		"accountID": account.UUID.String(),
+		"typ":       purpose,
+		"bind":      accountTokenBinding(purpose, account),
+	}, exp)
+}
+
+// ParseAccountToken validates the signature, the expiration and the type of a token, and returns the
+// ID of its account and its binding
+func ParseAccountToken(token string, purpose string) (string, string, error) {
+	claims, err := ValidateJWT(token)
+	if err != nil {
+		return "", "", ErrInvalidAccountToken
+	}
+	accountID, _ := claims["accountID"].(string)
+	typ, _ := claims["typ"].(string)
+	binding, _ := claims["bind"].(string)
+	if typ != purpose || accountID == "" || binding == "" {
+		return "", "", ErrInvalidAccountToken
+	}
+	return accountID, binding, nil
+}
+
+// CheckAccountToken returns true if the binding of the token matches the current state of the account
+func CheckAccountToken(purpose string, binding string, account *models.Account) bool {
+	return subtle.ConstantTimeCompare([]byte(binding), []byte(accountTokenBinding(purpose, account))) == 1
+}
+
+// ValidateAccountToken returns the account of a token sent by mail
+func ValidateAccountToken(token string, purpose string) (*models.Account, error) {
+	accountID, binding, err := ParseAccountToken(token, purpose)
+	if err != nil {
+		return nil, err
+	}
+
+	var account models.Account
+	if result := postgres.DB.Where("uuid = ?", accountID).First(&account); result.Error != nil {
+		return nil, ErrInvalidAccountToken
+	}
+	if !CheckAccountToken(purpose, binding, &account) {
+		return nil, ErrInvalidAccountToken
+	}
+	return &account, nil
+}
+
+// SendVerificationEmail sends (in the background) the link to verify the address of the account
+func SendVerificationEmail(account *models.Account) error {
+	token, err := GenerateAccountToken(EmailVerificationToken, account, config.CFG.Account.VerificationDuration)
+	if err != nil {
+		return err
+	}
+	mail.SendAsync(mail.VerificationMessage(account.Email, token))
+	return nil
+}
+
+// SendPasswordResetEmail sends (in the background) the link to reset the password of the account
+func SendPasswordResetEmail(account *models.Account) error {
+	token, err := GenerateAccountToken(PasswordResetToken, account, config.CFG.Account.ResetDuration)
+	if err != nil {
+		return err
+	}
+	mail.SendAsync(mail.PasswordResetMessage(account.Email, token))
+	return nil
+}
@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"area-server/db/postgres/models"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+)
+
+func TestAccountToken(t *testing.T) {
+	t.Setenv("JWT_SECRET", "secret")
+	t.Setenv("JWT_KEYS", "")
+	t.Setenv("JWT_KEY_ID", "")
+	if _, err := reloadJWTKeys(); err != nil {
+		t.Fatal(err)
+	}
+	account := &models.Account{UUID: uuid.New(), Email: "user@example.com", Password: "hash"}
+
+	token, err := GenerateAccountToken(PasswordResetToken, account, 60)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := ParseAccountToken(token, EmailVerificationToken); err != ErrInvalidAccountToken {
+		t.Errorf("expected the reset token to be refused for a verification, got %v", err)
+	}
+
+	accountID, binding, err := ParseAccountToken(token, PasswordResetToken)
+	if err != nil || accountID != account.UUID.String() {
+		t.Fatalf("expected the token to be valid, got %q %v", accountID, err)
+	}
+	if !CheckAccountToken(PasswordResetToken, binding, account) {
+		t.Error("expected the binding to match")
+	}
+
+	// The password changed: the reset token was used
+	account.Password = "new hash"
+	if CheckAccountToken(PasswordResetToken, binding, account) {
+		t.Error("expected the reset token to be invalid after a password change")
+	}
+
+	// The verification token only depends on the address
+	verification, _ := GenerateAccountToken(EmailVerificationToken, account, 60)
+	_, binding, _ = ParseAccountToken(verification, EmailVerificationToken)
+	account.Password = "another hash"
+	if !CheckAccountToken(EmailVerificationToken, binding, account) {
+		t.Error("expected the verification token to stay valid")
+	}
+	account.Email = "other@example.com"
+	if CheckAccountToken(EmailVerificationToken, binding, account) {
+		t.Error("expected the verification token to be invalid after an email change")
+	}
+
+	expired, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"accountID": account.UUID.String(),
+		"typ":       EmailVerificationToken,
+		"bind":      binding,
+		"exp":       time.Now().Add(-time.Minute).Unix(),
+	}).SignedString([]byte("secret"))
+	if _, _, err := ParseAccountToken(expired, EmailVerificationToken); err != ErrInvalidAccountToken {
+		t.Errorf("expected the expired token to be refused, got %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// Prefix of the API keys, to recognize them (e.g. in a secret scanner)
+const APIKeyPrefix = "area_"
+
+// Scopes that can be granted to an API key, the management of the account always needs a session or
+// a token
+const (
+	ScopeWebhooksTrigger = "webhooks:trigger" // Trigger the webhooks of the account
+	ScopeAppletsRead     = "applets:read"     // Read the applets and their status
+	ScopeAppletsWrite    = "applets:write"    // Create, start, stop and delete the applets
+)
+
+// The scopes accepted when a key is created
+var APIKeyScopes = []string{ScopeWebhooksTrigger, ScopeAppletsRead, ScopeAppletsWrite}
+
+// GenerateAPIKey returns a new key (only shown once to the user), the prefix displayed to recognize
+// it and the hash stored in the database
+func GenerateAPIKey() (key string, prefix string, hash string, err error) {
+	id, err := randomHex(4)
+	if err != nil {
+		return "", "", "", err
+	}
+	secret, err := randomURLSafe(32)
+	if err != nil {
+		return "", "", "", err
+	}
+	prefix = APIKeyPrefix + id
+	key = prefix + "_" + secret
+	return key, prefix, HashAPIKey(key), nil
+}
+
+// HashAPIKey returns the hash of a key, the keys are random so a fast hash is enough
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsAPIKey returns true if the credential looks like an API key
+func IsAPIKey(credential string) bool {
+	return strings.HasPrefix(credential, APIKeyPrefix)
+}
+
+// ValidAPIKeyScope returns true if the scope can be granted to a key
+func ValidAPIKeyScope(scope string) bool {
+	for _, s := range APIKeyScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// It returns n random bytes encoded in hexadecimal
+func randomHex(n int) (string, error) {
+	buffer := make([]byte, n)
+	if _, err := rand.Read(buffer); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buffer), nil
+}
@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateAPIKey(t *testing.T) {
+	key, prefix, hash, err := GenerateAPIKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsAPIKey(key) || !strings.HasPrefix(key, prefix+"_") || len(prefix) != len(APIKeyPrefix)+8 {
+		t.Errorf("unexpected key %q (prefix %q)", key, prefix)
+	}
+	if hash != HashAPIKey(key) || strings.Contains(hash, key) || len(hash) != 64 {
+		t.Errorf("unexpected hash %q", hash)
+	}
+
+	other, _, otherHash, _ := GenerateAPIKey()
+	if other == key || otherHash == hash {
+		t.Error("the keys must be random")
+	}
+}
+
+func TestAPIKeyScopes(t *testing.T) {
+	for _, scope := range []string{ScopeWebhooksTrigger, ScopeAppletsRead, ScopeAppletsWrite} {
+		if !ValidAPIKeyScope(scope) {
+			t.Errorf("expected %q to be valid", scope)
+		}
+	}
+	for _, scope := range []string{"", "*", "account:write"} {
+		if ValidAPIKeyScope(scope) {
+			t.Errorf("expected %q to be refused", scope)
+		}
+	}
+}
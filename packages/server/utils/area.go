@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"encoding/json"
 	"errors"
 	"time"
 )
@@ -129,3 +130,77 @@ func IsLatestBasic(
 	(*store)["ctx:last:total"] = nbItems
 	return true, nil
 }
+
+// We use this function to walk the new items of a paginated endpoint (newest first) since the last
+// checkpoint, the first call only saves the checkpoint (empty if there is no item yet, so all the items
+// of the next calls are new). The IDs of the first page are kept too, so a deleted checkpoint is
+// replaced by the next item already seen and the walk stops at the first page. The walk only goes
+// further when the first page is all new, and if no item seen is found the new items are limited
+// to the first page. The new items are returned newest first.
+func IsLatestByCheckpoint(
+	store *map[string]interface{},
+	it *PageIterator,
+	GetID func(item json.RawMessage) (string, error),
+) ([]json.RawMessage, error) {
+	checkpoint, hasCheckpoint := (*store)["ctx:last:checkpoint"].(string)
+	seen := map[string]bool{}
+	if hasCheckpoint && checkpoint != "" {
+		seen[checkpoint] = true
+	}
+	if ids, ok := (*store)["ctx:last:seen"].([]string); ok {
+		for _, id := range ids {
+			seen[id] = true
+		}
+	}
+
+	newItems := []json.RawMessage{}
+	firstPage := []string{}
+	firstPageNew := 0
+	found := false
+	for pages := 0; !found && it.Next(); pages++ {
+		for _, item := range it.Items() {
+			id, err := GetID(item)
+			if err != nil {
+				return nil, err
+			}
+			if pages == 0 {
+				firstPage = append(firstPage, id)
+			}
+			if !hasCheckpoint || seen[id] {
+				found = true
+				break
+			}
+			newItems = append(newItems, item)
+		}
+		if pages == 0 {
+			firstPageNew = len(newItems)
+			// The rest of the first page is kept to find the next item seen
+			for _, item := range it.Items()[len(firstPage):] {
+				id, err := GetID(item)
+				if err != nil {
+					return nil, err
+				}
+				firstPage = append(firstPage, id)
+			}
+		}
+	}
+	if it.Err() != nil {
+		return nil, it.Err()
+	}
+
+	if len(firstPage) > 0 || !hasCheckpoint {
+		latest := ""
+		if len(firstPage) > 0 {
+			latest = firstPage[0]
+		}
+		(*store)["ctx:last:checkpoint"] = latest
+		(*store)["ctx:last:seen"] = firstPage
+	}
+	if !hasCheckpoint {
+		return nil, nil
+	}
+	if !found {
+		return newItems[:firstPageNew], nil
+	}
+	return newItems, nil
+}
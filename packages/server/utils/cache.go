@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"sync"
+	"time"
+)
+
+// `cacheEntry` is a value kept in an `ExpiringCache`.
+type cacheEntry struct {
+	value   interface{}
+	expires time.Time
+}
+
+// `ExpiringCache` keeps values for a duration, with a maximum number of entries: the expired entries
+// are deleted when a value is added, then the entry expiring first if the cache is still full.
+type ExpiringCache struct {
+	mutex      sync.Mutex
+	entries    map[string]cacheEntry
+	duration   time.Duration
+	maxEntries int
+}
+
+// It creates an empty cache keeping the values for `duration`, with at most `maxEntries` entries
+func NewExpiringCache(duration time.Duration, maxEntries int) *ExpiringCache {
+	return &ExpiringCache{
+		entries:    make(map[string]cacheEntry),
+		duration:   duration,
+		maxEntries: maxEntries,
+	}
+}
+
+// Get returns the value of the key if it is not expired at `now`
+func (c *ExpiringCache) Get(key string, now time.Time) (interface{}, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || !now.Before(entry.expires) {
+		return nil, false
+	}
+	return entry.value, true
+}
+
+// Set adds the value of the key, it expires after the duration of the cache
+func (c *ExpiringCache) Set(key string, value interface{}, now time.Time) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.maxEntries {
+		for k, entry := range c.entries {
+			if !now.Before(entry.expires) {
+				delete(c.entries, k)
+			}
+		}
+		for len(c.entries) >= c.maxEntries {
+			oldest := ""
+			for k, entry := range c.entries {
+				if oldest == "" || entry.expires.Before(c.entries[oldest].expires) {
+					oldest = k
+				}
+			}
+			delete(c.entries, oldest)
+		}
+	}
+	c.entries[key] = cacheEntry{value: value, expires: now.Add(c.duration)}
+}
+
+// Len returns the number of entries (expired or not) in the cache
+func (c *ExpiringCache) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return len(c.entries)
+}
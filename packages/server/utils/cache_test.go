@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExpiringCache(t *testing.T) {
+	cache := NewExpiringCache(time.Minute, 10)
+	now := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	cache.Set("a", 1, now)
+	if value, ok := cache.Get("a", now.Add(30*time.Second)); !ok || value != 1 {
+		t.Errorf("expected the value, got %v %v", value, ok)
+	}
+	if _, ok := cache.Get("a", now.Add(time.Minute)); ok {
+		t.Error("expected the value to be expired")
+	}
+	if _, ok := cache.Get("b", now); ok {
+		t.Error("expected no value for an unknown key")
+	}
+}
+
+func TestExpiringCacheBounded(t *testing.T) {
+	cache := NewExpiringCache(time.Minute, 3)
+	now := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	cache.Set("expired", 0, now.Add(-2*time.Minute))
+	cache.Set("first", 1, now)
+	cache.Set("second", 2, now.Add(time.Second))
+
+	// The expired entry is deleted first
+	cache.Set("third", 3, now.Add(2*time.Second))
+	if cache.Len() != 3 {
+		t.Fatalf("expected 3 entries, got %d", cache.Len())
+	}
+	if _, ok := cache.Get("first", now.Add(3*time.Second)); !ok {
+		t.Error("expected the first entry to be kept")
+	}
+
+	// Then the entry expiring first
+	cache.Set("fourth", 4, now.Add(3*time.Second))
+	if cache.Len() != 3 {
+		t.Fatalf("expected 3 entries, got %d", cache.Len())
+	}
+	if _, ok := cache.Get("first", now.Add(4*time.Second)); ok {
+		t.Error("expected the first entry to be evicted")
+	}
+	for _, key := range []string{"second", "third", "fourth"} {
+		if _, ok := cache.Get(key, now.Add(4*time.Second)); !ok {
+			t.Errorf("expected %s to be kept", key)
+		}
+	}
+
+	// Replacing a value doesn't evict
+	cache.Set("fourth", 5, now.Add(5*time.Second))
+	if value, ok := cache.Get("second", now.Add(5*time.Second)); !ok || value != 2 || cache.Len() != 3 {
+		t.Errorf("expected the entries to be kept, got %v %v %d", value, ok, cache.Len())
+	}
+}
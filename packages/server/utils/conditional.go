@@ -0,0 +1,111 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Returned when the service answers 304, the resource did not change since the last request (the
+// action is not triggered).
+var ErrNotModified = errors.New("304 - Not modified since the last request")
+
+// Maximum number of validators kept in memory
+const MaxConditionalEntries = 10000
+
+// `conditionalScopeKey` is the key of the scope in the context of a request.
+type conditionalScopeKey struct{}
+
+// WithConditionalScope returns a context whose conditional requests share the validators of the scope
+// (e.g. the UUID of the applet), the requests without scope are never conditional.
+func WithConditionalScope(ctx context.Context, scope string) context.Context {
+	return context.WithValue(ctx, conditionalScopeKey{}, scope)
+}
+
+// `ConditionalEntry` contains the validators returned by a service for a resource.
+// @property {string} ETag - The `ETag` header, sent back in `If-None-Match`.
+// @property {string} LastModified - The `Last-Modified` header, sent back in `If-Modified-Since`.
+type ConditionalEntry struct {
+	ETag         string
+	LastModified string
+}
+
+// `ConditionalCache` stores the validators per scope, authorization and endpoint.
+type ConditionalCache struct {
+	mutex   sync.Mutex
+	entries map[string]ConditionalEntry
+	max     int
+}
+
+// The validators of the requests sent by the triggers
+var Validators = NewConditionalCache(MaxConditionalEntries)
+
+// It creates a cache keeping at most max validators
+func NewConditionalCache(max int) *ConditionalCache {
+	return &ConditionalCache{entries: make(map[string]ConditionalEntry), max: max}
+}
+
+// It returns the key of a request, empty if the request must not be conditional
+func conditionalKey(p *RequestParams, req *http.Request) string {
+	if !p.Conditional || req.Method != http.MethodGet {
+		return ""
+	}
+	scope, _ := req.Context().Value(conditionalScopeKey{}).(string)
+	if scope == "" {
+		return ""
+	}
+	return scope + "|" + p.RateLimitKey + "|" + req.URL.String()
+}
+
+// Apply adds the conditional headers of the key to the request
+func (c *ConditionalCache) Apply(key string, req *http.Request) {
+	if key == "" {
+		return
+	}
+	c.mutex.Lock()
+	entry, ok := c.entries[key]
+	c.mutex.Unlock()
+	if !ok {
+		return
+	}
+	if entry.ETag != "" {
+		req.Header.Set("If-None-Match", entry.ETag)
+	}
+	if entry.LastModified != "" {
+		req.Header.Set("If-Modified-Since", entry.LastModified)
+	}
+}
+
+// Store saves the validators of a successful response
+func (c *ConditionalCache) Store(key string, resp *http.Response) {
+	if key == "" || resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return
+	}
+	entry := ConditionalEntry{ETag: resp.Header.Get("ETag"), LastModified: resp.Header.Get("Last-Modified")}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if entry.ETag == "" && entry.LastModified == "" {
+		delete(c.entries, key)
+		return
+	}
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.max {
+		// The cache is only an optimization, the next requests are sent without validators
+		c.entries = make(map[string]ConditionalEntry)
+	}
+	c.entries[key] = entry
+}
+
+// Forget removes the validators of a scope, the next requests download the whole resources
+func (c *ConditionalCache) Forget(scope string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for key := range c.entries {
+		if strings.HasPrefix(key, scope+"|") {
+			delete(c.entries, key)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestConditionalRequests(t *testing.T) {
+	version := "v1"
+	full := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		etag := `"` + version + `"`
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		full++
+		w.Header().Set("ETag", etag)
+		fmt.Fprint(w, `{"version": "`+version+`"}`)
+	}))
+	defer server.Close()
+
+	rd := &RequestDescriptor{
+		BaseURL:        server.URL,
+		Params:         func(params []interface{}) *RequestParams { return &RequestParams{Method: "GET"} },
+		ExpectedStatus: []int{200},
+		Conditional:    true,
+	}
+	ctx := WithConditionalScope(context.Background(), "applet")
+	defer Validators.Forget("applet")
+
+	if _, _, err := rd.CallContext(ctx, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := rd.CallContext(ctx, nil); !errors.Is(err, ErrNotModified) {
+		t.Fatalf("expected ErrNotModified, got %v", err)
+	}
+
+	// The requests without scope (e.g. the routes) are never conditional
+	if _, _, err := rd.CallContext(context.Background(), nil); err != nil {
+		t.Fatal(err)
+	}
+	// Another applet has its own validators
+	if _, _, err := rd.CallContext(WithConditionalScope(context.Background(), "other"), nil); err != nil {
+		t.Fatal(err)
+	}
+	defer Validators.Forget("other")
+
+	version = "v2"
+	if resp, _, err := rd.CallContext(ctx, nil); err != nil || resp["version"] != "v2" {
+		t.Fatalf("expected the new version, got %v %v", resp, err)
+	}
+
+	Validators.Forget("applet")
+	if _, _, err := rd.CallContext(ctx, nil); err != nil {
+		t.Fatalf("the validators must be forgotten, got %v", err)
+	}
+	if full != 5 {
+		t.Errorf("expected 5 full responses, got %d", full)
+	}
+}
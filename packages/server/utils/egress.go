@@ -0,0 +1,290 @@
+package utils
+
+import (
+	"area-server/config"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Returned when the body of a response is bigger than `EgressPolicy.MaxResponseSize`
+var ErrResponseTooLarge = errors.New("Response body too large")
+
+// Ranges that are not covered by the methods of `net.IP` but must not be reached
+var reservedNetworks = mustParseCIDRs([]string{
+	"0.0.0.0/8",     // "This" network
+	"100.64.0.0/10", // Carrier-grade NAT
+	"192.0.0.0/24",  // IETF protocol assignments
+	"198.18.0.0/15", // Benchmarking
+	"64:ff9b::/96",  // IPv4/IPv6 translation
+})
+
+// `EgressError` is returned when a request is blocked by the egress policy.
+// @property {string} Host - The host of the request.
+// @property {string} IP - The address that was dialed (empty if the host is blocked).
+// @property {string} Reason - Why the request was blocked.
+type EgressError struct {
+	Host   string
+	IP     string
+	Reason string
+}
+
+// It returns the message of the error
+func (e *EgressError) Error() string {
+	if e.IP == "" {
+		return fmt.Sprintf("Egress: %s is blocked (%s)", e.Host, e.Reason)
+	}
+	return fmt.Sprintf("Egress: %s (%s) is blocked (%s)", e.Host, e.IP, e.Reason)
+}
+
+// `EgressPolicy` decides which hosts and addresses the server can reach. The addresses are checked
+// when the connection is dialed, so a host resolving to a private address after the validation (DNS
+// rebinding) is still blocked.
+// @property {bool} AllowPrivate - If true, the private, loopback and link-local addresses are allowed.
+// @property {int64} MaxResponseSize - The maximum size of a response body (0: unlimited).
+// @property {int} MaxRedirects - The maximum number of redirects followed by a request.
+type EgressPolicy struct {
+	AllowPrivate    bool
+	MaxResponseSize int64
+	MaxRedirects    int
+
+	allowHosts    []string
+	allowNetworks []*net.IPNet
+	denyHosts     []string
+	denyNetworks  []*net.IPNet
+}
+
+var (
+	egressMutex  sync.RWMutex
+	egressPolicy = mustEgressPolicy(config.CFG.Egress)
+)
+
+// It creates a policy from the configuration, the entries of the lists are hosts or CIDRs
+func NewEgressPolicy(cfg config.EgressConfig) (*EgressPolicy, error) {
+	policy := &EgressPolicy{
+		AllowPrivate:    cfg.AllowPrivate,
+		MaxResponseSize: cfg.MaxResponseSize,
+		MaxRedirects:    cfg.MaxRedirects,
+	}
+	var err error
+	if policy.allowHosts, policy.allowNetworks, err = parseEgressList(cfg.Allow); err != nil {
+		return nil, err
+	}
+	if policy.denyHosts, policy.denyNetworks, err = parseEgressList(cfg.Deny); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// It creates a policy from the configuration and panics if the configuration is invalid
+func mustEgressPolicy(cfg config.EgressConfig) *EgressPolicy {
+	policy, err := NewEgressPolicy(cfg)
+	if err != nil {
+		panic(err)
+	}
+	return policy
+}
+
+// It returns the current egress policy
+func Egress() *EgressPolicy {
+	egressMutex.RLock()
+	defer egressMutex.RUnlock()
+	return egressPolicy
+}
+
+// It replaces the egress policy (e.g. to reach a local server in the tests)
+func SetEgress(policy *EgressPolicy) {
+	egressMutex.Lock()
+	defer egressMutex.Unlock()
+	egressPolicy = policy
+}
+
+// It splits the entries between hosts and networks (CIDR or single address)
+func parseEgressList(entries []string) ([]string, []*net.IPNet, error) {
+	var hosts []string
+	var networks []*net.IPNet
+	for _, entry := range entries {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if strings.Contains(entry, "/") {
+			_, network, err := net.ParseCIDR(entry)
+			if err != nil {
+				return nil, nil, fmt.Errorf("Egress: invalid CIDR %q", entry)
+			}
+			networks = append(networks, network)
+		} else if ip := net.ParseIP(entry); ip != nil {
+			bits := 8 * len(ip.To16())
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 32
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+		} else {
+			hosts = append(hosts, strings.TrimSuffix(entry, "."))
+		}
+	}
+	return hosts, networks, nil
+}
+
+// It parses a list of CIDRs and panics if one of them is invalid
+func mustParseCIDRs(entries []string) []*net.IPNet {
+	_, networks, err := parseEgressList(entries)
+	if err != nil {
+		panic(err)
+	}
+	return networks
+}
+
+// It returns true if the host matches one of the patterns (`.example.com` matches the sub domains)
+func matchHost(host string, patterns []string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	for _, pattern := range patterns {
+		if host == pattern || (strings.HasPrefix(pattern, ".") && (strings.HasSuffix(host, pattern) || host == pattern[1:])) {
+			return true
+		}
+	}
+	return false
+}
+
+// It returns true if the address is in one of the networks
+func inNetworks(ip net.IP, networks []*net.IPNet) bool {
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// It returns true if the address is private, loopback, link-local (e.g. the cloud metadata) or reserved
+func IsInternalIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() || inNetworks(ip, reservedNetworks)
+}
+
+// CheckHost returns an error if the host is denied
+func (p *EgressPolicy) CheckHost(host string) error {
+	if matchHost(host, p.denyHosts) {
+		return &EgressError{Host: host, Reason: "denied host"}
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return p.CheckIP(host, ip)
+	}
+	return nil
+}
+
+// CheckIP returns an error if the address of the host can not be reached
+func (p *EgressPolicy) CheckIP(host string, ip net.IP) error {
+	if inNetworks(ip, p.denyNetworks) {
+		return &EgressError{Host: host, IP: ip.String(), Reason: "denied address"}
+	}
+	if p.AllowPrivate || matchHost(host, p.allowHosts) || inNetworks(ip, p.allowNetworks) {
+		return nil
+	}
+	if IsInternalIP(ip) {
+		return &EgressError{Host: host, IP: ip.String(), Reason: "internal address"}
+	}
+	return nil
+}
+
+// CheckURL returns an error if the URL can not be reached, the addresses of the host are only checked
+// when the request is sent
+func (p *EgressPolicy) CheckURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return &EgressError{Host: u.Host, Reason: "scheme not allowed"}
+	}
+	return p.CheckHost(u.Hostname())
+}
+
+// It dials the address after checking the host, the address actually dialed is checked by the
+// `Control` function of the dialer (after the resolution of the host)
+func egressDialContext(dialer *net.Dialer) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		policy := Egress()
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		if err := policy.CheckHost(host); err != nil {
+			log.Printf("[Egress] Blocked %s: %s", addr, err.Error())
+			return nil, err
+		}
+
+		d := *dialer
+		d.Control = func(network, address string, _ syscall.RawConn) error {
+			ipStr, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(ipStr)
+			if ip == nil {
+				return &EgressError{Host: host, IP: ipStr, Reason: "invalid address"}
+			}
+			if err := policy.CheckIP(host, ip); err != nil {
+				log.Printf("[Egress] Blocked %s: %s", addr, err.Error())
+				return err
+			}
+			return nil
+		}
+		return d.DialContext(ctx, network, addr)
+	}
+}
+
+// It stops the request after `MaxRedirects` redirects
+func egressCheckRedirect(req *http.Request, via []*http.Request) error {
+	if max := Egress().MaxRedirects; len(via) > max {
+		log.Printf("[Egress] Blocked redirect to %s: more than %d redirects", RedactURL(req.URL), max)
+		return fmt.Errorf("Egress: stopped after %d redirects", max)
+	}
+	return nil
+}
+
+// `limitedBody` returns `ErrResponseTooLarge` when more than `remaining` bytes are read.
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+}
+
+// It reads the body until the limit
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remaining < 0 {
+		return 0, ErrResponseTooLarge
+	}
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+	n, err := b.ReadCloser.Read(p)
+	b.remaining -= int64(n)
+	if b.remaining < 0 {
+		return n + int(b.remaining), ErrResponseTooLarge
+	}
+	return n, err
+}
+
+// It limits the size of the body of the response to `MaxResponseSize`
+func limitResponseBody(body io.ReadCloser) io.ReadCloser {
+	if max := Egress().MaxResponseSize; max > 0 {
+		return &limitedBody{ReadCloser: body, remaining: max}
+	}
+	return body
+}
+
+// The dialer of the shared client, the connections are checked by the egress policy
+var egressDialer = &net.Dialer{
+	Timeout:   10 * time.Second,
+	KeepAlive: 30 * time.Second,
+}
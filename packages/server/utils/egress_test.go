@@ -0,0 +1,114 @@
+package utils
+
+import (
+	"area-server/config"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+// The tests of the package reach a local server, the loopback address is allowed
+func TestMain(m *testing.M) {
+	SetEgress(mustEgressPolicy(config.EgressConfig{Allow: []string{"127.0.0.1"}, MaxResponseSize: 1 << 20, MaxRedirects: 5}))
+	os.Exit(m.Run())
+}
+
+// It replaces the policy for the duration of the test
+func useEgress(t *testing.T, cfg config.EgressConfig) {
+	previous := Egress()
+	SetEgress(mustEgressPolicy(cfg))
+	t.Cleanup(func() { SetEgress(previous) })
+}
+
+func TestIsInternalIP(t *testing.T) {
+	for _, addr := range []string{"127.0.0.1", "10.1.2.3", "172.17.0.2", "192.168.1.1", "169.254.169.254", "0.0.0.0", "100.64.0.1", "::1", "fe80::1", "fd00::1", "::ffff:127.0.0.1"} {
+		if !IsInternalIP(net.ParseIP(addr)) {
+			t.Errorf("%s should be internal", addr)
+		}
+	}
+	for _, addr := range []string{"8.8.8.8", "140.82.112.3", "2606:4700:4700::1111"} {
+		if IsInternalIP(net.ParseIP(addr)) {
+			t.Errorf("%s should be public", addr)
+		}
+	}
+}
+
+func TestEgressPolicyLists(t *testing.T) {
+	policy := mustEgressPolicy(config.EgressConfig{
+		Allow: []string{"internal.example.com", "10.0.0.5/32"},
+		Deny:  []string{".evil.com", "8.8.4.4"},
+	})
+
+	cases := map[string]bool{
+		"https://api.github.com/user":     true,
+		"https://internal.example.com/":   true,
+		"http://10.0.0.5:8080/hook":       true,
+		"http://10.0.0.6:8080/hook":       false,
+		"http://169.254.169.254/latest":   false,
+		"http://[::1]:6379/":              false,
+		"https://evil.com/":               false,
+		"https://api.evil.com/":           false,
+		"https://8.8.4.4/":                false,
+		"gopher://api.github.com/":        false,
+		"https://notevil.com/path?a=b":    true,
+		"https://internal.example.com.:1": true,
+	}
+	for raw, allowed := range cases {
+		err := policy.CheckURL(raw)
+		if (err == nil) != allowed {
+			t.Errorf("CheckURL(%q) = %v, expected allowed=%v", raw, err, allowed)
+		}
+	}
+
+	// Private addresses of an allowed host (resolved at dial time)
+	if err := policy.CheckIP("internal.example.com", net.ParseIP("10.9.9.9")); err != nil {
+		t.Errorf("the allowed host should reach its private address: %v", err)
+	}
+	if err := policy.CheckIP("rebind.example.org", net.ParseIP("127.0.0.1")); err == nil {
+		t.Error("a public host resolving to a loopback address must be blocked")
+	}
+}
+
+func TestEgressDial(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	useEgress(t, config.EgressConfig{})
+
+	// The name resolves to the loopback address, it is blocked when dialed
+	_, err := MakeRequest(strings.Replace(server.URL, "127.0.0.1", "localhost", 1), &RequestParams{Method: "GET"})
+	var egressErr *EgressError
+	if !errors.As(err, &egressErr) {
+		t.Fatalf("expected an EgressError, got %v", err)
+	}
+}
+
+func TestEgressRedirectsAndSize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/loop" {
+			http.Redirect(w, r, "/loop", http.StatusFound)
+			return
+		}
+		w.Write([]byte(strings.Repeat("a", 100)))
+	}))
+	defer server.Close()
+	useEgress(t, config.EgressConfig{Allow: []string{"127.0.0.1"}, MaxResponseSize: 64, MaxRedirects: 2})
+
+	if _, err := MakeRequest(server.URL+"/loop", &RequestParams{Method: "GET"}); err == nil || !strings.Contains(err.Error(), "redirects") {
+		t.Errorf("expected the redirects to be stopped, got %v", err)
+	}
+
+	resp, err := MakeRequest(server.URL+"/big", &RequestParams{Method: "GET"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if !errors.Is(err, ErrResponseTooLarge) || len(body) != 64 {
+		t.Errorf("expected the body to be cut at 64 bytes, got %d bytes and %v", len(body), err)
+	}
+}
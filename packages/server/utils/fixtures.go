@@ -0,0 +1,237 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+)
+
+// `FixtureMode` is the mode of a `FixtureTransport`.
+type FixtureMode int
+
+// Creating an enum.
+const (
+	FixtureReplay FixtureMode = iota // The responses are read from the fixture file
+	FixtureRecord                    // The requests are sent and the responses saved in the fixture file
+)
+
+// Environment variable used to record the fixtures instead of replaying them (AREA_FIXTURES=record)
+const FixtureModeEnv = "AREA_FIXTURES"
+
+// Regex used to scrub the secrets of the bodies (JSON and form encoded)
+var fixtureSecretRegex = regexp.MustCompile(`("(?:access_token|refresh_token|id_token|client_secret|token)"\s*:\s*)"[^"]*"|((?:access_token|refresh_token|client_secret|token)=)[^&]*`)
+
+// `FixtureRequest` is a request saved in a fixture file, the secrets are scrubbed.
+// @property {string} Method - The HTTP method of the request.
+// @property {string} URL - The URL of the request (see `RedactURL`).
+// @property {string} Body - The body of the request.
+type FixtureRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   string `json:"body,omitempty"`
+}
+
+// `FixtureResponse` is a response saved in a fixture file.
+// @property {int} Status - The status code of the response.
+// @property Headers - The headers of the response.
+// @property {string} Body - The body of the response.
+type FixtureResponse struct {
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body"`
+}
+
+// `Fixture` is a request and the response of the service.
+type Fixture struct {
+	Request  FixtureRequest  `json:"request"`
+	Response FixtureResponse `json:"response"`
+}
+
+// `FixtureTransport` is a `http.RoundTripper` that records the requests sent to the services or
+// replays them, so the services can be tested offline. In replay mode, the fixtures matching a request
+// are served in the order they were recorded (e.g. one per polling cycle).
+// @property {string} Path - The fixture file.
+// @property {FixtureMode} Mode - Record or replay.
+// @property Next - The transport used to send the requests in record mode.
+type FixtureTransport struct {
+	Path string
+	Mode FixtureMode
+	Next http.RoundTripper
+
+	mutex    sync.Mutex
+	fixtures []*Fixture
+	served   []bool
+}
+
+// It creates a fixture transport, in replay mode the fixture file is loaded
+func NewFixtureTransport(path string, mode FixtureMode, next http.RoundTripper) (*FixtureTransport, error) {
+	transport := &FixtureTransport{Path: path, Mode: mode, Next: next}
+	if mode == FixtureRecord {
+		return transport, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(content, &transport.fixtures); err != nil {
+		return nil, fmt.Errorf("Fixture %s: %s", path, err.Error())
+	}
+	transport.served = make([]bool, len(transport.fixtures))
+	return transport, nil
+}
+
+// It returns the scrubbed version of the request
+func fixtureRequest(req *http.Request) (FixtureRequest, error) {
+	fixture := FixtureRequest{Method: req.Method, URL: RedactURL(req.URL)}
+	if req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			return fixture, err
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		fixture.Body = ScrubSecrets(string(body))
+	}
+	return fixture, nil
+}
+
+// ScrubSecrets replaces the tokens / secrets of a body
+func ScrubSecrets(body string) string {
+	return fixtureSecretRegex.ReplaceAllStringFunc(body, func(match string) string {
+		groups := fixtureSecretRegex.FindStringSubmatch(match)
+		if groups[1] != "" {
+			return groups[1] + `"REDACTED"`
+		}
+		return groups[2] + "REDACTED"
+	})
+}
+
+// RoundTrip records or replays the request
+func (t *FixtureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	request, err := fixtureRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.Mode == FixtureRecord {
+		return t.record(req, request)
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	for i, fixture := range t.fixtures {
+		if t.served[i] || fixture.Request != request {
+			continue
+		}
+		t.served[i] = true
+
+		header := http.Header{}
+		for key, value := range fixture.Response.Headers {
+			header.Set(key, value)
+		}
+		return &http.Response{
+			Status:        http.StatusText(fixture.Response.Status),
+			StatusCode:    fixture.Response.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewBufferString(fixture.Response.Body)),
+			ContentLength: int64(len(fixture.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("Fixture %s: no response recorded for %s %s", filepath.Base(t.Path), request.Method, request.URL)
+}
+
+// It sends the request and saves the response
+func (t *FixtureTransport) record(req *http.Request, request FixtureRequest) (*http.Response, error) {
+	resp, err := t.Next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	headers := make(map[string]string)
+	for key := range resp.Header {
+		if !isSensitive(key) {
+			headers[key] = resp.Header.Get(key)
+		}
+	}
+
+	t.mutex.Lock()
+	t.fixtures = append(t.fixtures, &Fixture{
+		Request:  request,
+		Response: FixtureResponse{Status: resp.StatusCode, Headers: headers, Body: ScrubSecrets(string(body))},
+	})
+	t.mutex.Unlock()
+	return resp, nil
+}
+
+// Save writes the recorded fixtures in the fixture file
+func (t *FixtureTransport) Save() error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	content, err := json.MarshalIndent(t.fixtures, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(t.Path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(t.Path, content, 0644)
+}
+
+// Remaining returns the number of fixtures that were not served
+func (t *FixtureTransport) Remaining() int {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	remaining := 0
+	for _, served := range t.served {
+		if !served {
+			remaining++
+		}
+	}
+	return remaining
+}
+
+// UseFixtures injects a fixture transport in the shared `HTTPClient`, the fixtures are replayed unless
+// AREA_FIXTURES=record. The returned function restores the client (and saves the fixtures in record
+// mode).
+func UseFixtures(path string) (*FixtureTransport, func() error, error) {
+	mode := FixtureReplay
+	if os.Getenv(FixtureModeEnv) == "record" {
+		mode = FixtureRecord
+	}
+
+	previous := HTTPClient.Transport
+	transport, err := NewFixtureTransport(path, mode, previous)
+	if err != nil {
+		return nil, nil, err
+	}
+	HTTPClient.Transport = transport
+
+	return transport, func() error {
+		HTTPClient.Transport = previous
+		if mode == FixtureRecord {
+			return transport.Save()
+		}
+		return nil
+	}, nil
+}
@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestScrubSecrets(t *testing.T) {
+	cases := map[string]string{
+		`{"access_token": "abc", "scope": "read"}`:       `{"access_token": "REDACTED", "scope": "read"}`,
+		`grant_type=refresh_token&refresh_token=abc&x=1`: `grant_type=refresh_token&refresh_token=REDACTED&x=1`,
+		`{"name": "area"}`: `{"name": "area"}`,
+	}
+	for body, expected := range cases {
+		if got := ScrubSecrets(body); got != expected {
+			t.Errorf("ScrubSecrets(%q) = %q, expected %q", body, got, expected)
+		}
+	}
+}
+
+func TestFixtureRecordReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.Write([]byte(`{"access_token":"secret","name":"area"}`))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "fixture.json")
+	recorder, err := NewFixtureTransport(path, FixtureRecord, http.DefaultTransport)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &http.Client{Transport: recorder}
+	resp, err := client.Get(server.URL + "/me?access_token=secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.Contains(string(body), "secret") {
+		t.Fatal("the recorded response must not be altered")
+	}
+	if err := recorder.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	replayer, err := NewFixtureTransport(path, FixtureReplay, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client = &http.Client{Transport: replayer}
+	resp, err = client.Get(server.URL + "/me?access_token=other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ = io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if strings.Contains(string(body), "secret") || resp.Header.Get("Set-Cookie") != "" {
+		t.Errorf("secrets were saved in the fixture: %s", body)
+	}
+	if _, err := client.Get(server.URL + "/me"); err == nil {
+		t.Error("expected an error once the fixtures are exhausted")
+	}
+}
@@ -0,0 +1,92 @@
+package utils
+
+import (
+	"area-server/config"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Timeout applied to the requests that have no deadline
+const DefaultRequestTimeout = 30 * time.Second
+
+// HTTPClient is the client shared by all the requests sent to the services, it keeps the connections
+// alive between the calls. The connections and the redirects are checked by the egress policy (no
+// proxy is used, it would bypass the policy).
+var HTTPClient = &http.Client{
+	CheckRedirect: egressCheckRedirect,
+	Transport: &http.Transport{
+		DialContext:           egressDialContext(egressDialer),
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   10,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: 20 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	},
+}
+
+// Query parameters and headers whose value must never be logged
+var sensitiveKeys = []string{
+	"token", "secret", "password", "key", "code", "authorization", "signature", "appid", "cookie",
+}
+
+// It returns true if the name of the query parameter / header may contain a secret
+func isSensitive(name string) bool {
+	name = strings.ToLower(name)
+	for _, key := range sensitiveKeys {
+		if strings.Contains(name, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactURL returns the URL without the credentials and the secret query parameters
+func RedactURL(u *url.URL) string {
+	redacted := *u
+	if redacted.User != nil {
+		redacted.User = url.User("REDACTED")
+	}
+	query := redacted.Query()
+	for key := range query {
+		if isSensitive(key) {
+			query.Set(key, "REDACTED")
+		}
+	}
+	redacted.RawQuery = query.Encode()
+	return redacted.String()
+}
+
+// RedactHeaders returns the headers with the secret values replaced
+func RedactHeaders(headers http.Header) map[string]string {
+	redacted := make(map[string]string, len(headers))
+	for key := range headers {
+		if isSensitive(key) {
+			redacted[key] = "REDACTED"
+		} else {
+			redacted[key] = headers.Get(key)
+		}
+	}
+	return redacted
+}
+
+// It logs the request sent to a service (only in debug mode), the secrets are redacted
+func logRequest(req *http.Request, resp *http.Response, duration time.Duration, err error) {
+	if !config.CFG.Debug {
+		return
+	}
+
+	status := 0
+	if resp != nil {
+		status = resp.StatusCode
+	}
+	if err != nil {
+		log.Printf("[http] method=%s url=%q headers=%v duration=%s error=%q", req.Method, RedactURL(req.URL), RedactHeaders(req.Header), duration, err.Error())
+		return
+	}
+	log.Printf("[http] method=%s url=%q headers=%v status=%d duration=%s", req.Method, RedactURL(req.URL), RedactHeaders(req.Header), status, duration)
+}
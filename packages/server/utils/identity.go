@@ -0,0 +1,174 @@
+package utils
+
+import (
+	"area-server/db/postgres"
+	"area-server/db/postgres/models"
+	"errors"
+	"strconv"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Suffix added to the labels and names of the source account that are already used by the target
+const mergedSuffix = "-merged"
+
+var ErrMergeSameAccount = errors.New("Cannot merge an account into itself")
+
+// FindLoginAccount returns the account that logs in with the authenticator and the email returned by
+// the provider: the account linked with an identity, else the account created with the authenticator
+// (gorm.ErrRecordNotFound if there is none)
+func FindLoginAccount(authenticator string, email string) (*models.Account, error) {
+	var identity models.Identity
+	if result := postgres.DB.Where(&models.Identity{Authenticator: authenticator, Email: email}).Preload("Account").First(&identity); result.Error == nil {
+		return &identity.Account, nil
+	} else if result.Error != gorm.ErrRecordNotFound {
+		return nil, result.Error
+	}
+
+	var account models.Account
+	if result := postgres.DB.Where(&models.Account{Authenticator: authenticator, Email: email}).First(&account); result.Error != nil {
+		return nil, result.Error
+	}
+	return &account, nil
+}
+
+// Maximum length of the label of an authorization
+const maxLabelLength = 32
+
+// IdentityLabel returns the label of the authorization created for a linked login: the printable ASCII
+// characters of its email (at most 32), with a number if the label is taken. The returned label is
+// added to `taken`.
+func IdentityLabel(email string, taken map[string]bool) string {
+	base := make([]byte, 0, len(email))
+	for i := 0; i < len(email); i++ {
+		if email[i] >= 0x20 && email[i] <= 0x7e {
+			base = append(base, email[i])
+		}
+	}
+	if len(base) == 0 {
+		base = []byte("linked")
+	}
+
+	label := ""
+	for i := 1; label == "" || taken[label]; i++ {
+		suffix := ""
+		if i > 1 {
+			suffix = "-" + strconv.Itoa(i)
+		}
+		prefix := base
+		if len(prefix)+len(suffix) > maxLabelLength {
+			prefix = prefix[:maxLabelLength-len(suffix)]
+		}
+		label = string(prefix) + suffix
+	}
+	taken[label] = true
+	return label
+}
+
+// UniqueName returns the name if it is not taken, else the name with the merged suffix (and a number
+// if the suffixed name is taken too). The returned name is added to `taken`.
+func UniqueName(name string, taken map[string]bool) string {
+	unique := name
+	for i := 1; taken[unique]; i++ {
+		unique = name + mergedSuffix
+		if i > 1 {
+			unique += "-" + strconv.Itoa(i)
+		}
+	}
+	taken[unique] = true
+	return unique
+}
+
+// MergeAccounts moves the applets (with their areas), the authorizations, the API keys and the
+// identities of the source account to the target account, then deletes the source account.
+// The applets and authorizations whose name or label is already used by the target are renamed, the
+// authorizations of the source are not permanent anymore, and the external login of the source is
+// kept as an identity of the target. Everything is done in one transaction.
+func MergeAccounts(sourceUUID uuid.UUID, targetUUID uuid.UUID) error {
+	if sourceUUID == targetUUID {
+		return ErrMergeSameAccount
+	}
+
+	return postgres.DB.Transaction(func(tx *gorm.DB) error {
+		var source, target models.Account
+		if result := tx.Where(&models.Account{UUID: sourceUUID}).First(&source); result.Error != nil {
+			return result.Error
+		}
+		if result := tx.Where(&models.Account{UUID: targetUUID}).First(&target); result.Error != nil {
+			return result.Error
+		}
+
+		// Applets (the areas follow their applet)
+		var targetApplets, sourceApplets []models.Applet
+		if result := tx.Where(&models.Applet{AccountUUID: target.UUID}).Find(&targetApplets); result.Error != nil {
+			return result.Error
+		}
+		if result := tx.Where(&models.Applet{AccountUUID: source.UUID}).Find(&sourceApplets); result.Error != nil {
+			return result.Error
+		}
+		names := make(map[string]bool)
+		for _, applet := range targetApplets {
+			names[applet.Name] = true
+		}
+		for _, applet := range sourceApplets {
+			if result := tx.Model(&applet).Updates(map[string]interface{}{
+				"account_uuid": target.UUID,
+				"name":         UniqueName(applet.Name, names),
+			}); result.Error != nil {
+				return result.Error
+			}
+		}
+
+		// Authorizations (the label is unique per account and service)
+		var targetAuthorizations, sourceAuthorizations []models.Authorization
+		if result := tx.Where(&models.Authorization{AccountUUID: target.UUID}).Find(&targetAuthorizations); result.Error != nil {
+			return result.Error
+		}
+		if result := tx.Where(&models.Authorization{AccountUUID: source.UUID}).Find(&sourceAuthorizations); result.Error != nil {
+			return result.Error
+		}
+		labels := make(map[string]map[string]bool)
+		for _, authorization := range targetAuthorizations {
+			if labels[authorization.AuthService] == nil {
+				labels[authorization.AuthService] = make(map[string]bool)
+			}
+			labels[authorization.AuthService][authorization.Label] = true
+		}
+		for _, authorization := range sourceAuthorizations {
+			if labels[authorization.AuthService] == nil {
+				labels[authorization.AuthService] = make(map[string]bool)
+			}
+			if result := tx.Model(&authorization).Updates(map[string]interface{}{
+				"account_uuid": target.UUID,
+				"label":        UniqueName(authorization.Label, labels[authorization.AuthService]),
+				"permanent":    false,
+			}); result.Error != nil {
+				return result.Error
+			}
+		}
+
+		// API keys and identities
+		if result := tx.Model(&models.APIKey{}).Where("account_uuid = ?", source.UUID).Update("account_uuid", target.UUID); result.Error != nil {
+			return result.Error
+		}
+		if result := tx.Model(&models.Identity{}).Where("account_uuid = ?", source.UUID).Update("account_uuid", target.UUID); result.Error != nil {
+			return result.Error
+		}
+
+		// The external login of the source account logs in to the target
+		if source.Authenticator != "@local" && (source.Authenticator != target.Authenticator || source.Email != target.Email) {
+			if result := tx.Create(&models.Identity{
+				UUID:          uuid.New(),
+				AccountUUID:   target.UUID,
+				Authenticator: source.Authenticator,
+				Email:         source.Email,
+			}); result.Error != nil {
+				return result.Error
+			}
+		}
+
+		// The two-factor authentication, the sessions and the tokens of the source are deleted with it
+		return tx.Delete(&source).Error
+	})
+}
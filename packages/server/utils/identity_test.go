@@ -0,0 +1,49 @@
+package utils
+
+import "testing"
+
+func TestUniqueName(t *testing.T) {
+	taken := map[string]bool{"default": true, "bot": true, "bot-merged": true}
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"personal", "personal"},
+		{"default", "default-merged"},
+		{"bot", "bot-merged-2"},
+		{"bot", "bot-merged-3"},
+		{"personal", "personal-merged"},
+	}
+	for _, tt := range tests {
+		if got := UniqueName(tt.name, taken); got != tt.want {
+			t.Errorf("UniqueName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestIdentityLabel(t *testing.T) {
+	taken := map[string]bool{"default": true, "john@example.com": true}
+
+	tests := []struct {
+		email string
+		want  string
+	}{
+		{"jane@example.com", "jane@example.com"},
+		{"john@example.com", "john@example.com-2"},
+		{"john@example.com", "john@example.com-3"},
+		{"a.very.long.address@subdomain.example.com", "a.very.long.address@subdomain.ex"},
+		{"a.very.long.address@subdomain.example.com", "a.very.long.address@subdomain.-2"},
+		{"jé@example.com", "j@example.com"},
+		{"", "linked"},
+	}
+	for _, tt := range tests {
+		got := IdentityLabel(tt.email, taken)
+		if got != tt.want {
+			t.Errorf("IdentityLabel(%q) = %q, want %q", tt.email, got, tt.want)
+		}
+		if len(got) > 32 {
+			t.Errorf("IdentityLabel(%q) = %q is longer than 32", tt.email, got)
+		}
+	}
+}
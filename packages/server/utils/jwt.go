@@ -4,14 +4,109 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
 )
 
-// It takes a map of keys and values, and an expiration time in seconds, and returns a JWT token
+// ID of the key read from JWT_SECRET (also used for the tokens signed without `kid`)
+const DefaultJWTKeyID = "default"
+
+// `jwtKeyring` contains the signing keys of the tokens, indexed by their ID (`kid` header).
+// @property {string} active - The ID of the key signing the new tokens.
+type jwtKeyring struct {
+	active string
+	keys   map[string][]byte
+}
+
+// The signing keys, loaded from the environment on the first use
+var (
+	jwtKeys     *jwtKeyring
+	jwtKeysLock sync.RWMutex
+)
+
+// It loads the signing keys: JWT_KEYS="<id>:<secret>,<id>:<secret>" and / or JWT_SECRET (ID
+// "default"). The active key is JWT_KEY_ID, the first key of JWT_KEYS or JWT_SECRET. To rotate the
+// key, add a new key, make it active and restart the server: the tokens signed with the previous one
+// stay valid until it is removed.
+func loadJWTKeys() (*jwtKeyring, error) {
+	k := &jwtKeyring{keys: make(map[string][]byte)}
+	for _, entry := range strings.Split(os.Getenv("JWT_KEYS"), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		id, secret, ok := strings.Cut(entry, ":")
+		if !ok || id == "" || secret == "" {
+			return nil, errors.New("Invalid JWT_KEYS entry (expected <id>:<secret>)")
+		}
+		k.keys[id] = []byte(secret)
+		if k.active == "" {
+			k.active = id
+		}
+	}
+	if secret, present := os.LookupEnv("JWT_SECRET"); present && secret != "" {
+		if _, ok := k.keys[DefaultJWTKeyID]; !ok {
+			k.keys[DefaultJWTKeyID] = []byte(secret)
+		}
+		if k.active == "" {
+			k.active = DefaultJWTKeyID
+		}
+	}
+	if active := os.Getenv("JWT_KEY_ID"); active != "" {
+		k.active = active
+	}
+
+	if len(k.keys) == 0 {
+		return nil, errors.New("You must precise a secret key")
+	}
+	if _, ok := k.keys[k.active]; !ok {
+		return nil, fmt.Errorf("Unknown JWT key %q", k.active)
+	}
+	return k, nil
+}
+
+// It returns the signing keys, they are loaded from the environment on the first call only (a rotation
+// needs a restart)
+func currentJWTKeys() (*jwtKeyring, error) {
+	jwtKeysLock.RLock()
+	keyring := jwtKeys
+	jwtKeysLock.RUnlock()
+	if keyring != nil {
+		return keyring, nil
+	}
+	return reloadJWTKeys()
+}
+
+// It loads the signing keys from the environment again
+func reloadJWTKeys() (*jwtKeyring, error) {
+	keyring, err := loadJWTKeys()
+	if err != nil {
+		return nil, err
+	}
+	jwtKeysLock.Lock()
+	jwtKeys = keyring
+	jwtKeysLock.Unlock()
+	return keyring, nil
+}
+
+// GenerateTokenID returns a random ID for a token (`jti` claim)
+func GenerateTokenID() (string, error) {
+	return randomURLSafe(16)
+}
+
+// It takes a map of keys and values, and an expiration time in seconds, and returns a JWT token signed
+// with the active key (its ID is in the `kid` header)
 func GenerateJWT(keys map[string]interface{}, exp int) (string, error) {
+	keyring, err := currentJWTKeys()
+	if err != nil {
+		return "", err
+	}
+
 	token := jwt.New(jwt.SigningMethodHS256)
+	token.Header["kid"] = keyring.active
 
 	claims := token.Claims.(jwt.MapClaims)
 
@@ -19,17 +114,13 @@ func GenerateJWT(keys map[string]interface{}, exp int) (string, error) {
 		claims[key] = keys[key]
 	}
 
+	now := time.Now()
+	claims["iat"] = now.Unix()
 	if exp > 0 {
-		claims["exp"] = time.Now().Add(time.Second * time.Duration(exp)).Unix()
+		claims["exp"] = now.Add(time.Second * time.Duration(exp)).Unix()
 	}
 
-	secret, present := os.LookupEnv("JWT_SECRET")
-
-	if !present {
-		return "", errors.New("You must precise a secret key")
-	}
-
-	t, err := token.SignedString([]byte(secret))
+	t, err := token.SignedString(keyring.keys[keyring.active])
 	if err != nil {
 		return "", err
 	}
@@ -39,17 +130,24 @@ func GenerateJWT(keys map[string]interface{}, exp int) (string, error) {
 // It takes a token string, and returns a map of claims and an error
 func ValidateJWT(tokenString string) (map[string]interface{}, error) {
 
-	secret, present := os.LookupEnv("JWT_SECRET")
-
-	if !present {
-		return nil, errors.New("You must precise a secret key")
+	keyring, err := currentJWTKeys()
+	if err != nil {
+		return nil, err
 	}
 
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("Unexpected signing method: %v", token.Header["alg"])
 		}
-		return []byte(secret), nil
+		kid, _ := token.Header["kid"].(string)
+		if kid == "" {
+			kid = DefaultJWTKeyID
+		}
+		key, ok := keyring.keys[kid]
+		if !ok {
+			return nil, fmt.Errorf("Unknown signing key: %s", kid)
+		}
+		return key, nil
 	})
 
 	if err != nil {
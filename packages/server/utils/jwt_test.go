@@ -0,0 +1,105 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// It returns the `kid` header of a token
+func tokenKeyID(t *testing.T, token string) string {
+	parsed, _, err := new(jwt.Parser).ParseUnverified(token, jwt.MapClaims{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	kid, _ := parsed.Header["kid"].(string)
+	return kid
+}
+
+func TestJWTKeyRotation(t *testing.T) {
+	t.Setenv("JWT_SECRET", "legacy-secret")
+	t.Setenv("JWT_KEYS", "")
+	t.Setenv("JWT_KEY_ID", "")
+	if _, err := reloadJWTKeys(); err != nil {
+		t.Fatal(err)
+	}
+
+	legacy, err := GenerateJWT(map[string]interface{}{"accountID": "a"}, 60)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kid := tokenKeyID(t, legacy); kid != DefaultJWTKeyID {
+		t.Errorf("expected the default key, got %q", kid)
+	}
+
+	// A new key is added and becomes active, the previous tokens are still valid
+	t.Setenv("JWT_KEYS", "2023-02:new-secret")
+	if _, err := reloadJWTKeys(); err != nil {
+		t.Fatal(err)
+	}
+	rotated, err := GenerateJWT(map[string]interface{}{"accountID": "a"}, 60)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kid := tokenKeyID(t, rotated); kid != "2023-02" {
+		t.Errorf("expected the new key, got %q", kid)
+	}
+	for _, token := range []string{legacy, rotated} {
+		if claims, err := ValidateJWT(token); err != nil || claims["accountID"] != "a" || claims["iat"] == nil {
+			t.Errorf("expected the token to be valid, got %v %v", claims, err)
+		}
+	}
+
+	// The previous key is removed
+	t.Setenv("JWT_SECRET", "")
+	if _, err := reloadJWTKeys(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ValidateJWT(legacy); err == nil {
+		t.Error("expected the token signed with the removed key to be refused")
+	}
+
+	// A token signed with another secret
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"accountID": "b"})
+	forged.Header["kid"] = "2023-02"
+	signed, _ := forged.SignedString([]byte("guess"))
+	if _, err := ValidateJWT(signed); err == nil || !strings.Contains(err.Error(), "signature") {
+		t.Errorf("expected an invalid signature, got %v", err)
+	}
+}
+
+func TestJWTInvalidKeys(t *testing.T) {
+	t.Setenv("JWT_SECRET", "")
+	t.Setenv("JWT_KEYS", "")
+	if _, err := reloadJWTKeys(); err == nil {
+		t.Error("expected an error without key")
+	}
+	t.Setenv("JWT_KEYS", "k1:secret")
+	t.Setenv("JWT_KEY_ID", "missing")
+	if _, err := reloadJWTKeys(); err == nil {
+		t.Error("expected an error when the active key is missing")
+	}
+}
+
+func TestJWTKeysLoadedOnce(t *testing.T) {
+	t.Setenv("JWT_SECRET", "")
+	t.Setenv("JWT_KEYS", "k1:secret")
+	t.Setenv("JWT_KEY_ID", "")
+	if _, err := reloadJWTKeys(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The environment is not read again
+	t.Setenv("JWT_KEYS", "k2:other")
+	token, err := GenerateJWT(map[string]interface{}{"accountID": "a"}, 60)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kid := tokenKeyID(t, token); kid != "k1" {
+		t.Errorf("expected the loaded key, got %q", kid)
+	}
+	if _, err := ValidateJWT(token); err != nil {
+		t.Errorf("expected the token to be valid, got %v", err)
+	}
+}
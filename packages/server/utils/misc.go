@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 )
 
 // Name, Count
@@ -114,15 +115,31 @@ func GenerateFinalComponent(content string, data map[string]interface{}, allowed
 	return content
 }
 
-// It takes a Fiber context and an auth service name, and returns an authorization object and an error
+// FindAuthorization returns the authorization of the account for the service with the given label,
+// the oldest one if the label is empty
+func FindAuthorization(accountUUID uuid.UUID, authService string, label string) (*models.Authorization, error) {
+	query := postgres.DB.Where(&models.Authorization{AccountUUID: accountUUID, AuthService: authService})
+	if label != "" {
+		query = query.Where("label = ?", label)
+	}
+
+	var authorization models.Authorization
+	if result := query.Order("created_at").First(&authorization); result.Error != nil {
+		return nil, result.Error
+	}
+	return &authorization, nil
+}
+
+// It takes a Fiber context and an auth service name, and returns an authorization object and an error.
+// The authorization is selected by its label with the `authorization` query parameter (or local).
 func VerifyRoute(c *fiber.Ctx, authService string) (*models.Authorization, error) {
 
 	account := c.Locals("account").(models.Account)
 
-	var authorization models.Authorization
-	if result := postgres.DB.Where(&models.Authorization{AccountUUID: account.UUID, AuthService: authService}).First(&authorization); result.Error != nil {
-		return nil, result.Error
+	label := c.Query("authorization")
+	if label == "" {
+		label, _ = c.Locals("authorization").(string)
 	}
 
-	return &authorization, nil
+	return FindAuthorization(account.UUID, authService, label)
 }
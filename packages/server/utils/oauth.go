@@ -0,0 +1,111 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"net/url"
+	"strings"
+)
+
+// It returns a random string encoded in base64 (url safe, without padding)
+func randomURLSafe(size int) (string, error) {
+	buffer := make([]byte, size)
+	if _, err := rand.Read(buffer); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buffer), nil
+}
+
+// GenerateOAuthState returns a random, unguessable `state` for an OAuth2 flow
+func GenerateOAuthState() (string, error) {
+	return randomURLSafe(32)
+}
+
+// GeneratePKCEVerifier returns a random PKCE code verifier (43 characters, RFC 7636)
+func GeneratePKCEVerifier() (string, error) {
+	return randomURLSafe(32)
+}
+
+// PKCEChallenge returns the S256 code challenge of a verifier
+func PKCEChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// AuthorizationURL adds the state, the redirect URI and the PKCE challenge (if not empty) to the
+// authorization URI of an authenticator
+func AuthorizationURL(authorizationURI string, state string, redirectURI string, challenge string) (string, error) {
+	u, err := url.Parse(authorizationURI)
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	query.Set("state", state)
+	if redirectURI != "" {
+		query.Set("redirect_uri", redirectURI)
+	}
+	if challenge != "" {
+		query.Set("code_challenge", challenge)
+		query.Set("code_challenge_method", "S256")
+	}
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// WithCodeVerifier wraps the params of the access token request to send the PKCE code verifier, in the
+// body (form or JSON) or in the query depending on the request
+func WithCodeVerifier(paramsFn func(params []interface{}) *RequestParams, verifier string) func(params []interface{}) *RequestParams {
+	return func(params []interface{}) *RequestParams {
+		p := paramsFn(params)
+		if p == nil || verifier == "" {
+			return p
+		}
+
+		contentType := ""
+		for key, value := range p.Headers {
+			if strings.EqualFold(key, "Content-Type") {
+				contentType = value
+			}
+		}
+
+		switch {
+		case p.Body == "" && p.QueryParams != nil:
+			p.QueryParams["code_verifier"] = verifier
+		case strings.HasPrefix(contentType, "application/json"):
+			body := make(map[string]interface{})
+			if p.Body != "" {
+				if err := json.Unmarshal([]byte(p.Body), &body); err != nil {
+					return p
+				}
+			}
+			body["code_verifier"] = verifier
+			encoded, err := json.Marshal(body)
+			if err != nil {
+				return p
+			}
+			p.Body = string(encoded)
+		default:
+			values, err := url.ParseQuery(p.Body)
+			if err != nil {
+				return p
+			}
+			values.Set("code_verifier", verifier)
+			p.Body = values.Encode()
+		}
+		return p
+	}
+}
+
+// GenerateCSRFToken returns a random, unguessable CSRF token for a session
+func GenerateCSRFToken() (string, error) {
+	return randomURLSafe(32)
+}
+
+// CheckCSRFToken returns true if the token sent by the client is the token of the session (compared in
+// constant time)
+func CheckCSRFToken(expected string, token string) bool {
+	return expected != "" && subtle.ConstantTimeCompare([]byte(expected), []byte(token)) == 1
+}
@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+)
+
+func TestPKCEChallenge(t *testing.T) {
+	// Example of the RFC 7636 (Appendix B)
+	if challenge := PKCEChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"); challenge != "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM" {
+		t.Errorf("unexpected challenge %q", challenge)
+	}
+
+	verifier, err := GeneratePKCEVerifier()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(verifier) < 43 || len(verifier) > 128 {
+		t.Errorf("the verifier must have between 43 and 128 characters, got %d", len(verifier))
+	}
+	if state, _ := GenerateOAuthState(); state == verifier || state == "" {
+		t.Error("the states must be random")
+	}
+}
+
+func TestAuthorizationURL(t *testing.T) {
+	raw, err := AuthorizationURL("https://provider.test/authorize?client_id=id&state=static", "one-time", "http://localhost:8081", "challenge")
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, _ := url.Parse(raw)
+	query := u.Query()
+	if query.Get("client_id") != "id" || query.Get("state") != "one-time" || query.Get("redirect_uri") != "http://localhost:8081" {
+		t.Errorf("unexpected query %v", query)
+	}
+	if query.Get("code_challenge") != "challenge" || query.Get("code_challenge_method") != "S256" {
+		t.Errorf("expected the PKCE challenge, got %v", query)
+	}
+
+	raw, _ = AuthorizationURL("https://provider.test/authorize", "one-time", "", "")
+	if u, _ := url.Parse(raw); u.Query().Has("code_challenge") || u.Query().Has("redirect_uri") {
+		t.Errorf("unexpected query %v", u.Query())
+	}
+}
+
+func TestWithCodeVerifier(t *testing.T) {
+	form := WithCodeVerifier(func(params []interface{}) *RequestParams {
+		return &RequestParams{
+			Headers: map[string]string{"Content-Type": "application/x-www-form-urlencoded"},
+			Body:    url.Values{"code": {params[0].(string)}}.Encode(),
+		}
+	}, "verifier")([]interface{}{"abc"})
+	if values, _ := url.ParseQuery(form.Body); values.Get("code") != "abc" || values.Get("code_verifier") != "verifier" {
+		t.Errorf("unexpected form body %q", form.Body)
+	}
+
+	query := WithCodeVerifier(func(params []interface{}) *RequestParams {
+		return &RequestParams{QueryParams: map[string]string{"code": "abc"}}
+	}, "verifier")(nil)
+	if query.QueryParams["code_verifier"] != "verifier" || query.Body != "" {
+		t.Errorf("expected the verifier in the query, got %v", query.QueryParams)
+	}
+
+	body := WithCodeVerifier(func(params []interface{}) *RequestParams {
+		return &RequestParams{Headers: map[string]string{"Content-Type": "application/json"}, Body: `{"code":"abc"}`}
+	}, "verifier")(nil)
+	decoded := map[string]string{}
+	if err := json.Unmarshal([]byte(body.Body), &decoded); err != nil || decoded["code_verifier"] != "verifier" || decoded["code"] != "abc" {
+		t.Errorf("unexpected JSON body %q", body.Body)
+	}
+}
+
+func TestCheckCSRFToken(t *testing.T) {
+	token, err := GenerateCSRFToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !CheckCSRFToken(token, token) {
+		t.Error("expected the token of the session to be accepted")
+	}
+	if CheckCSRFToken(token, token[1:]) || CheckCSRFToken(token, "") {
+		t.Error("expected another token to be rejected")
+	}
+	if CheckCSRFToken("", "") {
+		t.Error("expected a session without token to reject the requests")
+	}
+}
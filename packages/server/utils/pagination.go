@@ -0,0 +1,263 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// `PaginationType` is the way the pages of an endpoint are requested.
+type PaginationType int
+
+// Creating an enum.
+const (
+	PaginationLink    PaginationType = iota + 1 // The next page is in the `Link` header (rel="next"), e.g. Github
+	PaginationCursor                            // The body contains the token of the next page, sent in the query (e.g. `after`, `pageToken`)
+	PaginationOffset                            // The pages are requested with an offset and a limit in the query
+	PaginationHasMore                           // The body contains a cursor and a `has_more` flag, the cursor is posted to `ContinueURL` (e.g. Dropbox)
+)
+
+// Maximum number of pages requested by an iterator if not set in the `Pagination`
+const DefaultMaxPages = 10
+
+// Regex used to find the next page of a `Link` header
+var linkNextRegex = regexp.MustCompile(`<([^>]+)>\s*;\s*rel="?next"?`)
+
+// `Pagination` describes how the pages of an endpoint are requested.
+// @property {PaginationType} Type - The type of pagination.
+// @property {string} Items - The path of the items in the body, the keys are separated by `.` (e.g.
+// `data.children`), empty if the body is the list of items.
+// @property {string} Cursor - The path of the next cursor in the body (cursor and has_more).
+// @property {string} HasMore - The path of the `has_more` flag in the body (has_more).
+// @property {string} Param - The query parameter of the cursor (cursor) or of the offset (offset).
+// @property {string} LimitParam - The query parameter of the size of the page (offset).
+// @property {int} Limit - The size of a page (offset), a page smaller than the limit is the last one.
+// @property {string} ContinueURL - The URL of the next pages (has_more).
+// @property {int} MaxPages - The maximum number of pages requested (default: DefaultMaxPages).
+type Pagination struct {
+	Type        PaginationType
+	Items       string
+	Cursor      string
+	HasMore     string
+	Param       string
+	LimitParam  string
+	Limit       int
+	ContinueURL string
+	MaxPages    int
+}
+
+// `PageIterator` requests the pages of an endpoint one by one.
+//
+//	it := endpoint.Paginate(ctx, params)
+//	for it.Next() {
+//		for _, item := range it.Items() { ... }
+//	}
+//	if it.Err() != nil { ... }
+type PageIterator struct {
+	descriptor *RequestDescriptor
+	pagination Pagination
+	baseURL    string
+	params     *RequestParams
+	pages      int
+	offset     int
+	cursor     string
+	done       bool
+	items      []json.RawMessage
+	response   *http.Response
+	err        error
+}
+
+// Paginate returns an iterator over the pages of the endpoint, the descriptor must have a `Pagination`.
+func (rd *RequestDescriptor) Paginate(ctx context.Context, params []interface{}) *PageIterator {
+	it := &PageIterator{descriptor: rd, baseURL: rd.BaseURL, params: rd.params(ctx, params)}
+	if rd.Pagination == nil {
+		it.err = fmt.Errorf("%s is not paginated", rd.BaseURL)
+		return it
+	}
+	it.pagination = *rd.Pagination
+	if it.pagination.MaxPages <= 0 {
+		it.pagination.MaxPages = DefaultMaxPages
+	}
+	if it.params == nil {
+		it.err = errors.New("Request params is nil")
+		return it
+	}
+
+	// The params are modified between the pages
+	query := make(map[string]string, len(it.params.QueryParams))
+	for key, value := range it.params.QueryParams {
+		query[key] = value
+	}
+	it.params.QueryParams = query
+
+	if it.pagination.Type == PaginationOffset {
+		if it.pagination.LimitParam != "" && it.pagination.Limit > 0 {
+			query[it.pagination.LimitParam] = strconv.Itoa(it.pagination.Limit)
+		}
+		if value, ok := query[it.pagination.Param]; ok {
+			it.offset, _ = strconv.Atoi(value)
+		}
+	}
+	return it
+}
+
+// Next requests the next page, it returns false when there are no more pages or an error occurred.
+func (it *PageIterator) Next() bool {
+	if it.err != nil || it.done {
+		return false
+	}
+	if it.pages >= it.pagination.MaxPages {
+		it.done = true
+		return false
+	}
+
+	_, body, resp, err := DoRequest(it.baseURL, it.params, it.descriptor.ExpectedStatus, false)
+	if err != nil {
+		it.err = err
+		return false
+	}
+	it.pages++
+	it.response = resp
+
+	var decoded interface{}
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		it.err = err
+		return false
+	}
+	items, err := pageItems(decoded, it.pagination.Items)
+	if err != nil {
+		it.err = err
+		return false
+	}
+	it.items = items
+
+	it.done = !it.prepareNext(decoded, resp)
+	return true
+}
+
+// It prepares the request of the next page, it returns false if the current page is the last one
+func (it *PageIterator) prepareNext(body interface{}, resp *http.Response) bool {
+	switch it.pagination.Type {
+	case PaginationLink:
+		next := NextLink(resp.Header.Get("Link"))
+		if next == nil {
+			return false
+		}
+		query := make(map[string]string)
+		for key := range next.Query() {
+			query[key] = next.Query().Get(key)
+		}
+		next.RawQuery = ""
+		it.baseURL = next.String()
+		it.params.UrlParams = nil
+		it.params.QueryParams = query
+		return true
+
+	case PaginationCursor:
+		cursor, _ := lookupPath(body, it.pagination.Cursor).(string)
+		if cursor == "" || len(it.items) == 0 {
+			return false
+		}
+		it.cursor = cursor
+		it.params.QueryParams[it.pagination.Param] = cursor
+		return true
+
+	case PaginationOffset:
+		it.offset += len(it.items)
+		if len(it.items) == 0 || (it.pagination.Limit > 0 && len(it.items) < it.pagination.Limit) {
+			return false
+		}
+		it.params.QueryParams[it.pagination.Param] = strconv.Itoa(it.offset)
+		return true
+
+	case PaginationHasMore:
+		if cursor, ok := lookupPath(body, it.pagination.Cursor).(string); ok {
+			it.cursor = cursor
+		}
+		hasMore, _ := lookupPath(body, it.pagination.HasMore).(bool)
+		if !hasMore || it.cursor == "" {
+			return false
+		}
+		encoded, _ := json.Marshal(map[string]string{"cursor": it.cursor})
+		it.baseURL = it.pagination.ContinueURL
+		it.params.Method = http.MethodPost
+		it.params.Body = string(encoded)
+		return true
+	}
+	return false
+}
+
+// Items returns the items of the current page.
+func (it *PageIterator) Items() []json.RawMessage {
+	return it.items
+}
+
+// Response returns the response of the current page, its body is already read.
+func (it *PageIterator) Response() *http.Response {
+	return it.response
+}
+
+// Cursor returns the last cursor returned by the service (cursor and has_more), it can be saved to
+// resume the iteration later.
+func (it *PageIterator) Cursor() string {
+	return it.cursor
+}
+
+// Err returns the error that stopped the iteration.
+func (it *PageIterator) Err() error {
+	return it.err
+}
+
+// NextLink returns the URL of the next page of a `Link` header, nil if it is the last page.
+func NextLink(header string) *url.URL {
+	match := linkNextRegex.FindStringSubmatch(header)
+	if match == nil {
+		return nil
+	}
+	next, err := url.Parse(match[1])
+	if err != nil {
+		return nil
+	}
+	return next
+}
+
+// It returns the items at the path of the body
+func pageItems(body interface{}, path string) ([]json.RawMessage, error) {
+	list, ok := lookupPath(body, path).([]interface{})
+	if !ok {
+		if lookupPath(body, path) == nil {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("Pagination: %q is not a list", path)
+	}
+	items := make([]json.RawMessage, 0, len(list))
+	for _, item := range list {
+		encoded, err := json.Marshal(item)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, encoded)
+	}
+	return items, nil
+}
+
+// It returns the value at the path (keys separated by `.`) of a decoded JSON body
+func lookupPath(body interface{}, path string) interface{} {
+	if path == "" {
+		return body
+	}
+	for _, key := range strings.Split(path, ".") {
+		object, ok := body.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		body = object[key]
+	}
+	return body
+}
@@ -0,0 +1,218 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+// It returns the values of the items of all the pages
+func collectPages(t *testing.T, it *PageIterator) []int {
+	values := []int{}
+	for it.Next() {
+		for _, item := range it.Items() {
+			var value int
+			if err := json.Unmarshal(item, &value); err != nil {
+				t.Fatal(err)
+			}
+			values = append(values, value)
+		}
+	}
+	if it.Err() != nil {
+		t.Fatal(it.Err())
+	}
+	return values
+}
+
+func getParams(params []interface{}) *RequestParams {
+	return &RequestParams{Method: "GET", QueryParams: map[string]string{"limit": "2"}}
+}
+
+func TestPaginateLink(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page < 2 {
+			w.Header().Set("Link", fmt.Sprintf(`<%s/items?page=%d>; rel="next", <%s/items?page=2>; rel="last"`, server.URL, page+1, server.URL))
+		}
+		fmt.Fprintf(w, "[%d, %d]", page*2, page*2+1)
+	}))
+	defer server.Close()
+
+	rd := &RequestDescriptor{BaseURL: server.URL + "/items", Params: getParams, ExpectedStatus: []int{200}, Pagination: &Pagination{Type: PaginationLink}}
+	if values := collectPages(t, rd.Paginate(context.Background(), nil)); fmt.Sprint(values) != "[0 1 2 3 4 5]" {
+		t.Errorf("unexpected items %v", values)
+	}
+}
+
+func TestPaginateCursor(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("after") {
+		case "":
+			fmt.Fprint(w, `{"data": {"children": [1, 2], "after": "t3_b"}}`)
+		case "t3_b":
+			fmt.Fprint(w, `{"data": {"children": [3], "after": null}}`)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	rd := &RequestDescriptor{BaseURL: server.URL, Params: getParams, ExpectedStatus: []int{200}, Pagination: &Pagination{
+		Type: PaginationCursor, Items: "data.children", Cursor: "data.after", Param: "after",
+	}}
+	if values := collectPages(t, rd.Paginate(context.Background(), nil)); fmt.Sprint(values) != "[1 2 3]" {
+		t.Errorf("unexpected items %v", values)
+	}
+}
+
+func TestPaginateOffset(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		items := []int{}
+		for i := offset; i < offset+3 && i < 7; i++ {
+			items = append(items, i)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"items": items})
+	}))
+	defer server.Close()
+
+	rd := &RequestDescriptor{BaseURL: server.URL, Params: getParams, ExpectedStatus: []int{200}, Pagination: &Pagination{
+		Type: PaginationOffset, Items: "items", Param: "offset", LimitParam: "limit", Limit: 3,
+	}}
+	if values := collectPages(t, rd.Paginate(context.Background(), nil)); fmt.Sprint(values) != "[0 1 2 3 4 5 6]" {
+		t.Errorf("unexpected items %v", values)
+	}
+	if requests != 3 {
+		t.Errorf("expected 3 requests, got %d", requests)
+	}
+}
+
+func TestPaginateHasMore(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/continue" {
+			fmt.Fprint(w, `{"entries": [1], "cursor": "c1", "has_more": true}`)
+			return
+		}
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["cursor"] != "c1" || r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fmt.Fprint(w, `{"entries": [2], "cursor": "c2", "has_more": false}`)
+	}))
+	defer server.Close()
+
+	rd := &RequestDescriptor{BaseURL: server.URL + "/list", Params: getParams, ExpectedStatus: []int{200}, Pagination: &Pagination{
+		Type: PaginationHasMore, Items: "entries", Cursor: "cursor", HasMore: "has_more", ContinueURL: server.URL + "/continue",
+	}}
+	it := rd.Paginate(context.Background(), nil)
+	if values := collectPages(t, it); fmt.Sprint(values) != "[1 2]" {
+		t.Errorf("unexpected items %v", values)
+	}
+	if it.Cursor() != "c2" {
+		t.Errorf("expected the last cursor, got %q", it.Cursor())
+	}
+}
+
+func TestIsLatestByCheckpoint(t *testing.T) {
+	pages := [][]int{{5, 4}, {3, 2}, {1}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page+1 < len(pages) {
+			w.Header().Set("Link", fmt.Sprintf(`<http://%s/?page=%d>; rel="next"`, r.Host, page+1))
+		}
+		json.NewEncoder(w).Encode(pages[page])
+	}))
+	defer server.Close()
+
+	rd := &RequestDescriptor{BaseURL: server.URL, Params: getParams, ExpectedStatus: []int{200}, Pagination: &Pagination{Type: PaginationLink}}
+	id := func(item json.RawMessage) (string, error) { return string(item), nil }
+	store := map[string]interface{}{}
+
+	// The first call only saves the checkpoint
+	items, err := IsLatestByCheckpoint(&store, rd.Paginate(context.Background(), nil), id)
+	if err != nil || len(items) != 0 || store["ctx:last:checkpoint"] != "5" {
+		t.Fatalf("unexpected first call: %v %v %v", items, err, store)
+	}
+
+	pages = [][]int{{8, 7}, {6, 5}, {4}}
+	items, err = IsLatestByCheckpoint(&store, rd.Paginate(context.Background(), nil), id)
+	if err != nil || fmt.Sprint(items) != "[8 7 6]" || store["ctx:last:checkpoint"] != "8" {
+		t.Fatalf("unexpected second call: %s %v %v", items, err, store)
+	}
+}
+
+func TestIsLatestByCheckpointEmptyFirst(t *testing.T) {
+	pages := [][]int{{}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(pages[0])
+	}))
+	defer server.Close()
+
+	rd := &RequestDescriptor{BaseURL: server.URL, Params: getParams, ExpectedStatus: []int{200}, Pagination: &Pagination{Type: PaginationLink}}
+	id := func(item json.RawMessage) (string, error) { return string(item), nil }
+	store := map[string]interface{}{}
+
+	// The first call saves an empty checkpoint when there is no item yet
+	items, err := IsLatestByCheckpoint(&store, rd.Paginate(context.Background(), nil), id)
+	if checkpoint, ok := store["ctx:last:checkpoint"].(string); err != nil || len(items) != 0 || !ok || checkpoint != "" {
+		t.Fatalf("unexpected first call: %v %v %v", items, err, store)
+	}
+
+	// The first item is a new one, not the baseline
+	pages = [][]int{{1}}
+	items, err = IsLatestByCheckpoint(&store, rd.Paginate(context.Background(), nil), id)
+	if err != nil || fmt.Sprint(items) != "[1]" || store["ctx:last:checkpoint"] != "1" {
+		t.Fatalf("unexpected second call: %s %v %v", items, err, store)
+	}
+
+	items, err = IsLatestByCheckpoint(&store, rd.Paginate(context.Background(), nil), id)
+	if err != nil || len(items) != 0 {
+		t.Fatalf("unexpected third call: %s %v %v", items, err, store)
+	}
+}
+
+func TestIsLatestByCheckpointDeleted(t *testing.T) {
+	pages := [][]int{{5, 4}, {3, 2}, {1}}
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page+1 < len(pages) {
+			w.Header().Set("Link", fmt.Sprintf(`<http://%s/?page=%d>; rel="next"`, r.Host, page+1))
+		}
+		json.NewEncoder(w).Encode(pages[page])
+	}))
+	defer server.Close()
+
+	rd := &RequestDescriptor{BaseURL: server.URL, Params: getParams, ExpectedStatus: []int{200}, Pagination: &Pagination{Type: PaginationLink}}
+	id := func(item json.RawMessage) (string, error) { return string(item), nil }
+	store := map[string]interface{}{}
+
+	if _, err := IsLatestByCheckpoint(&store, rd.Paginate(context.Background(), nil), id); err != nil {
+		t.Fatal(err)
+	}
+
+	// The checkpoint (5) is deleted, the walk stops at the next item seen of the first page
+	pages = [][]int{{6, 4}, {3, 2}, {1}}
+	requests = 0
+	items, err := IsLatestByCheckpoint(&store, rd.Paginate(context.Background(), nil), id)
+	if err != nil || fmt.Sprint(items) != "[6]" || store["ctx:last:checkpoint"] != "6" || requests != 1 {
+		t.Fatalf("unexpected call: %s %v %v (%d requests)", items, err, store, requests)
+	}
+
+	// No item seen anymore: the new items are limited to the first page
+	pages = [][]int{{9, 8}, {7}}
+	items, err = IsLatestByCheckpoint(&store, rd.Paginate(context.Background(), nil), id)
+	if err != nil || fmt.Sprint(items) != "[9 8]" || store["ctx:last:checkpoint"] != "9" {
+		t.Fatalf("unexpected call: %s %v %v", items, err, store)
+	}
+}
@@ -0,0 +1,160 @@
+package utils
+
+import (
+	"area-server/config"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Length (in bytes) of the salt of the argon2id hashes
+const passwordSaltLength = 16
+
+// Returned when a stored hash can not be parsed
+var ErrInvalidPasswordHash = errors.New("Invalid password hash")
+
+// `argon2Params` are the parameters of an argon2id hash.
+type argon2Params struct {
+	time    uint32
+	memory  uint32
+	threads uint8
+	keyLen  uint32
+}
+
+// It returns the argon2id parameters of the configuration
+func configArgon2Params(cfg config.PasswordConfig) argon2Params {
+	return argon2Params{time: cfg.Argon2Time, memory: cfg.Argon2Memory, threads: cfg.Argon2Threads, keyLen: cfg.Argon2KeyLength}
+}
+
+// HashPassword hashes the password with the algorithm of the configuration, the hash is encoded with
+// its parameters (e.g. `$argon2id$v=19$m=19456,t=2,p=1$<salt>$<hash>` or a bcrypt hash).
+func HashPassword(password string) (string, error) {
+	cfg := config.CFG.Password
+	if cfg.Algorithm == "bcrypt" {
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), cfg.BcryptCost)
+		return string(hash), err
+	}
+
+	params := configArgon2Params(cfg)
+	salt := make([]byte, passwordSaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	hash := argon2.IDKey([]byte(password), salt, params.time, params.memory, params.threads, params.keyLen)
+	return fmt.Sprintf(
+		"$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, params.memory, params.time, params.threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(hash),
+	), nil
+}
+
+// VerifyPassword compares the password with the stored hash in constant time. `rehash` is true when
+// the password is correct but the hash must be upgraded (stored before the hashing was introduced, or
+// with other parameters than the configuration).
+func VerifyPassword(password string, stored string) (ok bool, rehash bool, err error) {
+	cfg := config.CFG.Password
+
+	switch {
+	case strings.HasPrefix(stored, "$argon2id$"):
+		params, salt, hash, err := decodeArgon2Hash(stored)
+		if err != nil {
+			return false, false, err
+		}
+		computed := argon2.IDKey([]byte(password), salt, params.time, params.memory, params.threads, uint32(len(hash)))
+		if subtle.ConstantTimeCompare(computed, hash) != 1 {
+			return false, false, nil
+		}
+		return true, cfg.Algorithm == "bcrypt" || params != configArgon2Params(cfg), nil
+
+	case strings.HasPrefix(stored, "$2a$") || strings.HasPrefix(stored, "$2b$") || strings.HasPrefix(stored, "$2y$"):
+		if err := bcrypt.CompareHashAndPassword([]byte(stored), []byte(password)); err != nil {
+			if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+				return false, false, nil
+			}
+			return false, false, err
+		}
+		cost, err := bcrypt.Cost([]byte(stored))
+		if err != nil {
+			return true, true, nil
+		}
+		return true, cfg.Algorithm != "bcrypt" || cost != cfg.BcryptCost, nil
+	}
+
+	// Legacy password stored as sent by the client
+	if stored == "" {
+		return false, false, nil
+	}
+	if subtle.ConstantTimeCompare([]byte(password), []byte(stored)) != 1 {
+		return false, false, nil
+	}
+	return true, true, nil
+}
+
+// A hash compared when the account does not exist, with the configuration it was computed with
+var (
+	dummyHashLock   sync.Mutex
+	dummyHash       string
+	dummyHashConfig config.PasswordConfig
+)
+
+// VerifyDummyPassword compares the password with a hash of the configuration and always returns false,
+// so a login with an unknown email takes as long as with a wrong password
+func VerifyDummyPassword(password string) bool {
+	dummyHashLock.Lock()
+	if dummyHash == "" || dummyHashConfig != config.CFG.Password {
+		hash, err := HashPassword("dummy password")
+		if err != nil {
+			dummyHashLock.Unlock()
+			return false
+		}
+		dummyHash, dummyHashConfig = hash, config.CFG.Password
+	}
+	hash := dummyHash
+	dummyHashLock.Unlock()
+
+	VerifyPassword(password, hash)
+	return false
+}
+
+// IsPasswordHash returns true if the stored password is already hashed
+func IsPasswordHash(stored string) bool {
+	return strings.HasPrefix(stored, "$argon2id$") || strings.HasPrefix(stored, "$2a$") ||
+		strings.HasPrefix(stored, "$2b$") || strings.HasPrefix(stored, "$2y$")
+}
+
+// It parses an encoded argon2id hash
+func decodeArgon2Hash(encoded string) (argon2Params, []byte, []byte, error) {
+	var params argon2Params
+	// "", "argon2id", "v=19", "m=...,t=...,p=...", salt, hash
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 {
+		return params, nil, nil, ErrInvalidPasswordHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, ErrInvalidPasswordHash
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.memory, &params.time, &params.threads); err != nil {
+		return params, nil, nil, ErrInvalidPasswordHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, ErrInvalidPasswordHash
+	}
+	hash, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(hash) == 0 {
+		return params, nil, nil, ErrInvalidPasswordHash
+	}
+	params.keyLen = uint32(len(hash))
+	return params, salt, hash, nil
+}
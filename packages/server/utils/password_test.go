@@ -0,0 +1,89 @@
+package utils
+
+import (
+	"area-server/config"
+	"strings"
+	"testing"
+)
+
+// It replaces the password configuration for the duration of the test
+func usePasswordConfig(t *testing.T, cfg config.PasswordConfig) {
+	previous := config.CFG.Password
+	config.CFG.Password = cfg
+	t.Cleanup(func() { config.CFG.Password = previous })
+}
+
+func TestPasswordArgon2id(t *testing.T) {
+	usePasswordConfig(t, config.PasswordConfig{Algorithm: "argon2id", Argon2Time: 1, Argon2Memory: 1024, Argon2Threads: 1, Argon2KeyLength: 32, BcryptCost: 4})
+
+	hash, err := HashPassword("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(hash, "$argon2id$v=19$m=1024,t=1,p=1$") || strings.Contains(hash, "correct horse") {
+		t.Fatalf("unexpected hash %q", hash)
+	}
+	if other, _ := HashPassword("correct horse"); other == hash {
+		t.Error("the hashes must be salted")
+	}
+
+	if ok, rehash, err := VerifyPassword("correct horse", hash); !ok || rehash || err != nil {
+		t.Errorf("expected the password to match, got %v %v %v", ok, rehash, err)
+	}
+	if ok, _, _ := VerifyPassword("wrong horse", hash); ok {
+		t.Error("a wrong password must not match")
+	}
+
+	// The parameters changed, the hash is upgraded on the next login
+	config.CFG.Password.Argon2Time = 2
+	if ok, rehash, _ := VerifyPassword("correct horse", hash); !ok || !rehash {
+		t.Error("expected the hash to be upgraded")
+	}
+
+	if _, _, err := VerifyPassword("correct horse", "$argon2id$v=19$broken"); err == nil {
+		t.Error("expected an error for an invalid hash")
+	}
+}
+
+func TestPasswordBcryptAndLegacy(t *testing.T) {
+	usePasswordConfig(t, config.PasswordConfig{Algorithm: "bcrypt", BcryptCost: 4})
+
+	hash, err := HashPassword("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, rehash, err := VerifyPassword("correct horse", hash); !ok || rehash || err != nil {
+		t.Errorf("expected the password to match, got %v %v %v", ok, rehash, err)
+	}
+	if ok, _, err := VerifyPassword("wrong horse", hash); ok || err != nil {
+		t.Errorf("a wrong password must not match, got %v", err)
+	}
+
+	// Stored before the hashing was introduced
+	if ok, rehash, _ := VerifyPassword("encoded-by-client", "encoded-by-client"); !ok || !rehash {
+		t.Error("expected the legacy password to match and be upgraded")
+	}
+	if ok, _, _ := VerifyPassword("", ""); ok {
+		t.Error("an empty password must never match")
+	}
+	if IsPasswordHash("encoded-by-client") || !IsPasswordHash(hash) {
+		t.Error("unexpected IsPasswordHash result")
+	}
+}
+
+func TestVerifyDummyPassword(t *testing.T) {
+	usePasswordConfig(t, config.PasswordConfig{Algorithm: "bcrypt", BcryptCost: 4})
+	if VerifyDummyPassword("dummy password") {
+		t.Error("the dummy verification must never succeed")
+	}
+	if !strings.HasPrefix(dummyHash, "$2a$04$") {
+		t.Errorf("expected a hash of the configuration, got %q", dummyHash)
+	}
+
+	// The hash follows the configuration, so the verification costs as much as a real one
+	config.CFG.Password = config.PasswordConfig{Algorithm: "argon2id", Argon2Time: 1, Argon2Memory: 1024, Argon2Threads: 1, Argon2KeyLength: 32}
+	VerifyDummyPassword("wrong horse")
+	if !strings.HasPrefix(dummyHash, "$argon2id$v=19$m=1024,t=1,p=1$") {
+		t.Errorf("expected the hash to follow the configuration, got %q", dummyHash)
+	}
+}
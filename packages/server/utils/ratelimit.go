@@ -0,0 +1,204 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Maximum number of times a throttled request is sent again
+const MaxRateLimitRetries = 3
+
+// Minimum time between two evictions of the idle buckets
+const rateLimitSweepInterval = time.Minute
+
+// `RateLimitError` is returned when a service still throttles the request after the retries.
+// @property {int} StatusCode - The status code of the last response (429).
+// @property {time.Duration} RetryAfter - The time to wait before sending a new request.
+type RateLimitError struct {
+	StatusCode int
+	RetryAfter time.Duration
+}
+
+// It returns the message of the error
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("%d - Rate limited, retry after %s", e.StatusCode, e.RetryAfter)
+}
+
+// `TokenBucket` allows `Burst` requests at once, then `Rate` requests per second. The bucket can also
+// be blocked until a date given by the service (Retry-After, X-RateLimit-Reset).
+type TokenBucket struct {
+	Rate  float64
+	Burst float64
+
+	mutex        sync.Mutex
+	tokens       float64
+	last         time.Time
+	blockedUntil time.Time
+}
+
+// It creates a full bucket
+func NewTokenBucket(rate float64, burst float64) *TokenBucket {
+	return &TokenBucket{Rate: rate, Burst: burst, tokens: burst, last: time.Now()}
+}
+
+// It takes a token, the second value is the time to wait if no token is available
+func (b *TokenBucket) take(now time.Time) (bool, time.Duration) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if now.Before(b.blockedUntil) {
+		return false, b.blockedUntil.Sub(now)
+	}
+
+	b.tokens = math.Min(b.Burst, b.tokens+now.Sub(b.last).Seconds()*b.Rate)
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - b.tokens) / b.Rate * float64(time.Second))
+}
+
+// Wait blocks until a token is available or the context is done. If no token is available before the
+// deadline of the context (e.g. the service paused the requests), a `RateLimitError` is returned at once.
+func (b *TokenBucket) Wait(ctx context.Context) error {
+	for {
+		now := time.Now()
+		ok, wait := b.take(now)
+		if ok {
+			return nil
+		}
+		if deadline, ok := ctx.Deadline(); ok && now.Add(wait).After(deadline) {
+			return &RateLimitError{StatusCode: http.StatusTooManyRequests, RetryAfter: wait}
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Block prevents the requests until the date
+func (b *TokenBucket) Block(until time.Time) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if until.After(b.blockedUntil) {
+		b.blockedUntil = until
+	}
+}
+
+// It returns true if the bucket is full again and not blocked: it is the same as a new bucket
+func (b *TokenBucket) idle(now time.Time) bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	full := b.tokens+now.Sub(b.last).Seconds()*b.Rate >= b.Burst
+	return full && !now.Before(b.blockedUntil)
+}
+
+// `RateLimiter` holds a bucket per key, the key is the host of the service and the authorization used,
+// so every trigger using the same authorization shares the same bucket.
+type RateLimiter struct {
+	Rate  float64
+	Burst float64
+
+	mutex     sync.Mutex
+	buckets   map[string]*TokenBucket
+	lastSweep time.Time
+}
+
+// Limiter is the rate limiter shared by all the requests sent to the services, the endpoints of a
+// service use its own rate (see `RequestDescriptor.Rate`)
+var Limiter = NewRateLimiter(5, 10)
+
+// It creates a rate limiter, the buckets are created with the rate and burst given
+func NewRateLimiter(rate float64, burst float64) *RateLimiter {
+	return &RateLimiter{Rate: rate, Burst: burst, buckets: make(map[string]*TokenBucket)}
+}
+
+// It returns the bucket of the key, it is created if it doesn't exist
+func (l *RateLimiter) Bucket(key string) *TokenBucket {
+	return l.bucket(key, 0, time.Now())
+}
+
+// BucketWithRate returns the bucket of the key, it is created with the rate (requests per second) if it
+// doesn't exist. The rate of the limiter is used if `rate` is 0.
+func (l *RateLimiter) BucketWithRate(key string, rate float64) *TokenBucket {
+	return l.bucket(key, rate, time.Now())
+}
+
+// It returns the bucket of the key, the idle buckets are evicted at most every `rateLimitSweepInterval`
+// so the buckets of the authorizations no longer used don't pile up
+func (l *RateLimiter) bucket(key string, rate float64, now time.Time) *TokenBucket {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if now.Sub(l.lastSweep) >= rateLimitSweepInterval {
+		for k, bucket := range l.buckets {
+			if bucket.idle(now) {
+				delete(l.buckets, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	bucket, ok := l.buckets[key]
+	if !ok {
+		if rate <= 0 {
+			rate = l.Rate
+		}
+		bucket = NewTokenBucket(rate, l.Burst)
+		bucket.last = now
+		l.buckets[key] = bucket
+	}
+	return bucket
+}
+
+// It reads the rate limit headers of the response, it returns the time to wait before the next request
+// (0 if the requests are not limited)
+func RateLimitDelay(resp *http.Response, now time.Time) time.Duration {
+	if value := resp.Header.Get("Retry-After"); value != "" {
+		if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+			return time.Duration(seconds * float64(time.Second))
+		}
+		if date, err := http.ParseTime(value); err == nil {
+			return date.Sub(now)
+		}
+	}
+
+	remaining := resp.Header.Get("X-RateLimit-Remaining")
+	if remaining == "" {
+		return 0
+	}
+	if left, err := strconv.ParseFloat(remaining, 64); err != nil || left >= 1 {
+		return 0
+	}
+
+	// Discord
+	if value := resp.Header.Get("X-RateLimit-Reset-After"); value != "" {
+		if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+			return time.Duration(seconds * float64(time.Second))
+		}
+	}
+	// Github / Twitch send a timestamp, Reddit sends a number of seconds
+	if value := resp.Header.Get("X-RateLimit-Reset"); value != "" {
+		if reset, err := strconv.ParseFloat(value, 64); err == nil {
+			if reset > 1e9 {
+				return time.Unix(int64(reset), 0).Sub(now)
+			}
+			return time.Duration(reset * float64(time.Second))
+		}
+	}
+	return time.Second
+}
@@ -0,0 +1,160 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTokenBucket(t *testing.T) {
+	bucket := NewTokenBucket(10, 2)
+	now := time.Now()
+
+	if ok, _ := bucket.take(now); !ok {
+		t.Fatalf("first token should be available")
+	}
+	if ok, _ := bucket.take(now); !ok {
+		t.Fatalf("second token should be available (burst)")
+	}
+	if ok, wait := bucket.take(now); ok || wait <= 0 {
+		t.Fatalf("bucket should be empty")
+	}
+	if ok, _ := bucket.take(now.Add(150 * time.Millisecond)); !ok {
+		t.Fatalf("bucket should be refilled")
+	}
+
+	bucket.Block(now.Add(time.Hour))
+	if ok, wait := bucket.take(now.Add(time.Second)); ok || wait < 59*time.Minute {
+		t.Fatalf("bucket should be blocked")
+	}
+}
+
+func TestRateLimiterEvictsIdleBuckets(t *testing.T) {
+	limiter := NewRateLimiter(1, 2)
+	now := time.Now()
+
+	idle := limiter.bucket("idle", 0, now)
+	idle.take(now)
+	idle.take(now)
+	blocked := limiter.bucket("blocked", 0, now)
+	blocked.Block(now.Add(time.Hour))
+	if limiter.bucket("idle", 0, now.Add(time.Second)) != idle {
+		t.Fatal("expected the same bucket before the sweep")
+	}
+
+	later := now.Add(rateLimitSweepInterval)
+	used := limiter.bucket("used", 0, later.Add(-time.Second))
+	used.take(later.Add(-time.Second))
+	used.take(later.Add(-time.Second))
+
+	// "idle" is full again, "used" is still refilling and "blocked" waits for the service
+	limiter.bucket("new", 0, later)
+	if _, ok := limiter.buckets["idle"]; ok {
+		t.Error("expected the idle bucket to be evicted")
+	}
+	if limiter.buckets["used"] != used || limiter.buckets["blocked"] != blocked {
+		t.Error("expected the buckets in use to be kept")
+	}
+	if len(limiter.buckets) != 3 {
+		t.Errorf("expected 3 buckets, got %d", len(limiter.buckets))
+	}
+}
+
+func TestRateLimitDelay(t *testing.T) {
+	now := time.Now()
+	cases := []struct {
+		headers  map[string]string
+		expected time.Duration
+	}{
+		{map[string]string{"Retry-After": "2"}, 2 * time.Second},
+		{map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset-After": "1.5"}, 1500 * time.Millisecond},
+		{map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": strconv.FormatInt(now.Add(10*time.Second).Unix(), 10)}, 10 * time.Second},
+		{map[string]string{"X-RateLimit-Remaining": "0.0", "X-RateLimit-Reset": "30"}, 30 * time.Second},
+		{map[string]string{"X-RateLimit-Remaining": "12", "X-RateLimit-Reset": "30"}, 0},
+		{map[string]string{}, 0},
+	}
+
+	for i, c := range cases {
+		resp := &http.Response{Header: http.Header{}}
+		for key, value := range c.headers {
+			resp.Header.Set(key, value)
+		}
+		delay := RateLimitDelay(resp, now)
+		if delay < c.expected-time.Second || delay > c.expected {
+			t.Errorf("case %d: expected %s, got %s", i, c.expected, delay)
+		}
+	}
+}
+
+func TestMakeRequestRetry(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "0.05")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"ok": true}`))
+	}))
+	defer server.Close()
+
+	body, _, resp, err := DoRequest(server.URL, &RequestParams{Method: "GET", RateLimitKey: "retry"}, []int{200}, true)
+	if err != nil || resp.StatusCode != 200 || body.(map[string]interface{})["ok"] != true {
+		t.Fatalf("expected the throttled request to be retried, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 calls, got %d", calls)
+	}
+}
+
+func TestMakeRequestRetryDeadline(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, _, _, err := DoRequest(server.URL, &RequestParams{Method: "GET", Context: ctx, RateLimitKey: "deadline"}, []int{200}, true)
+	if _, ok := err.(*RateLimitError); !ok {
+		t.Fatalf("expected a RateLimitError, got %v", err)
+	}
+}
+
+func TestTokenBucketWaitBlockedPastDeadline(t *testing.T) {
+	bucket := NewTokenBucket(10, 2)
+	bucket.Block(time.Now().Add(time.Hour))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	err := bucket.Wait(ctx)
+	var rateErr *RateLimitError
+	if !errors.As(err, &rateErr) {
+		t.Fatalf("expected a RateLimitError, got %v", err)
+	}
+	if rateErr.RetryAfter < 59*time.Minute {
+		t.Errorf("expected to retry after the block, got %s", rateErr.RetryAfter)
+	}
+	if time.Since(start) > time.Second {
+		t.Error("expected to return without waiting for the deadline")
+	}
+}
+
+func TestRateLimiterBucketWithRate(t *testing.T) {
+	limiter := NewRateLimiter(5, 10)
+	if bucket := limiter.BucketWithRate("github", 0.1); bucket.Rate != 0.1 || bucket.Burst != 10 {
+		t.Errorf("expected the rate of the service, got %v/%v", bucket.Rate, bucket.Burst)
+	}
+	if bucket := limiter.BucketWithRate("other", 0); bucket.Rate != 5 {
+		t.Errorf("expected the rate of the limiter, got %v", bucket.Rate)
+	}
+}
@@ -1,13 +1,17 @@
 package utils
 
 import (
+	"area-server/db/postgres/models"
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 )
 
 // `RequestParams` is a struct that contains a `Method` string, a `Body` string, a `QueryParams` map of
@@ -19,12 +23,25 @@ import (
 // @property UrlParams - These are the parameters that are part of the URL. For example, if you have a
 // URL like `/users/{userId}/posts/{postId}`, then `userId` and `postId` are the URL parameters.
 // @property Headers - This is a map of key-value pairs that will be added to the request headers.
+// @property Context - The context of the request, the request is cancelled with it (optional).
+// @property Timeout - The timeout of the request, `DefaultRequestTimeout` is used if not set and the
+// context has no deadline.
+// @property RateLimitKey - The requests with the same key (and host) share the same rate limit, the
+// UUID of the authorization is used by the endpoints of the services.
+// @property {float64} Rate - The requests per second allowed for the key (the rate of `Limiter` if 0).
+// @property {bool} Conditional - The validators (ETag, Last-Modified) of the last response are sent
+// with the request, a 304 is returned as `ErrNotModified` (only in a scope, see `WithConditionalScope`).
 type RequestParams struct {
-	Method      string
-	Body        string
-	QueryParams map[string]string
-	UrlParams   map[string]string
-	Headers     map[string]string
+	Method       string
+	Body         string
+	QueryParams  map[string]string
+	UrlParams    map[string]string
+	Headers      map[string]string
+	Context      context.Context
+	Timeout      time.Duration
+	RateLimitKey string
+	Rate         float64
+	Conditional  bool
 }
 
 type RequestDescriptor struct {
@@ -32,11 +49,53 @@ type RequestDescriptor struct {
 	Params            func(params []interface{}) *RequestParams          `json:"-"`      // Params to send to the request
 	ExpectedStatus    []int                                              `json:"status"` // Expected status code of the response
 	TransformResponse func(response any) (map[string]interface{}, error) `json:"-"`      // Transform the response
+	Timeout           time.Duration                                      `json:"-"`      // Timeout of the request (default: DefaultRequestTimeout)
+	Pagination        *Pagination                                        `json:"-"`      // Pagination of the endpoint (see `Paginate`)
+	Conditional       bool                                               `json:"-"`      // Send the validators of the last response (ETag / Last-Modified)
+	Rate              float64                                            `json:"-"`      // Requests per second allowed per authorization (default: the rate of Limiter)
+}
+
+// It builds the params of the request with the context and the timeout of the descriptor
+func (rd *RequestDescriptor) params(ctx context.Context, params []interface{}) *RequestParams {
+	p := rd.Params(params)
+	if p == nil {
+		return nil
+	}
+	if ctx != nil {
+		p.Context = ctx
+	}
+	if p.Timeout == 0 {
+		p.Timeout = rd.Timeout
+	}
+	if rd.Conditional {
+		p.Conditional = true
+	}
+	if p.Rate == 0 {
+		p.Rate = rd.Rate
+	}
+	if p.RateLimitKey == "" {
+		for _, param := range params {
+			switch auth := param.(type) {
+			case *models.Authorization:
+				if auth != nil {
+					p.RateLimitKey = auth.UUID.String()
+				}
+			case models.Authorization:
+				p.RateLimitKey = auth.UUID.String()
+			}
+		}
+	}
+	return p
 }
 
 // Calling the `DoRequest` function and returning the response.
 func (rd *RequestDescriptor) Call(params []interface{}) (map[string]interface{}, *http.Response, error) {
-	resp, _, res, err := DoRequest(rd.BaseURL, rd.Params(params), rd.ExpectedStatus, true)
+	return rd.CallContext(context.Background(), params)
+}
+
+// Same as `Call`, the request is cancelled when the context is done.
+func (rd *RequestDescriptor) CallContext(ctx context.Context, params []interface{}) (map[string]interface{}, *http.Response, error) {
+	resp, _, res, err := DoRequest(rd.BaseURL, rd.params(ctx, params), rd.ExpectedStatus, true)
 	if err != nil {
 		return nil, res, err
 	}
@@ -49,7 +108,12 @@ func (rd *RequestDescriptor) Call(params []interface{}) (map[string]interface{},
 
 // Calling the `DoRequest` function and returning the response.
 func (rd *RequestDescriptor) CallPure(params []interface{}) (any, *http.Response, error) {
-	resp, _, res, err := DoRequest(rd.BaseURL, rd.Params(params), rd.ExpectedStatus, true)
+	return rd.CallPureContext(context.Background(), params)
+}
+
+// Same as `CallPure`, the request is cancelled when the context is done.
+func (rd *RequestDescriptor) CallPureContext(ctx context.Context, params []interface{}) (any, *http.Response, error) {
+	resp, _, res, err := DoRequest(rd.BaseURL, rd.params(ctx, params), rd.ExpectedStatus, true)
 	if err != nil {
 		return resp, res, err
 	}
@@ -58,7 +122,12 @@ func (rd *RequestDescriptor) CallPure(params []interface{}) (any, *http.Response
 
 // Calling the `DoRequest` function and returning the response.
 func (rd *RequestDescriptor) CallEncode(params []interface{}) ([]byte, *http.Response, error) {
-	_, enc, res, err := DoRequest(rd.BaseURL, rd.Params(params), rd.ExpectedStatus, false)
+	return rd.CallEncodeContext(context.Background(), params)
+}
+
+// Same as `CallEncode`, the request is cancelled when the context is done.
+func (rd *RequestDescriptor) CallEncodeContext(ctx context.Context, params []interface{}) ([]byte, *http.Response, error) {
+	_, enc, res, err := DoRequest(rd.BaseURL, rd.params(ctx, params), rd.ExpectedStatus, false)
 	if err != nil {
 		return enc, res, err
 	}
@@ -68,33 +137,100 @@ func (rd *RequestDescriptor) CallEncode(params []interface{}) ([]byte, *http.Res
 // -----------------------------------UTILS--------------------------------------------
 
 // It takes a base URL, a method, a body, a map of URL parameters, a map of query parameters, and a map
-// of headers, and returns a response
+// of headers, and returns a response. The request is sent with the shared `HTTPClient`, the body of
+// the response must be closed by the caller.
 func MakeRequest(baseurl string, p *RequestParams) (*http.Response, error) {
 	if p == nil {
 		return nil, fmt.Errorf("Request params is nil")
 	}
-	client := &http.Client{}
 	for urlKey, urlParam := range p.UrlParams {
 		baseurl = strings.Replace(baseurl, "${"+urlKey+"}", urlParam, -1)
 	}
-	fmt.Println("URL :> ", baseurl)
-	req, err := http.NewRequest(p.Method, baseurl, bytes.NewBufferString(p.Body))
-	if err != nil {
-		return nil, err
+
+	ctx := p.Context
+	if ctx == nil {
+		ctx = context.Background()
 	}
+	timeout := p.Timeout
+	if _, ok := ctx.Deadline(); !ok && timeout == 0 {
+		timeout = DefaultRequestTimeout
+	}
+	var cancel context.CancelFunc = func() {}
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	}
+
 	queryParams := url.Values{}
 	for key := range p.QueryParams {
 		queryParams.Add(key, p.QueryParams[key])
 	}
-	req.URL.RawQuery = queryParams.Encode()
-	for key := range p.Headers {
-		req.Header.Add(key, p.Headers[key])
-	}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
+
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, p.Method, baseurl, bytes.NewBufferString(p.Body))
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		req.URL.RawQuery = queryParams.Encode()
+		for key := range p.Headers {
+			req.Header.Add(key, p.Headers[key])
+		}
+		conditional := conditionalKey(p, req)
+		Validators.Apply(conditional, req)
+
+		key := p.RateLimitKey
+		if key == "" {
+			key = "app"
+		}
+		bucket := Limiter.BucketWithRate(req.URL.Host+"|"+key, p.Rate)
+		if err := bucket.Wait(ctx); err != nil {
+			cancel()
+			return nil, err
+		}
+
+		start := time.Now()
+		resp, err := HTTPClient.Do(req)
+		logRequest(req, resp, time.Since(start), err)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+
+		Validators.Store(conditional, resp)
+		delay := RateLimitDelay(resp, time.Now())
+		if delay > 0 {
+			bucket.Block(time.Now().Add(delay))
+		}
+
+		// Throttled, the request is sent again if the service allows it before the deadline
+		if resp.StatusCode == http.StatusTooManyRequests && attempt < MaxRateLimitRetries {
+			if delay <= 0 {
+				delay = time.Duration(1<<attempt) * time.Second
+				bucket.Block(time.Now().Add(delay))
+			}
+			if deadline, ok := ctx.Deadline(); !ok || time.Now().Add(delay).Before(deadline) {
+				resp.Body.Close()
+				continue
+			}
+		}
+
+		// The timeout must also cover the reading of the body
+		resp.Body = &cancelOnClose{ReadCloser: limitResponseBody(resp.Body), cancel: cancel}
+		return resp, nil
 	}
-	return resp, nil
+}
+
+// `cancelOnClose` releases the context of the request when the body of the response is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// It closes the body and releases the context
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
 }
 
 // Read the body of the response and return it as a byte array.
@@ -113,7 +249,14 @@ func DoRequest(baseurl string, p *RequestParams, expectedStatus []int, decode bo
 	if err != nil {
 		return nil, nil, nil, err
 	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotModified && !containsStatus(expectedStatus, resp.StatusCode) {
+		return nil, nil, resp, ErrNotModified
+	}
 	body, err := RequestReadBody(resp)
+	if err == nil && resp.StatusCode == http.StatusTooManyRequests && !containsStatus(expectedStatus, resp.StatusCode) {
+		return nil, body, resp, &RateLimitError{StatusCode: resp.StatusCode, RetryAfter: RateLimitDelay(resp, time.Now())}
+	}
 	if err != nil {
 		return nil, nil, resp, err
 	}
@@ -136,3 +279,13 @@ func DoRequest(baseurl string, p *RequestParams, expectedStatus []int, decode bo
 	}
 	return nil, nil, resp, fmt.Errorf("%d - Unexpected status code expected one of %v", resp.StatusCode, expectedStatus)
 }
+
+// It returns true if the status is one of the expected status
+func containsStatus(expectedStatus []int, status int) bool {
+	for _, eS := range expectedStatus {
+		if eS == status {
+			return true
+		}
+	}
+	return false
+}
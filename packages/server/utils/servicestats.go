@@ -0,0 +1,130 @@
+package utils
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Duration of a bucket of the service statistics, the statistics are kept for `ServiceStatsRetention`
+const serviceStatsBucket = time.Minute
+
+// Duration the calls are kept in the statistics
+const ServiceStatsRetention = 24 * time.Hour
+
+// `serviceCounters` are the calls of a service during a bucket.
+type serviceCounters struct {
+	start       time.Time
+	calls       int
+	errors      int
+	rateLimited int
+}
+
+// `ServiceErrorRate` is the summary of the calls sent to a service during a window.
+// @property {string} Service - The name of the service.
+// @property {int} Calls - The number of calls (actions and reactions).
+// @property {int} Errors - The number of calls that failed (rate limits excluded).
+// @property {int} RateLimited - The number of calls throttled by the service.
+// @property {float64} ErrorRate - Errors / Calls (0 without calls).
+// @property {string} LastError - The last error returned by the service (even before the window).
+// @property {time.Time} LastErrorAt - The date of the last error.
+type ServiceErrorRate struct {
+	Service     string     `json:"service"`
+	Calls       int        `json:"calls"`
+	Errors      int        `json:"errors"`
+	RateLimited int        `json:"rate_limited"`
+	ErrorRate   float64    `json:"error_rate"`
+	LastError   string     `json:"last_error,omitempty"`
+	LastErrorAt *time.Time `json:"last_error_at,omitempty"`
+}
+
+// `ServiceStats` counts the calls of the areas and their errors per service, in buckets of one minute.
+type ServiceStats struct {
+	mutex       sync.Mutex
+	buckets     map[string][]*serviceCounters
+	lastError   map[string]string
+	lastErrorAt map[string]time.Time
+}
+
+// Stats are the statistics of the calls of the triggers of the server
+var Stats = NewServiceStats()
+
+// It creates empty statistics
+func NewServiceStats() *ServiceStats {
+	return &ServiceStats{
+		buckets:     make(map[string][]*serviceCounters),
+		lastError:   make(map[string]string),
+		lastErrorAt: make(map[string]time.Time),
+	}
+}
+
+// Record counts a call to the service: an error is a failure, except `ErrNotModified` (success) and a
+// `RateLimitError` (counted apart)
+func (s *ServiceStats) Record(service string, err error, now time.Time) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	start := now.Truncate(serviceStatsBucket)
+	buckets := s.buckets[service]
+	if len(buckets) == 0 || buckets[len(buckets)-1].start.Before(start) {
+		buckets = append(buckets, &serviceCounters{start: start})
+	}
+	// The buckets older than the retention are dropped
+	for len(buckets) > 0 && now.Sub(buckets[0].start) > ServiceStatsRetention {
+		buckets = buckets[1:]
+	}
+	s.buckets[service] = buckets
+
+	current := buckets[len(buckets)-1]
+	current.calls++
+
+	var rateErr *RateLimitError
+	switch {
+	case err == nil || errors.Is(err, ErrNotModified):
+	case errors.As(err, &rateErr):
+		current.rateLimited++
+	default:
+		current.errors++
+		s.lastError[service] = err.Error()
+		s.lastErrorAt[service] = now
+	}
+}
+
+// Summary returns the error rate of every service called since `now - window`, the services with the
+// highest error rate first
+func (s *ServiceStats) Summary(window time.Duration, now time.Time) []ServiceErrorRate {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	since := now.Add(-window).Truncate(serviceStatsBucket)
+	summary := make([]ServiceErrorRate, 0, len(s.buckets))
+	for service, buckets := range s.buckets {
+		rate := ServiceErrorRate{Service: service}
+		for _, bucket := range buckets {
+			if bucket.start.Before(since) {
+				continue
+			}
+			rate.Calls += bucket.calls
+			rate.Errors += bucket.errors
+			rate.RateLimited += bucket.rateLimited
+		}
+		if rate.Calls == 0 {
+			continue
+		}
+		rate.ErrorRate = float64(rate.Errors) / float64(rate.Calls)
+		if at, ok := s.lastErrorAt[service]; ok {
+			rate.LastError = s.lastError[service]
+			rate.LastErrorAt = &at
+		}
+		summary = append(summary, rate)
+	}
+
+	sort.Slice(summary, func(i, j int) bool {
+		if summary[i].ErrorRate != summary[j].ErrorRate {
+			return summary[i].ErrorRate > summary[j].ErrorRate
+		}
+		return summary[i].Service < summary[j].Service
+	})
+	return summary
+}
@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestServiceStatsSummary(t *testing.T) {
+	stats := NewServiceStats()
+	now := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	stats.Record("github", nil, now.Add(-2*time.Hour)) // Outside of the window
+	stats.Record("github", errors.New("old"), now.Add(-2*time.Hour))
+	stats.Record("github", nil, now.Add(-30*time.Minute))
+	stats.Record("github", ErrNotModified, now.Add(-20*time.Minute))
+	stats.Record("github", &RateLimitError{StatusCode: 429}, now.Add(-10*time.Minute))
+	stats.Record("github", errors.New("bad gateway"), now.Add(-5*time.Minute))
+	stats.Record("discord", nil, now)
+
+	summary := stats.Summary(time.Hour, now)
+	if len(summary) != 2 {
+		t.Fatalf("expected 2 services, got %v", summary)
+	}
+
+	github := summary[0]
+	if github.Service != "github" || github.Calls != 4 || github.Errors != 1 || github.RateLimited != 1 {
+		t.Errorf("unexpected summary %+v", github)
+	}
+	if github.ErrorRate != 0.25 || github.LastError != "bad gateway" || github.LastErrorAt == nil {
+		t.Errorf("unexpected error rate %+v", github)
+	}
+	if discord := summary[1]; discord.Service != "discord" || discord.ErrorRate != 0 || discord.LastErrorAt != nil {
+		t.Errorf("unexpected summary %+v", discord)
+	}
+}
+
+func TestServiceStatsRetention(t *testing.T) {
+	stats := NewServiceStats()
+	now := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	stats.Record("github", errors.New("old"), now.Add(-ServiceStatsRetention-time.Hour))
+	stats.Record("github", nil, now)
+	if buckets := len(stats.buckets["github"]); buckets != 1 {
+		t.Errorf("expected the old bucket to be dropped, got %d buckets", buckets)
+	}
+	if summary := stats.Summary(2*ServiceStatsRetention, now); len(summary) != 1 || summary[0].Calls != 1 {
+		t.Errorf("unexpected summary %v", summary)
+	}
+}
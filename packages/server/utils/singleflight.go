@@ -0,0 +1,46 @@
+package utils
+
+import "sync"
+
+// `flightCall` is a call in progress (or completed) of a `SingleFlight`.
+type flightCall struct {
+	wg    sync.WaitGroup
+	value interface{}
+	err   error
+}
+
+// `SingleFlight` deduplicates the concurrent calls sharing the same key, the callers arriving while a
+// call is in progress wait for it and receive its result.
+type SingleFlight struct {
+	mutex sync.Mutex
+	calls map[string]*flightCall
+}
+
+// It creates an empty group of calls
+func NewSingleFlight() *SingleFlight {
+	return &SingleFlight{calls: make(map[string]*flightCall)}
+}
+
+// Do calls fn once for all the concurrent callers of the key, `shared` is true when the result was
+// produced by the call of another caller
+func (g *SingleFlight) Do(key string, fn func() (interface{}, error)) (value interface{}, err error, shared bool) {
+	g.mutex.Lock()
+	if call, ok := g.calls[key]; ok {
+		g.mutex.Unlock()
+		call.wg.Wait()
+		return call.value, call.err, true
+	}
+	call := new(flightCall)
+	call.wg.Add(1)
+	g.calls[key] = call
+	g.mutex.Unlock()
+
+	defer func() {
+		g.mutex.Lock()
+		delete(g.calls, key)
+		g.mutex.Unlock()
+		call.wg.Done()
+	}()
+	call.value, call.err = fn()
+	return call.value, call.err, false
+}
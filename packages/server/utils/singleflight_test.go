@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestSingleFlightDeduplicates(t *testing.T) {
+	group := NewSingleFlight()
+	release := make(chan struct{})
+	started := make(chan struct{})
+	var calls int32
+
+	var wg sync.WaitGroup
+	results := make([]interface{}, 5)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _, _ = group.Do("authorization", func() (interface{}, error) {
+				if atomic.AddInt32(&calls, 1) == 1 {
+					close(started)
+				}
+				<-release
+				return "token", nil
+			})
+		}(i)
+	}
+	<-started
+	close(release)
+	wg.Wait()
+
+	if calls < 1 || calls > int32(len(results)) {
+		t.Fatalf("unexpected number of calls %d", calls)
+	}
+	for _, result := range results {
+		if result != "token" {
+			t.Errorf("expected every caller to get the token, got %v", result)
+		}
+	}
+
+	// The key is released, the next call runs again
+	if _, err, shared := group.Do("authorization", func() (interface{}, error) { return nil, errors.New("revoked") }); err == nil || shared {
+		t.Errorf("expected a new call, got %v %v", err, shared)
+	}
+}
@@ -0,0 +1,111 @@
+package utils
+
+import (
+	"area-server/db/postgres/models"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Directories of the files written for the accounts (relative to the working directory of the server)
+const (
+	LogsDir    = "logs"
+	AvatarsDir = "avatars"
+)
+
+// Delay before a failed teardown step is tried again, doubled on each attempt up to the maximum
+const (
+	teardownRetryDelay    = 30 * time.Second
+	teardownMaxRetryDelay = time.Hour
+)
+
+// AccountFiles returns the files of an account: the logs of its applets and its avatar
+func AccountFiles(accountID string, applets []string) []string {
+	files := make([]string, 0, len(applets)+1)
+	for _, applet := range applets {
+		files = append(files, filepath.Join(LogsDir, applet+".log"))
+	}
+	return append(files, filepath.Join(AvatarsDir, accountID))
+}
+
+// RemoveFiles deletes the files, or moves them in the `archive` directory if it is not empty. The
+// missing files are ignored, so it can be called again after a failure
+func RemoveFiles(files []string, archive string) error {
+	if archive != "" {
+		if err := os.MkdirAll(archive, 0755); err != nil {
+			return err
+		}
+	}
+
+	for _, file := range files {
+		var err error
+		if archive != "" {
+			err = os.Rename(file, filepath.Join(archive, filepath.Base(file)))
+		} else {
+			err = os.Remove(file)
+		}
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// TeardownRetryDelay returns the time to wait before trying again a teardown step that failed
+// `attempts` times
+func TeardownRetryDelay(attempts int) time.Duration {
+	delay := teardownRetryDelay
+	for i := 1; i < attempts && delay < teardownMaxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > teardownMaxRetryDelay {
+		return teardownMaxRetryDelay
+	}
+	return delay
+}
+
+// `TeardownStep` is a step of the deletion of an account.
+// @property {string} Name - the name saved in `AccountTeardown.Step` (`models.Teardown*`)
+// @property {func} Run - it does the step, it can be called again after a failure
+type TeardownStep struct {
+	Name string
+	Run  func(*models.AccountTeardown) error
+}
+
+// RunTeardownSteps runs the steps from the one saved in the teardown, the progress is saved with
+// `save` after each step. When a step fails, its attempts, error and next run are saved and the error
+// is returned. It returns true when all the steps are done.
+func RunTeardownSteps(teardown *models.AccountTeardown, steps []TeardownStep, save func(*models.AccountTeardown) error, now func() time.Time) (bool, error) {
+	started := false
+	for i, step := range steps {
+		if !started && step.Name != teardown.Step {
+			continue
+		}
+		started = true
+
+		if err := step.Run(teardown); err != nil {
+			teardown.Attempts++
+			teardown.LastError = step.Name + ": " + err.Error()
+			teardown.NextRunAt = now().Add(TeardownRetryDelay(teardown.Attempts))
+			if saveErr := save(teardown); saveErr != nil {
+				return false, fmt.Errorf("%s (not saved: %s)", teardown.LastError, saveErr.Error())
+			}
+			return false, errors.New(teardown.LastError)
+		}
+
+		if i+1 < len(steps) {
+			teardown.Step = steps[i+1].Name
+			teardown.Attempts = 0
+			teardown.LastError = ""
+			if err := save(teardown); err != nil {
+				return false, err
+			}
+		}
+	}
+	if !started {
+		return false, fmt.Errorf("unknown step %q", teardown.Step)
+	}
+	return true, nil
+}
@@ -0,0 +1,155 @@
+package utils
+
+import (
+	"area-server/db/postgres/models"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAccountFiles(t *testing.T) {
+	files := AccountFiles("account", []string{"a", "b"})
+	expected := []string{filepath.Join(LogsDir, "a.log"), filepath.Join(LogsDir, "b.log"), filepath.Join(AvatarsDir, "account")}
+	if len(files) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, files)
+	}
+	for i := range expected {
+		if files[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected, files)
+		}
+	}
+}
+
+func TestRemoveFiles(t *testing.T) {
+	dir := t.TempDir()
+	present := filepath.Join(dir, "present.log")
+	if err := os.WriteFile(present, []byte("log"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	files := []string{present, filepath.Join(dir, "missing.log")}
+	if err := RemoveFiles(files, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(present); !os.IsNotExist(err) {
+		t.Errorf("expected %s to be deleted", present)
+	}
+
+	// Called again after a partial failure
+	if err := RemoveFiles(files, ""); err != nil {
+		t.Errorf("unexpected error on the second call: %v", err)
+	}
+}
+
+func TestRemoveFilesArchive(t *testing.T) {
+	dir := t.TempDir()
+	present := filepath.Join(dir, "present.log")
+	if err := os.WriteFile(present, []byte("log"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	archive := filepath.Join(dir, "archive", "account")
+	if err := RemoveFiles([]string{present, filepath.Join(dir, "missing.log")}, archive); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(present); !os.IsNotExist(err) {
+		t.Errorf("expected %s to be moved", present)
+	}
+	if data, err := os.ReadFile(filepath.Join(archive, "present.log")); err != nil || string(data) != "log" {
+		t.Errorf("expected the file in the archive, got %q (%v)", data, err)
+	}
+}
+
+func TestTeardownRetryDelay(t *testing.T) {
+	cases := map[int]time.Duration{
+		0:  30 * time.Second,
+		1:  30 * time.Second,
+		2:  time.Minute,
+		3:  2 * time.Minute,
+		20: time.Hour,
+	}
+	for attempts, expected := range cases {
+		if delay := TeardownRetryDelay(attempts); delay != expected {
+			t.Errorf("attempts %d: expected %s, got %s", attempts, expected, delay)
+		}
+	}
+}
+
+// It returns steps recording their calls, the step named `failing` fails
+func recordedSteps(calls *[]string, failing string) []TeardownStep {
+	names := []string{"triggers", "tokens", "account", "files"}
+	steps := make([]TeardownStep, 0, len(names))
+	for _, name := range names {
+		name := name
+		steps = append(steps, TeardownStep{Name: name, Run: func(*models.AccountTeardown) error {
+			*calls = append(*calls, name)
+			if name == failing {
+				return errors.New("unavailable")
+			}
+			return nil
+		}})
+	}
+	return steps
+}
+
+func TestRunTeardownStepsResumes(t *testing.T) {
+	var calls []string
+	saved := 0
+	teardown := models.AccountTeardown{Step: "account", Attempts: 2, LastError: "account: unavailable"}
+	save := func(*models.AccountTeardown) error { saved++; return nil }
+
+	done, err := RunTeardownSteps(&teardown, recordedSteps(&calls, ""), save, time.Now)
+	if !done || err != nil {
+		t.Fatalf("expected the teardown to be done, got %v %v", done, err)
+	}
+	if len(calls) != 2 || calls[0] != "account" || calls[1] != "files" {
+		t.Errorf("expected the steps from account, got %v", calls)
+	}
+	if teardown.Step != "files" || teardown.Attempts != 0 || teardown.LastError != "" || saved != 1 {
+		t.Errorf("unexpected progress: %+v (saved %d times)", teardown, saved)
+	}
+}
+
+func TestRunTeardownStepsFailure(t *testing.T) {
+	var calls []string
+	var saves []models.AccountTeardown
+	now := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	teardown := models.AccountTeardown{Step: "triggers"}
+	save := func(teardown *models.AccountTeardown) error { saves = append(saves, *teardown); return nil }
+	steps := recordedSteps(&calls, "tokens")
+
+	done, err := RunTeardownSteps(&teardown, steps, save, func() time.Time { return now })
+	if done || err == nil {
+		t.Fatalf("expected the teardown to fail, got %v %v", done, err)
+	}
+	if len(calls) != 2 || calls[1] != "tokens" {
+		t.Errorf("expected to stop at tokens, got %v", calls)
+	}
+	last := saves[len(saves)-1]
+	if last.Step != "tokens" || last.Attempts != 1 || last.LastError != "tokens: unavailable" || !last.NextRunAt.Equal(now.Add(TeardownRetryDelay(1))) {
+		t.Errorf("unexpected saved teardown: %+v", last)
+	}
+
+	// The next run starts again from the failed step and keeps counting the attempts
+	calls = nil
+	if done, _ := RunTeardownSteps(&teardown, steps, save, func() time.Time { return now }); done {
+		t.Fatal("expected the teardown to fail again")
+	}
+	if len(calls) != 1 || calls[0] != "tokens" {
+		t.Errorf("expected to resume at tokens, got %v", calls)
+	}
+	if teardown.Attempts != 2 || !teardown.NextRunAt.Equal(now.Add(TeardownRetryDelay(2))) {
+		t.Errorf("expected a second attempt, got %+v", teardown)
+	}
+}
+
+func TestRunTeardownStepsUnknownStep(t *testing.T) {
+	var calls []string
+	teardown := models.AccountTeardown{Step: "unknown"}
+	done, err := RunTeardownSteps(&teardown, recordedSteps(&calls, ""), func(*models.AccountTeardown) error { return nil }, time.Now)
+	if done || err == nil || len(calls) != 0 {
+		t.Errorf("expected an error without running a step, got %v %v %v", done, err, calls)
+	}
+}
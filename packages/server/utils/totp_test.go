@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTOTPCode(t *testing.T) {
+	// RFC 6238 test vectors (SHA1), truncated to 6 digits
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+	for _, vector := range []struct {
+		time int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	} {
+		code, err := TOTPCode(secret, TOTPStep(time.Unix(vector.time, 0)))
+		if err != nil || code != vector.code {
+			t.Errorf("at %d: expected %s, got %s (%v)", vector.time, vector.code, code, err)
+		}
+	}
+}
+
+func TestVerifyTOTP(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Unix(1700000000, 0)
+	code, _ := TOTPCode(secret, TOTPStep(now))
+
+	step, ok := VerifyTOTP(secret, code, now, 0)
+	if !ok || step != TOTPStep(now) {
+		t.Fatalf("expected the code to be valid")
+	}
+	if _, ok := VerifyTOTP(secret, code, now, step); ok {
+		t.Error("expected the code to be refused once used")
+	}
+	if _, ok := VerifyTOTP(secret, code, now.Add(TOTPPeriod*time.Second), 0); !ok {
+		t.Error("expected the previous code to be accepted (clock drift)")
+	}
+	if _, ok := VerifyTOTP(secret, code, now.Add(3*TOTPPeriod*time.Second), 0); ok {
+		t.Error("expected an old code to be refused")
+	}
+	if _, ok := VerifyTOTP(secret, "12345", now, 0); ok {
+		t.Error("expected a short code to be refused")
+	}
+
+	uri := TOTPProvisioningURI("Area", "user@example.com", secret)
+	if !strings.HasPrefix(uri, "otpauth://totp/Area:user@example.com?") || !strings.Contains(uri, "secret="+secret) {
+		t.Errorf("unexpected provisioning URI %s", uri)
+	}
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, hashes, err := GenerateRecoveryCodes()
+	if err != nil || len(codes) != RecoveryCodeCount || len(hashes) != RecoveryCodeCount {
+		t.Fatalf("expected %d codes, got %v %v", RecoveryCodeCount, codes, err)
+	}
+
+	remaining, ok := UseRecoveryCode(hashes, strings.ToUpper(codes[3]))
+	if !ok || len(remaining) != RecoveryCodeCount-1 {
+		t.Fatalf("expected the code to be used")
+	}
+	if _, ok := UseRecoveryCode(remaining, codes[3]); ok {
+		t.Error("expected a used code to be refused")
+	}
+	if len(hashes) != RecoveryCodeCount {
+		t.Error("expected the original hashes to be unchanged")
+	}
+}
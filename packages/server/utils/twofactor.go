@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"area-server/db/postgres"
+	"area-server/db/postgres/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Issuer displayed by the authenticator apps
+const TOTPIssuer = "Area"
+
+// FindTwoFactor returns the two-factor authentication of the account (gorm.ErrRecordNotFound if the
+// account never enrolled)
+func FindTwoFactor(accountUUID uuid.UUID) (*models.TwoFactor, error) {
+	var twoFactor models.TwoFactor
+	if result := postgres.DB.Where(&models.TwoFactor{AccountUUID: accountUUID}).First(&twoFactor); result.Error != nil {
+		return nil, result.Error
+	}
+	return &twoFactor, nil
+}
+
+// VerifySecondFactor checks a TOTP code or a recovery code of the account and consumes it. The row is
+// locked during the check, so the same code can't be accepted by two concurrent requests.
+func VerifySecondFactor(accountUUID uuid.UUID, code string) (bool, error) {
+	valid := false
+	err := postgres.DB.Transaction(func(tx *gorm.DB) error {
+		var twoFactor models.TwoFactor
+		if result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where(&models.TwoFactor{AccountUUID: accountUUID}).First(&twoFactor); result.Error != nil {
+			return result.Error
+		}
+
+		if step, ok := VerifyTOTP(twoFactor.Secret, code, time.Now(), twoFactor.LastStep); ok {
+			valid = true
+			return tx.Model(&twoFactor).Update("last_step", step).Error
+		}
+
+		if !twoFactor.Enabled {
+			return nil
+		}
+		if remaining, ok := UseRecoveryCode(twoFactor.RecoveryCodes, code); ok {
+			valid = true
+			twoFactor.RecoveryCodes = remaining
+			return tx.Model(&twoFactor).Select("recovery_codes").Updates(&twoFactor).Error
+		}
+		return nil
+	})
+	return valid, err
+}
@@ -8,6 +8,7 @@ import { IService } from '../../interfaces'
 import {
     useGetServiceAuthorizationsQuery,
     usePostAuthorizationMutation,
+    useStartAuthorizationMutation,
 } from '../../redux/api'
 import { getAvatar } from '../../utils/more'
 
@@ -35,6 +36,7 @@ const ChooseService = ({
     const [tmpService, setTmpService] = useState<IService | null>(null)
     const [useAuthorize, { error: perror, isLoading: pisLoading, isSuccess }] =
         usePostAuthorizationMutation()
+    const [startAuthorization] = useStartAuthorizationMutation()
     const { data, isLoading, error } = useGetServiceAuthorizationsQuery(null, {
         refetchOnMountOrArgChange: true,
         refetchOnReconnect: true,
@@ -71,7 +73,7 @@ const ChooseService = ({
         }
     }, [isSuccess, pisLoading, error, perror])
 
-    const handleServiceClick = (service: IService) => {
+    const handleServiceClick = async (service: IService) => {
         const redirectUri = 'http://localhost:8081'
 
         if (data && data[service.name]) {
@@ -87,22 +89,47 @@ const ChooseService = ({
             return
         }
 
+        // Opened before the request, so the popup is not blocked
+        popup.current = window.open(
+            '',
+            'Login with ' + service.name,
+            'width=800,height=600'
+        )
+
+        let flow: { authorization_uri: string; state: string }
+        try {
+            flow = await startAuthorization({
+                authenticator: service.authenticator?.name,
+                redirect_uri: redirectUri,
+            }).unwrap()
+        } catch (err: any) {
+            popup.current?.close()
+            toast.error(JSON.stringify(err?.data || err), {
+                position: toast.POSITION.TOP_CENTER,
+            })
+            return
+        }
+
         setTmpService(service)
-        window.handleAuthorization = (data: any) =>
+        window.handleAuthorization = (data: any) => {
+            // The callback must come from the flow started above
+            if (!data.state || data.state !== flow.state) {
+                toast.error('Invalid authorization state, please try again', {
+                    position: toast.POSITION.TOP_CENTER,
+                })
+                return
+            }
             useAuthorize({
                 ...{
                     authenticator: service.authenticator?.name,
                     code: data.code,
                     redirect_uri: redirectUri,
+                    state: data.state,
                 },
             })
+        }
 
-        popup.current = window.open(
-            service.authenticator?.authorization_uri +
-                `&redirect_uri=${redirectUri}`,
-            'Login with ' + service.name,
-            'width=800,height=600'
-        )
+        if (popup.current) popup.current.location.href = flow.authorization_uri
     }
 
     if (isLoading) return <div>Loading...</div>
@@ -6,7 +6,10 @@ import Modal from '../Modal'
 
 import { MdLoop } from 'react-icons/md'
 import { IAuthenticator } from '../../interfaces'
-import { useAuthAccountMutation } from '../../redux/api'
+import {
+    useAuthAccountMutation,
+    useStartExternalAuthMutation,
+} from '../../redux/api'
 import { getAvatarM } from '../../utils/more'
 import AnimatedButtonHL from '../animated/buttons/AnimatedButtonHL'
 import AnimatedButtonWB from '../animated/buttons/AnimatedButtonWB'
@@ -143,6 +146,66 @@ const BasicAuthentication = ({ useAuth, mode, setMode }: CommonProps) => {
     )
 }
 
+/* Defining the props of the SecondFactorAuthentication component. */
+interface SecondFactorProps {
+    useAuth: any
+    challenge: string
+    cancel: () => void
+}
+
+/* A React component that asks the code of the authenticator app (or a recovery code) of an account with two-factor authentication. */
+const SecondFactorAuthentication = ({
+    useAuth,
+    challenge,
+    cancel,
+}: SecondFactorProps) => {
+    const onSubmit = (e: React.FormEvent<HTMLFormElement>) => {
+        e.preventDefault()
+
+        const data: any = Object.fromEntries([
+            ...new FormData(e.currentTarget).entries(),
+        ])
+
+        useAuth({
+            mode: 'login/2fa',
+            ...{
+                challenge,
+                code: data.code.trim(),
+            },
+        })
+    }
+
+    return (
+        <>
+            <FormContainer onSubmit={onSubmit}>
+                <FormTitle>AreaAuth - Two-factor authentication</FormTitle>
+                <FormMiddle>
+                    <FormInput
+                        name="code"
+                        placeholder="Code or recovery code"
+                        autoComplete="one-time-code"
+                        autoFocus
+                    />
+                </FormMiddle>
+                <FormBottom>
+                    <AnimatedButtonWB color="#000" bgColor="#fff" type="submit">
+                        Verify
+                    </AnimatedButtonWB>
+                </FormBottom>
+            </FormContainer>
+            <ButtonContainer>
+                <AnimatedButtonHL
+                    color="#fff"
+                    hlColor="#139A43"
+                    onClick={cancel}
+                >
+                    Back to login
+                </AnimatedButtonHL>
+            </ButtonContainer>
+        </>
+    )
+}
+
 const Container = styled(motion.div)`
     display: flex;
     flex-direction: column;
@@ -203,6 +266,8 @@ const ServiceAuthentication = ({
     authenticators,
     loading,
 }: ServiceAuthenticationProps) => {
+    const [startExternalAuth] = useStartExternalAuthMutation()
+
     const onClick = async (
         e: React.MouseEvent<HTMLButtonElement, MouseEvent>,
         authenticator: IAuthenticator
@@ -211,23 +276,47 @@ const ServiceAuthentication = ({
         e.stopPropagation()
         const redirectUri = 'http://localhost:8081'
 
+        // Opened before the request, so the popup is not blocked
+        const popup = window.open(
+            '',
+            'Login with ' + authenticator.name,
+            'width=800,height=600'
+        )
+
+        let flow: { authorization_uri: string; state: string }
+        try {
+            flow = await startExternalAuth({
+                authenticator: authenticator.name,
+                redirect_uri: redirectUri,
+            }).unwrap()
+        } catch (err: any) {
+            popup?.close()
+            toast.error(JSON.stringify(err?.data || err), {
+                position: toast.POSITION.TOP_CENTER,
+            })
+            return
+        }
+
         window.handleAuthorization = (data: any) => {
+            // The callback must come from the flow started above
+            if (!data.state || data.state !== flow.state) {
+                toast.error('Invalid authorization state, please try again', {
+                    position: toast.POSITION.TOP_CENTER,
+                })
+                return
+            }
             useAuth({
                 mode: 'external',
                 ...{
                     authenticator: authenticator.name,
                     code: data.code,
                     redirect_uri: redirectUri,
+                    state: data.state,
                 },
             })
         }
 
-        window.open(
-            authenticator.authorization_uri +
-                '&redirect_uri=http://localhost:8081',
-            'Login with ' + authenticator.name,
-            'width=800,height=600'
-        )
+        if (popup) popup.location.href = flow.authorization_uri
     }
 
     return (
@@ -319,11 +408,12 @@ const AuthModal = ({
     const [mode, setMode] = useState<'login' | 'register' | 'external'>(
         'external'
     )
+    const [challenge, setChallenge] = useState<string | null>(null)
     const [useAuth, { data, isLoading, error, isSuccess }] =
         useAuthAccountMutation()
 
     useEffect(() => {
-        if (isLoading || isSuccess) {
+        if (isLoading || (isSuccess && data?.token)) {
             toast.promise(
                 new Promise((res, rej) => {
                     if (isSuccess) return res(0)
@@ -342,16 +432,30 @@ const AuthModal = ({
             toast.error(JSON.stringify(error.data), {
                 position: toast.POSITION.TOP_CENTER,
             })
+            // Too many invalid codes: the login must be started again
+            if (error.status === 429) setChallenge(null)
         }
-        if (isSuccess) {
-            updateToken(data)
+        if (isSuccess && data?.challenge) {
+            setChallenge(data.challenge)
+        } else if (isSuccess) {
+            setChallenge(null)
+            updateToken(data?.token)
             close()
         }
-    }, [isSuccess, error, isLoading])
+    }, [isSuccess, error, isLoading, data])
 
     return (
         <Modal open={open} close={close}>
-            {mode == 'external' ? (
+            {challenge ? (
+                <SecondFactorAuthentication
+                    useAuth={useAuth}
+                    challenge={challenge}
+                    cancel={() => {
+                        setChallenge(null)
+                        setMode('login')
+                    }}
+                />
+            ) : mode == 'external' ? (
                 <ServiceAuthentication
                     useAuth={useAuth}
                     setMode={setMode}
@@ -1,4 +1,11 @@
-import { createApi, fetchBaseQuery } from '@reduxjs/toolkit/query/react'
+import {
+    BaseQueryApi,
+    BaseQueryFn,
+    FetchArgs,
+    FetchBaseQueryError,
+    createApi,
+    fetchBaseQuery,
+} from '@reduxjs/toolkit/query/react'
 import {
     IApplet,
     IArea,
@@ -10,13 +17,85 @@ import {
 } from '../interfaces'
 
 export const baseUrl = window.location.origin + '/api'
+
+const rawBaseQuery = fetchBaseQuery({
+    baseUrl,
+    mode: 'cors',
+})
+
+/* The refresh in progress, shared by the requests refused at the same time (a refresh token can only be used once). */
+let refreshing: Promise<boolean> | null = null
+
+/**
+ * It exchanges the refresh token for a new access token (and the next refresh token)
+ * @returns true if the tokens were renewed
+ */
+const refreshTokens = async (
+    api: BaseQueryApi,
+    extraOptions: {}
+): Promise<boolean> => {
+    const refreshToken = localStorage.getItem('refresh_token')
+    if (!refreshToken) return false
+
+    const result = await rawBaseQuery(
+        {
+            url: '/auth/refresh',
+            method: 'POST',
+            body: { refresh_token: refreshToken },
+        },
+        api,
+        extraOptions
+    )
+    const data = (
+        result.data as IResponse<{ token: string; refresh_token: string }>
+    )?.data
+    if (result.error || !data?.token) {
+        localStorage.removeItem('refresh_token')
+        return false
+    }
+    localStorage.setItem('token', data.token)
+    localStorage.setItem('refresh_token', data.refresh_token)
+    return true
+}
+
+/* The access tokens are short-lived: a request refused with 401 is sent again once the tokens are renewed. */
+const baseQuery: BaseQueryFn<
+    string | FetchArgs,
+    unknown,
+    FetchBaseQueryError
+> = async (args, api, extraOptions) => {
+    let result = await rawBaseQuery(args, api, extraOptions)
+    if (
+        result.error?.status !== 401 ||
+        typeof args === 'string' ||
+        !(args.headers as Record<string, string> | undefined)?.Authorization
+    )
+        return result
+
+    if (!refreshing)
+        refreshing = refreshTokens(api, extraOptions).finally(() => {
+            refreshing = null
+        })
+    if (await refreshing) {
+        result = await rawBaseQuery(
+            {
+                ...args,
+                headers: {
+                    ...(args.headers as Record<string, string>),
+                    Authorization: `Bearer ${localStorage.getItem('token')}`,
+                },
+            },
+            api,
+            extraOptions
+        )
+    }
+    return result
+}
+
 /* The above code is creating a serviceApi object that is being used to make requests to the server. */
 export const serviceApi = createApi({
     reducerPath: 'AreaAPI',
-    baseQuery: fetchBaseQuery({
-        baseUrl,
-        mode: 'cors',
-    }),
+    baseQuery,
     endpoints: (builder) => ({
         getAbout: builder.query({
             query: () => ({
@@ -50,8 +129,64 @@ export const serviceApi = createApi({
                 validateStatus: (status) =>
                     status.status === 201 || status.status === 200,
             }),
-            transformResponse: (response: IResponse<{ token: string }>) =>
-                response.data.token,
+            /* With two-factor authentication, the login returns a challenge to send with the code (`/auth/login/2fa`) instead of the token. */
+            transformResponse: (
+                response: IResponse<{
+                    token?: string
+                    refresh_token?: string
+                    two_factor?: boolean
+                    challenge?: string
+                }>
+            ): { token?: string; challenge?: string } => {
+                if (response.data.two_factor)
+                    return { challenge: response.data.challenge }
+                if (response.data.refresh_token)
+                    localStorage.setItem(
+                        'refresh_token',
+                        response.data.refresh_token
+                    )
+                return { token: response.data.token }
+            },
+            transformErrorResponse: (error, _meta, { mode }) => {
+                // An invalid or expired code is shown in the second factor form
+                if (error.status === 401 && mode !== 'login/2fa') {
+                    localStorage.removeItem('token')
+                    window.location.assign('/')
+                }
+                return error
+            },
+        }),
+        /* Starting an external authentication: returns the URI of the provider and the state to send back. */
+        startExternalAuth: builder.mutation({
+            query: (body) => ({
+                url: '/auth/external/state',
+                method: 'POST',
+                body,
+            }),
+            transformResponse: (
+                response: IResponse<{
+                    authorization_uri: string
+                    state: string
+                }>
+            ) => response.data,
+        }),
+        // Authorization
+        /* Starting the authorization of a service: returns the URI of the provider and the state to send back. */
+        startAuthorization: builder.mutation({
+            query: (body) => ({
+                url: '/authorization/state',
+                method: 'POST',
+                headers: {
+                    Authorization: `Bearer ${localStorage.getItem('token')}`,
+                },
+                body,
+            }),
+            transformResponse: (
+                response: IResponse<{
+                    authorization_uri: string
+                    state: string
+                }>
+            ) => response.data,
             transformErrorResponse: (error) => {
                 if (error.status === 401) {
                     localStorage.removeItem('token')
@@ -60,7 +195,6 @@ export const serviceApi = createApi({
                 return error
             },
         }),
-        // Authorization
         getAuthorizations: builder.query({
             query: () => ({
                 url: '/authorization',
@@ -164,8 +298,10 @@ export const serviceApi = createApi({
                 },
                 method: 'POST',
             }),
-            transformResponse: (response: IResponse<{ message: string }>) =>
-                response.data.message,
+            transformResponse: (response: IResponse<{ message: string }>) => {
+                localStorage.removeItem('refresh_token')
+                return response.data.message
+            },
             transformErrorResponse: (error) => {
                 if (error.status === 401) {
                     localStorage.removeItem('token')
@@ -203,8 +339,10 @@ export const serviceApi = createApi({
                 },
                 method: 'DELETE',
             }),
-            transformResponse: (response: IResponse<{ message: string }>) =>
-                response.data.message,
+            transformResponse: (response: IResponse<{ message: string }>) => {
+                localStorage.removeItem('refresh_token')
+                return response.data.message
+            },
             transformErrorResponse: (error) => {
                 if (error.status === 401) {
                     localStorage.removeItem('token')
@@ -557,6 +695,8 @@ export const serviceApi = createApi({
 export const {
     useGetAboutQuery,
     useAuthAccountMutation,
+    useStartExternalAuthMutation,
+    useStartAuthorizationMutation,
     useLogoutAccountMutation,
     useGetAuthorizationsQuery,
     useGetServiceAuthorizationsQuery,